@@ -0,0 +1,574 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.32.0
+// 	protoc        (unknown)
+// source: permissionapi/role.proto
+
+package permissionapi
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type BulkUpdatePlayerRolesRequest_ChangeType int32
+
+const (
+	BulkUpdatePlayerRolesRequest_ADD    BulkUpdatePlayerRolesRequest_ChangeType = 0
+	BulkUpdatePlayerRolesRequest_REMOVE BulkUpdatePlayerRolesRequest_ChangeType = 1
+)
+
+// Enum value maps for BulkUpdatePlayerRolesRequest_ChangeType.
+var (
+	BulkUpdatePlayerRolesRequest_ChangeType_name = map[int32]string{
+		0: "ADD",
+		1: "REMOVE",
+	}
+	BulkUpdatePlayerRolesRequest_ChangeType_value = map[string]int32{
+		"ADD":    0,
+		"REMOVE": 1,
+	}
+)
+
+func (x BulkUpdatePlayerRolesRequest_ChangeType) Enum() *BulkUpdatePlayerRolesRequest_ChangeType {
+	p := new(BulkUpdatePlayerRolesRequest_ChangeType)
+	*p = x
+	return p
+}
+
+func (x BulkUpdatePlayerRolesRequest_ChangeType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BulkUpdatePlayerRolesRequest_ChangeType) Descriptor() protoreflect.EnumDescriptor {
+	return file_permissionapi_role_proto_enumTypes[0].Descriptor()
+}
+
+func (BulkUpdatePlayerRolesRequest_ChangeType) Type() protoreflect.EnumType {
+	return &file_permissionapi_role_proto_enumTypes[0]
+}
+
+func (x BulkUpdatePlayerRolesRequest_ChangeType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BulkUpdatePlayerRolesRequest_ChangeType.Descriptor instead.
+func (BulkUpdatePlayerRolesRequest_ChangeType) EnumDescriptor() ([]byte, []int) {
+	return file_permissionapi_role_proto_rawDescGZIP(), []int{2, 0}
+}
+
+type BulkUpdatePlayerRolesResponse_Result_Status int32
+
+const (
+	// UPDATED means the role was added or removed, depending on the change type
+	BulkUpdatePlayerRolesResponse_Result_UPDATED            BulkUpdatePlayerRolesResponse_Result_Status = 0
+	BulkUpdatePlayerRolesResponse_Result_ALREADY_HAS_ROLE   BulkUpdatePlayerRolesResponse_Result_Status = 1
+	BulkUpdatePlayerRolesResponse_Result_DOES_NOT_HAVE_ROLE BulkUpdatePlayerRolesResponse_Result_Status = 2
+	BulkUpdatePlayerRolesResponse_Result_INVALID_PLAYER_ID  BulkUpdatePlayerRolesResponse_Result_Status = 3
+	// DUPLICATE is a player id that appeared earlier in the request
+	BulkUpdatePlayerRolesResponse_Result_DUPLICATE BulkUpdatePlayerRolesResponse_Result_Status = 4
+	// FAILED means the player may not have been changed, as the write failed or its outcome is unknown
+	BulkUpdatePlayerRolesResponse_Result_FAILED BulkUpdatePlayerRolesResponse_Result_Status = 5
+)
+
+// Enum value maps for BulkUpdatePlayerRolesResponse_Result_Status.
+var (
+	BulkUpdatePlayerRolesResponse_Result_Status_name = map[int32]string{
+		0: "UPDATED",
+		1: "ALREADY_HAS_ROLE",
+		2: "DOES_NOT_HAVE_ROLE",
+		3: "INVALID_PLAYER_ID",
+		4: "DUPLICATE",
+		5: "FAILED",
+	}
+	BulkUpdatePlayerRolesResponse_Result_Status_value = map[string]int32{
+		"UPDATED":            0,
+		"ALREADY_HAS_ROLE":   1,
+		"DOES_NOT_HAVE_ROLE": 2,
+		"INVALID_PLAYER_ID":  3,
+		"DUPLICATE":          4,
+		"FAILED":             5,
+	}
+)
+
+func (x BulkUpdatePlayerRolesResponse_Result_Status) Enum() *BulkUpdatePlayerRolesResponse_Result_Status {
+	p := new(BulkUpdatePlayerRolesResponse_Result_Status)
+	*p = x
+	return p
+}
+
+func (x BulkUpdatePlayerRolesResponse_Result_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BulkUpdatePlayerRolesResponse_Result_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_permissionapi_role_proto_enumTypes[1].Descriptor()
+}
+
+func (BulkUpdatePlayerRolesResponse_Result_Status) Type() protoreflect.EnumType {
+	return &file_permissionapi_role_proto_enumTypes[1]
+}
+
+func (x BulkUpdatePlayerRolesResponse_Result_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BulkUpdatePlayerRolesResponse_Result_Status.Descriptor instead.
+func (BulkUpdatePlayerRolesResponse_Result_Status) EnumDescriptor() ([]byte, []int) {
+	return file_permissionapi_role_proto_rawDescGZIP(), []int{3, 0, 0}
+}
+
+type DeleteRoleRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *DeleteRoleRequest) Reset() {
+	*x = DeleteRoleRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_permissionapi_role_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRoleRequest) ProtoMessage() {}
+
+func (x *DeleteRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_permissionapi_role_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRoleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRoleRequest) Descriptor() ([]byte, []int) {
+	return file_permissionapi_role_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DeleteRoleRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteRoleResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DeleteRoleResponse) Reset() {
+	*x = DeleteRoleResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_permissionapi_role_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteRoleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRoleResponse) ProtoMessage() {}
+
+func (x *DeleteRoleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_permissionapi_role_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRoleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteRoleResponse) Descriptor() ([]byte, []int) {
+	return file_permissionapi_role_proto_rawDescGZIP(), []int{1}
+}
+
+type BulkUpdatePlayerRolesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoleId     string                                  `protobuf:"bytes,1,opt,name=role_id,json=roleId,proto3" json:"role_id,omitempty"`
+	ChangeType BulkUpdatePlayerRolesRequest_ChangeType `protobuf:"varint,2,opt,name=change_type,json=changeType,proto3,enum=emortal.grpc.permission.BulkUpdatePlayerRolesRequest_ChangeType" json:"change_type,omitempty"`
+	PlayerIds  []string                                `protobuf:"bytes,3,rep,name=player_ids,json=playerIds,proto3" json:"player_ids,omitempty"`
+}
+
+func (x *BulkUpdatePlayerRolesRequest) Reset() {
+	*x = BulkUpdatePlayerRolesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_permissionapi_role_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BulkUpdatePlayerRolesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkUpdatePlayerRolesRequest) ProtoMessage() {}
+
+func (x *BulkUpdatePlayerRolesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_permissionapi_role_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkUpdatePlayerRolesRequest.ProtoReflect.Descriptor instead.
+func (*BulkUpdatePlayerRolesRequest) Descriptor() ([]byte, []int) {
+	return file_permissionapi_role_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BulkUpdatePlayerRolesRequest) GetRoleId() string {
+	if x != nil {
+		return x.RoleId
+	}
+	return ""
+}
+
+func (x *BulkUpdatePlayerRolesRequest) GetChangeType() BulkUpdatePlayerRolesRequest_ChangeType {
+	if x != nil {
+		return x.ChangeType
+	}
+	return BulkUpdatePlayerRolesRequest_ADD
+}
+
+func (x *BulkUpdatePlayerRolesRequest) GetPlayerIds() []string {
+	if x != nil {
+		return x.PlayerIds
+	}
+	return nil
+}
+
+type BulkUpdatePlayerRolesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// results are in the same order as the request's player ids
+	Results []*BulkUpdatePlayerRolesResponse_Result `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *BulkUpdatePlayerRolesResponse) Reset() {
+	*x = BulkUpdatePlayerRolesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_permissionapi_role_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BulkUpdatePlayerRolesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkUpdatePlayerRolesResponse) ProtoMessage() {}
+
+func (x *BulkUpdatePlayerRolesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_permissionapi_role_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkUpdatePlayerRolesResponse.ProtoReflect.Descriptor instead.
+func (*BulkUpdatePlayerRolesResponse) Descriptor() ([]byte, []int) {
+	return file_permissionapi_role_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BulkUpdatePlayerRolesResponse) GetResults() []*BulkUpdatePlayerRolesResponse_Result {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type BulkUpdatePlayerRolesResponse_Result struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PlayerId string                                      `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Status   BulkUpdatePlayerRolesResponse_Result_Status `protobuf:"varint,2,opt,name=status,proto3,enum=emortal.grpc.permission.BulkUpdatePlayerRolesResponse_Result_Status" json:"status,omitempty"`
+}
+
+func (x *BulkUpdatePlayerRolesResponse_Result) Reset() {
+	*x = BulkUpdatePlayerRolesResponse_Result{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_permissionapi_role_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BulkUpdatePlayerRolesResponse_Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkUpdatePlayerRolesResponse_Result) ProtoMessage() {}
+
+func (x *BulkUpdatePlayerRolesResponse_Result) ProtoReflect() protoreflect.Message {
+	mi := &file_permissionapi_role_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkUpdatePlayerRolesResponse_Result.ProtoReflect.Descriptor instead.
+func (*BulkUpdatePlayerRolesResponse_Result) Descriptor() ([]byte, []int) {
+	return file_permissionapi_role_proto_rawDescGZIP(), []int{3, 0}
+}
+
+func (x *BulkUpdatePlayerRolesResponse_Result) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *BulkUpdatePlayerRolesResponse_Result) GetStatus() BulkUpdatePlayerRolesResponse_Result_Status {
+	if x != nil {
+		return x.Status
+	}
+	return BulkUpdatePlayerRolesResponse_Result_UPDATED
+}
+
+var File_permissionapi_role_proto protoreflect.FileDescriptor
+
+var file_permissionapi_role_proto_rawDesc = []byte{
+	0x0a, 0x18, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x61, 0x70, 0x69, 0x2f,
+	0x72, 0x6f, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x17, 0x65, 0x6d, 0x6f, 0x72,
+	0x74, 0x61, 0x6c, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x22, 0x23, 0x0a, 0x11, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x6f, 0x6c,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x14, 0x0a, 0x12, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x52, 0x6f, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xdc,
+	0x01, 0x0a, 0x1c, 0x42, 0x75, 0x6c, 0x6b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x6c, 0x61,
+	0x79, 0x65, 0x72, 0x52, 0x6f, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x17, 0x0a, 0x07, 0x72, 0x6f, 0x6c, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x72, 0x6f, 0x6c, 0x65, 0x49, 0x64, 0x12, 0x61, 0x0a, 0x0b, 0x63, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x40, 0x2e,
+	0x65, 0x6d, 0x6f, 0x72, 0x74, 0x61, 0x6c, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x70, 0x65, 0x72,
+	0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x42, 0x75, 0x6c, 0x6b, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x6f, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x0a, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x09, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x64, 0x73, 0x22, 0x21, 0x0a, 0x0a, 0x43, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x44, 0x44, 0x10,
+	0x00, 0x12, 0x0a, 0x0a, 0x06, 0x52, 0x45, 0x4d, 0x4f, 0x56, 0x45, 0x10, 0x01, 0x22, 0xf5, 0x02,
+	0x0a, 0x1d, 0x42, 0x75, 0x6c, 0x6b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x6c, 0x61, 0x79,
+	0x65, 0x72, 0x52, 0x6f, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x57, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x3d, 0x2e, 0x65, 0x6d, 0x6f, 0x72, 0x74, 0x61, 0x6c, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x42, 0x75, 0x6c, 0x6b, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x6f, 0x6c, 0x65, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52,
+	0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x1a, 0xfa, 0x01, 0x0a, 0x06, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x64,
+	0x12, 0x5c, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x44, 0x2e, 0x65, 0x6d, 0x6f, 0x72, 0x74, 0x61, 0x6c, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x42, 0x75, 0x6c, 0x6b, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x6f, 0x6c, 0x65, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x2e,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x75,
+	0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x50, 0x44, 0x41,
+	0x54, 0x45, 0x44, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x41, 0x4c, 0x52, 0x45, 0x41, 0x44, 0x59,
+	0x5f, 0x48, 0x41, 0x53, 0x5f, 0x52, 0x4f, 0x4c, 0x45, 0x10, 0x01, 0x12, 0x16, 0x0a, 0x12, 0x44,
+	0x4f, 0x45, 0x53, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x48, 0x41, 0x56, 0x45, 0x5f, 0x52, 0x4f, 0x4c,
+	0x45, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x50,
+	0x4c, 0x41, 0x59, 0x45, 0x52, 0x5f, 0x49, 0x44, 0x10, 0x03, 0x12, 0x0d, 0x0a, 0x09, 0x44, 0x55,
+	0x50, 0x4c, 0x49, 0x43, 0x41, 0x54, 0x45, 0x10, 0x04, 0x12, 0x0a, 0x0a, 0x06, 0x46, 0x41, 0x49,
+	0x4c, 0x45, 0x44, 0x10, 0x05, 0x32, 0xfd, 0x01, 0x0a, 0x0b, 0x52, 0x6f, 0x6c, 0x65, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x65, 0x0a, 0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52,
+	0x6f, 0x6c, 0x65, 0x12, 0x2a, 0x2e, 0x65, 0x6d, 0x6f, 0x72, 0x74, 0x61, 0x6c, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x52, 0x6f, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x2b, 0x2e, 0x65, 0x6d, 0x6f, 0x72, 0x74, 0x61, 0x6c, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x70,
+	0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x52, 0x6f, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x86, 0x01, 0x0a,
+	0x15, 0x42, 0x75, 0x6c, 0x6b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x6c, 0x61, 0x79, 0x65,
+	0x72, 0x52, 0x6f, 0x6c, 0x65, 0x73, 0x12, 0x35, 0x2e, 0x65, 0x6d, 0x6f, 0x72, 0x74, 0x61, 0x6c,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x2e, 0x42, 0x75, 0x6c, 0x6b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x6c, 0x61, 0x79, 0x65,
+	0x72, 0x52, 0x6f, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x36, 0x2e,
+	0x65, 0x6d, 0x6f, 0x72, 0x74, 0x61, 0x6c, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x70, 0x65, 0x72,
+	0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x42, 0x75, 0x6c, 0x6b, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x6f, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x26, 0x5a, 0x24, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f,
+	0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_permissionapi_role_proto_rawDescOnce sync.Once
+	file_permissionapi_role_proto_rawDescData = file_permissionapi_role_proto_rawDesc
+)
+
+func file_permissionapi_role_proto_rawDescGZIP() []byte {
+	file_permissionapi_role_proto_rawDescOnce.Do(func() {
+		file_permissionapi_role_proto_rawDescData = protoimpl.X.CompressGZIP(file_permissionapi_role_proto_rawDescData)
+	})
+	return file_permissionapi_role_proto_rawDescData
+}
+
+var file_permissionapi_role_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_permissionapi_role_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_permissionapi_role_proto_goTypes = []interface{}{
+	(BulkUpdatePlayerRolesRequest_ChangeType)(0),     // 0: emortal.grpc.permission.BulkUpdatePlayerRolesRequest.ChangeType
+	(BulkUpdatePlayerRolesResponse_Result_Status)(0), // 1: emortal.grpc.permission.BulkUpdatePlayerRolesResponse.Result.Status
+	(*DeleteRoleRequest)(nil),                        // 2: emortal.grpc.permission.DeleteRoleRequest
+	(*DeleteRoleResponse)(nil),                       // 3: emortal.grpc.permission.DeleteRoleResponse
+	(*BulkUpdatePlayerRolesRequest)(nil),             // 4: emortal.grpc.permission.BulkUpdatePlayerRolesRequest
+	(*BulkUpdatePlayerRolesResponse)(nil),            // 5: emortal.grpc.permission.BulkUpdatePlayerRolesResponse
+	(*BulkUpdatePlayerRolesResponse_Result)(nil),     // 6: emortal.grpc.permission.BulkUpdatePlayerRolesResponse.Result
+}
+var file_permissionapi_role_proto_depIdxs = []int32{
+	0, // 0: emortal.grpc.permission.BulkUpdatePlayerRolesRequest.change_type:type_name -> emortal.grpc.permission.BulkUpdatePlayerRolesRequest.ChangeType
+	6, // 1: emortal.grpc.permission.BulkUpdatePlayerRolesResponse.results:type_name -> emortal.grpc.permission.BulkUpdatePlayerRolesResponse.Result
+	1, // 2: emortal.grpc.permission.BulkUpdatePlayerRolesResponse.Result.status:type_name -> emortal.grpc.permission.BulkUpdatePlayerRolesResponse.Result.Status
+	2, // 3: emortal.grpc.permission.RoleService.DeleteRole:input_type -> emortal.grpc.permission.DeleteRoleRequest
+	4, // 4: emortal.grpc.permission.RoleService.BulkUpdatePlayerRoles:input_type -> emortal.grpc.permission.BulkUpdatePlayerRolesRequest
+	3, // 5: emortal.grpc.permission.RoleService.DeleteRole:output_type -> emortal.grpc.permission.DeleteRoleResponse
+	5, // 6: emortal.grpc.permission.RoleService.BulkUpdatePlayerRoles:output_type -> emortal.grpc.permission.BulkUpdatePlayerRolesResponse
+	5, // [5:7] is the sub-list for method output_type
+	3, // [3:5] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_permissionapi_role_proto_init() }
+func file_permissionapi_role_proto_init() {
+	if File_permissionapi_role_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_permissionapi_role_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteRoleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_permissionapi_role_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteRoleResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_permissionapi_role_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BulkUpdatePlayerRolesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_permissionapi_role_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BulkUpdatePlayerRolesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_permissionapi_role_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BulkUpdatePlayerRolesResponse_Result); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_permissionapi_role_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_permissionapi_role_proto_goTypes,
+		DependencyIndexes: file_permissionapi_role_proto_depIdxs,
+		EnumInfos:         file_permissionapi_role_proto_enumTypes,
+		MessageInfos:      file_permissionapi_role_proto_msgTypes,
+	}.Build()
+	File_permissionapi_role_proto = out.File
+	file_permissionapi_role_proto_rawDesc = nil
+	file_permissionapi_role_proto_goTypes = nil
+	file_permissionapi_role_proto_depIdxs = nil
+}
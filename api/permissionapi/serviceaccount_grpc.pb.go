@@ -0,0 +1,335 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: permissionapi/serviceaccount.proto
+
+package permissionapi
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ServiceAccountService_GetServiceAccounts_FullMethodName              = "/emortal.grpc.permission.ServiceAccountService/GetServiceAccounts"
+	ServiceAccountService_CreateServiceAccount_FullMethodName            = "/emortal.grpc.permission.ServiceAccountService/CreateServiceAccount"
+	ServiceAccountService_DeleteServiceAccount_FullMethodName            = "/emortal.grpc.permission.ServiceAccountService/DeleteServiceAccount"
+	ServiceAccountService_RotateServiceAccountToken_FullMethodName       = "/emortal.grpc.permission.ServiceAccountService/RotateServiceAccountToken"
+	ServiceAccountService_AddRoleToServiceAccount_FullMethodName         = "/emortal.grpc.permission.ServiceAccountService/AddRoleToServiceAccount"
+	ServiceAccountService_RemoveRoleFromServiceAccount_FullMethodName    = "/emortal.grpc.permission.ServiceAccountService/RemoveRoleFromServiceAccount"
+	ServiceAccountService_UpdateServiceAccountPermissions_FullMethodName = "/emortal.grpc.permission.ServiceAccountService/UpdateServiceAccountPermissions"
+)
+
+// ServiceAccountServiceClient is the client API for ServiceAccountService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ServiceAccountServiceClient interface {
+	GetServiceAccounts(ctx context.Context, in *GetServiceAccountsRequest, opts ...grpc.CallOption) (*GetServiceAccountsResponse, error)
+	// CreateServiceAccount returns the account's API token. It is only ever returned here or by RotateServiceAccountToken.
+	CreateServiceAccount(ctx context.Context, in *CreateServiceAccountRequest, opts ...grpc.CallOption) (*CreateServiceAccountResponse, error)
+	DeleteServiceAccount(ctx context.Context, in *DeleteServiceAccountRequest, opts ...grpc.CallOption) (*DeleteServiceAccountResponse, error)
+	RotateServiceAccountToken(ctx context.Context, in *RotateServiceAccountTokenRequest, opts ...grpc.CallOption) (*RotateServiceAccountTokenResponse, error)
+	AddRoleToServiceAccount(ctx context.Context, in *AddRoleToServiceAccountRequest, opts ...grpc.CallOption) (*AddRoleToServiceAccountResponse, error)
+	RemoveRoleFromServiceAccount(ctx context.Context, in *RemoveRoleFromServiceAccountRequest, opts ...grpc.CallOption) (*RemoveRoleFromServiceAccountResponse, error)
+	// UpdateServiceAccountPermissions sets or unsets nodes granted directly to the account.
+	UpdateServiceAccountPermissions(ctx context.Context, in *UpdateServiceAccountPermissionsRequest, opts ...grpc.CallOption) (*UpdateServiceAccountPermissionsResponse, error)
+}
+
+type serviceAccountServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewServiceAccountServiceClient(cc grpc.ClientConnInterface) ServiceAccountServiceClient {
+	return &serviceAccountServiceClient{cc}
+}
+
+func (c *serviceAccountServiceClient) GetServiceAccounts(ctx context.Context, in *GetServiceAccountsRequest, opts ...grpc.CallOption) (*GetServiceAccountsResponse, error) {
+	out := new(GetServiceAccountsResponse)
+	err := c.cc.Invoke(ctx, ServiceAccountService_GetServiceAccounts_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountServiceClient) CreateServiceAccount(ctx context.Context, in *CreateServiceAccountRequest, opts ...grpc.CallOption) (*CreateServiceAccountResponse, error) {
+	out := new(CreateServiceAccountResponse)
+	err := c.cc.Invoke(ctx, ServiceAccountService_CreateServiceAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountServiceClient) DeleteServiceAccount(ctx context.Context, in *DeleteServiceAccountRequest, opts ...grpc.CallOption) (*DeleteServiceAccountResponse, error) {
+	out := new(DeleteServiceAccountResponse)
+	err := c.cc.Invoke(ctx, ServiceAccountService_DeleteServiceAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountServiceClient) RotateServiceAccountToken(ctx context.Context, in *RotateServiceAccountTokenRequest, opts ...grpc.CallOption) (*RotateServiceAccountTokenResponse, error) {
+	out := new(RotateServiceAccountTokenResponse)
+	err := c.cc.Invoke(ctx, ServiceAccountService_RotateServiceAccountToken_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountServiceClient) AddRoleToServiceAccount(ctx context.Context, in *AddRoleToServiceAccountRequest, opts ...grpc.CallOption) (*AddRoleToServiceAccountResponse, error) {
+	out := new(AddRoleToServiceAccountResponse)
+	err := c.cc.Invoke(ctx, ServiceAccountService_AddRoleToServiceAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountServiceClient) RemoveRoleFromServiceAccount(ctx context.Context, in *RemoveRoleFromServiceAccountRequest, opts ...grpc.CallOption) (*RemoveRoleFromServiceAccountResponse, error) {
+	out := new(RemoveRoleFromServiceAccountResponse)
+	err := c.cc.Invoke(ctx, ServiceAccountService_RemoveRoleFromServiceAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountServiceClient) UpdateServiceAccountPermissions(ctx context.Context, in *UpdateServiceAccountPermissionsRequest, opts ...grpc.CallOption) (*UpdateServiceAccountPermissionsResponse, error) {
+	out := new(UpdateServiceAccountPermissionsResponse)
+	err := c.cc.Invoke(ctx, ServiceAccountService_UpdateServiceAccountPermissions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ServiceAccountServiceServer is the server API for ServiceAccountService service.
+// All implementations must embed UnimplementedServiceAccountServiceServer
+// for forward compatibility
+type ServiceAccountServiceServer interface {
+	GetServiceAccounts(context.Context, *GetServiceAccountsRequest) (*GetServiceAccountsResponse, error)
+	// CreateServiceAccount returns the account's API token. It is only ever returned here or by RotateServiceAccountToken.
+	CreateServiceAccount(context.Context, *CreateServiceAccountRequest) (*CreateServiceAccountResponse, error)
+	DeleteServiceAccount(context.Context, *DeleteServiceAccountRequest) (*DeleteServiceAccountResponse, error)
+	RotateServiceAccountToken(context.Context, *RotateServiceAccountTokenRequest) (*RotateServiceAccountTokenResponse, error)
+	AddRoleToServiceAccount(context.Context, *AddRoleToServiceAccountRequest) (*AddRoleToServiceAccountResponse, error)
+	RemoveRoleFromServiceAccount(context.Context, *RemoveRoleFromServiceAccountRequest) (*RemoveRoleFromServiceAccountResponse, error)
+	// UpdateServiceAccountPermissions sets or unsets nodes granted directly to the account.
+	UpdateServiceAccountPermissions(context.Context, *UpdateServiceAccountPermissionsRequest) (*UpdateServiceAccountPermissionsResponse, error)
+	mustEmbedUnimplementedServiceAccountServiceServer()
+}
+
+// UnimplementedServiceAccountServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedServiceAccountServiceServer struct {
+}
+
+func (UnimplementedServiceAccountServiceServer) GetServiceAccounts(context.Context, *GetServiceAccountsRequest) (*GetServiceAccountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetServiceAccounts not implemented")
+}
+func (UnimplementedServiceAccountServiceServer) CreateServiceAccount(context.Context, *CreateServiceAccountRequest) (*CreateServiceAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateServiceAccount not implemented")
+}
+func (UnimplementedServiceAccountServiceServer) DeleteServiceAccount(context.Context, *DeleteServiceAccountRequest) (*DeleteServiceAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteServiceAccount not implemented")
+}
+func (UnimplementedServiceAccountServiceServer) RotateServiceAccountToken(context.Context, *RotateServiceAccountTokenRequest) (*RotateServiceAccountTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateServiceAccountToken not implemented")
+}
+func (UnimplementedServiceAccountServiceServer) AddRoleToServiceAccount(context.Context, *AddRoleToServiceAccountRequest) (*AddRoleToServiceAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRoleToServiceAccount not implemented")
+}
+func (UnimplementedServiceAccountServiceServer) RemoveRoleFromServiceAccount(context.Context, *RemoveRoleFromServiceAccountRequest) (*RemoveRoleFromServiceAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveRoleFromServiceAccount not implemented")
+}
+func (UnimplementedServiceAccountServiceServer) UpdateServiceAccountPermissions(context.Context, *UpdateServiceAccountPermissionsRequest) (*UpdateServiceAccountPermissionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateServiceAccountPermissions not implemented")
+}
+func (UnimplementedServiceAccountServiceServer) mustEmbedUnimplementedServiceAccountServiceServer() {}
+
+// UnsafeServiceAccountServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ServiceAccountServiceServer will
+// result in compilation errors.
+type UnsafeServiceAccountServiceServer interface {
+	mustEmbedUnimplementedServiceAccountServiceServer()
+}
+
+func RegisterServiceAccountServiceServer(s grpc.ServiceRegistrar, srv ServiceAccountServiceServer) {
+	s.RegisterService(&ServiceAccountService_ServiceDesc, srv)
+}
+
+func _ServiceAccountService_GetServiceAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetServiceAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountServiceServer).GetServiceAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccountService_GetServiceAccounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountServiceServer).GetServiceAccounts(ctx, req.(*GetServiceAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccountService_CreateServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateServiceAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountServiceServer).CreateServiceAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccountService_CreateServiceAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountServiceServer).CreateServiceAccount(ctx, req.(*CreateServiceAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccountService_DeleteServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteServiceAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountServiceServer).DeleteServiceAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccountService_DeleteServiceAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountServiceServer).DeleteServiceAccount(ctx, req.(*DeleteServiceAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccountService_RotateServiceAccountToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateServiceAccountTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountServiceServer).RotateServiceAccountToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccountService_RotateServiceAccountToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountServiceServer).RotateServiceAccountToken(ctx, req.(*RotateServiceAccountTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccountService_AddRoleToServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRoleToServiceAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountServiceServer).AddRoleToServiceAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccountService_AddRoleToServiceAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountServiceServer).AddRoleToServiceAccount(ctx, req.(*AddRoleToServiceAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccountService_RemoveRoleFromServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRoleFromServiceAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountServiceServer).RemoveRoleFromServiceAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccountService_RemoveRoleFromServiceAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountServiceServer).RemoveRoleFromServiceAccount(ctx, req.(*RemoveRoleFromServiceAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccountService_UpdateServiceAccountPermissions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateServiceAccountPermissionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountServiceServer).UpdateServiceAccountPermissions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccountService_UpdateServiceAccountPermissions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountServiceServer).UpdateServiceAccountPermissions(ctx, req.(*UpdateServiceAccountPermissionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ServiceAccountService_ServiceDesc is the grpc.ServiceDesc for ServiceAccountService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ServiceAccountService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "emortal.grpc.permission.ServiceAccountService",
+	HandlerType: (*ServiceAccountServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetServiceAccounts",
+			Handler:    _ServiceAccountService_GetServiceAccounts_Handler,
+		},
+		{
+			MethodName: "CreateServiceAccount",
+			Handler:    _ServiceAccountService_CreateServiceAccount_Handler,
+		},
+		{
+			MethodName: "DeleteServiceAccount",
+			Handler:    _ServiceAccountService_DeleteServiceAccount_Handler,
+		},
+		{
+			MethodName: "RotateServiceAccountToken",
+			Handler:    _ServiceAccountService_RotateServiceAccountToken_Handler,
+		},
+		{
+			MethodName: "AddRoleToServiceAccount",
+			Handler:    _ServiceAccountService_AddRoleToServiceAccount_Handler,
+		},
+		{
+			MethodName: "RemoveRoleFromServiceAccount",
+			Handler:    _ServiceAccountService_RemoveRoleFromServiceAccount_Handler,
+		},
+		{
+			MethodName: "UpdateServiceAccountPermissions",
+			Handler:    _ServiceAccountService_UpdateServiceAccountPermissions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "permissionapi/serviceaccount.proto",
+}
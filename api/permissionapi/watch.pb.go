@@ -0,0 +1,468 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.32.0
+// 	protoc        (unknown)
+// source: permissionapi/watch.proto
+
+package permissionapi
+
+import (
+	permission "github.com/emortalmc/proto-specs/gen/go/message/permission"
+	permission1 "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type WatchPlayerPermissionsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PlayerIds []string `protobuf:"bytes,1,rep,name=player_ids,json=playerIds,proto3" json:"player_ids,omitempty"`
+	// all_roles watches every role, not only those held by the watched players
+	AllRoles bool `protobuf:"varint,2,opt,name=all_roles,json=allRoles,proto3" json:"all_roles,omitempty"`
+}
+
+func (x *WatchPlayerPermissionsRequest) Reset() {
+	*x = WatchPlayerPermissionsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_permissionapi_watch_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchPlayerPermissionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchPlayerPermissionsRequest) ProtoMessage() {}
+
+func (x *WatchPlayerPermissionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_permissionapi_watch_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchPlayerPermissionsRequest.ProtoReflect.Descriptor instead.
+func (*WatchPlayerPermissionsRequest) Descriptor() ([]byte, []int) {
+	return file_permissionapi_watch_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *WatchPlayerPermissionsRequest) GetPlayerIds() []string {
+	if x != nil {
+		return x.PlayerIds
+	}
+	return nil
+}
+
+func (x *WatchPlayerPermissionsRequest) GetAllRoles() bool {
+	if x != nil {
+		return x.AllRoles
+	}
+	return false
+}
+
+type WatchPlayerPermissionsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Update:
+	//	*WatchPlayerPermissionsResponse_Snapshot_
+	//	*WatchPlayerPermissionsResponse_RoleUpdate
+	//	*WatchPlayerPermissionsResponse_PlayerRolesUpdate
+	Update isWatchPlayerPermissionsResponse_Update `protobuf_oneof:"update"`
+}
+
+func (x *WatchPlayerPermissionsResponse) Reset() {
+	*x = WatchPlayerPermissionsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_permissionapi_watch_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchPlayerPermissionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchPlayerPermissionsResponse) ProtoMessage() {}
+
+func (x *WatchPlayerPermissionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_permissionapi_watch_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchPlayerPermissionsResponse.ProtoReflect.Descriptor instead.
+func (*WatchPlayerPermissionsResponse) Descriptor() ([]byte, []int) {
+	return file_permissionapi_watch_proto_rawDescGZIP(), []int{1}
+}
+
+func (m *WatchPlayerPermissionsResponse) GetUpdate() isWatchPlayerPermissionsResponse_Update {
+	if m != nil {
+		return m.Update
+	}
+	return nil
+}
+
+func (x *WatchPlayerPermissionsResponse) GetSnapshot() *WatchPlayerPermissionsResponse_Snapshot {
+	if x, ok := x.GetUpdate().(*WatchPlayerPermissionsResponse_Snapshot_); ok {
+		return x.Snapshot
+	}
+	return nil
+}
+
+func (x *WatchPlayerPermissionsResponse) GetRoleUpdate() *permission.RoleUpdateMessage {
+	if x, ok := x.GetUpdate().(*WatchPlayerPermissionsResponse_RoleUpdate); ok {
+		return x.RoleUpdate
+	}
+	return nil
+}
+
+func (x *WatchPlayerPermissionsResponse) GetPlayerRolesUpdate() *permission.PlayerRolesUpdateMessage {
+	if x, ok := x.GetUpdate().(*WatchPlayerPermissionsResponse_PlayerRolesUpdate); ok {
+		return x.PlayerRolesUpdate
+	}
+	return nil
+}
+
+type isWatchPlayerPermissionsResponse_Update interface {
+	isWatchPlayerPermissionsResponse_Update()
+}
+
+type WatchPlayerPermissionsResponse_Snapshot_ struct {
+	Snapshot *WatchPlayerPermissionsResponse_Snapshot `protobuf:"bytes,1,opt,name=snapshot,proto3,oneof"`
+}
+
+type WatchPlayerPermissionsResponse_RoleUpdate struct {
+	// role_update is sent for roles held by a watched player, or every role if all_roles is set.
+	// A role a watched player didn't hold before is sent as a MODIFY when they're given it.
+	RoleUpdate *permission.RoleUpdateMessage `protobuf:"bytes,2,opt,name=role_update,json=roleUpdate,proto3,oneof"`
+}
+
+type WatchPlayerPermissionsResponse_PlayerRolesUpdate struct {
+	PlayerRolesUpdate *permission.PlayerRolesUpdateMessage `protobuf:"bytes,3,opt,name=player_roles_update,json=playerRolesUpdate,proto3,oneof"`
+}
+
+func (*WatchPlayerPermissionsResponse_Snapshot_) isWatchPlayerPermissionsResponse_Update() {}
+
+func (*WatchPlayerPermissionsResponse_RoleUpdate) isWatchPlayerPermissionsResponse_Update() {}
+
+func (*WatchPlayerPermissionsResponse_PlayerRolesUpdate) isWatchPlayerPermissionsResponse_Update() {}
+
+type WatchPlayerPermissionsResponse_Snapshot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Roles   []*permission1.Role                           `protobuf:"bytes,1,rep,name=roles,proto3" json:"roles,omitempty"`
+	Players []*WatchPlayerPermissionsResponse_PlayerRoles `protobuf:"bytes,2,rep,name=players,proto3" json:"players,omitempty"`
+}
+
+func (x *WatchPlayerPermissionsResponse_Snapshot) Reset() {
+	*x = WatchPlayerPermissionsResponse_Snapshot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_permissionapi_watch_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchPlayerPermissionsResponse_Snapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchPlayerPermissionsResponse_Snapshot) ProtoMessage() {}
+
+func (x *WatchPlayerPermissionsResponse_Snapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_permissionapi_watch_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchPlayerPermissionsResponse_Snapshot.ProtoReflect.Descriptor instead.
+func (*WatchPlayerPermissionsResponse_Snapshot) Descriptor() ([]byte, []int) {
+	return file_permissionapi_watch_proto_rawDescGZIP(), []int{1, 0}
+}
+
+func (x *WatchPlayerPermissionsResponse_Snapshot) GetRoles() []*permission1.Role {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *WatchPlayerPermissionsResponse_Snapshot) GetPlayers() []*WatchPlayerPermissionsResponse_PlayerRoles {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+type WatchPlayerPermissionsResponse_PlayerRoles struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PlayerId string   `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	RoleIds  []string `protobuf:"bytes,2,rep,name=role_ids,json=roleIds,proto3" json:"role_ids,omitempty"`
+}
+
+func (x *WatchPlayerPermissionsResponse_PlayerRoles) Reset() {
+	*x = WatchPlayerPermissionsResponse_PlayerRoles{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_permissionapi_watch_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchPlayerPermissionsResponse_PlayerRoles) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchPlayerPermissionsResponse_PlayerRoles) ProtoMessage() {}
+
+func (x *WatchPlayerPermissionsResponse_PlayerRoles) ProtoReflect() protoreflect.Message {
+	mi := &file_permissionapi_watch_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchPlayerPermissionsResponse_PlayerRoles.ProtoReflect.Descriptor instead.
+func (*WatchPlayerPermissionsResponse_PlayerRoles) Descriptor() ([]byte, []int) {
+	return file_permissionapi_watch_proto_rawDescGZIP(), []int{1, 1}
+}
+
+func (x *WatchPlayerPermissionsResponse_PlayerRoles) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *WatchPlayerPermissionsResponse_PlayerRoles) GetRoleIds() []string {
+	if x != nil {
+		return x.RoleIds
+	}
+	return nil
+}
+
+var File_permissionapi_watch_proto protoreflect.FileDescriptor
+
+var file_permissionapi_watch_proto_rawDesc = []byte{
+	0x0a, 0x19, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x61, 0x70, 0x69, 0x2f,
+	0x77, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x17, 0x65, 0x6d, 0x6f,
+	0x72, 0x74, 0x61, 0x6c, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x1a, 0x19, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x2f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
+	0x17, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2f, 0x6d, 0x6f, 0x64, 0x65,
+	0x6c, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x5b, 0x0a, 0x1d, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x6c, 0x61,
+	0x79, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x70,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x64, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x6c, 0x6c, 0x5f,
+	0x72, 0x6f, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x61, 0x6c, 0x6c,
+	0x52, 0x6f, 0x6c, 0x65, 0x73, 0x22, 0xad, 0x04, 0x0a, 0x1e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x50,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5e, 0x0a, 0x08, 0x73, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x40, 0x2e, 0x65, 0x6d, 0x6f,
+	0x72, 0x74, 0x61, 0x6c, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x48, 0x00, 0x52, 0x08,
+	0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x50, 0x0a, 0x0b, 0x72, 0x6f, 0x6c, 0x65,
+	0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2d, 0x2e,
+	0x65, 0x6d, 0x6f, 0x72, 0x74, 0x61, 0x6c, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e,
+	0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x0a,
+	0x72, 0x6f, 0x6c, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x66, 0x0a, 0x13, 0x70, 0x6c,
+	0x61, 0x79, 0x65, 0x72, 0x5f, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x65, 0x6d, 0x6f, 0x72, 0x74, 0x61,
+	0x6c, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x6f, 0x6c, 0x65, 0x73,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52,
+	0x11, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x6f, 0x6c, 0x65, 0x73, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x1a, 0x9f, 0x01, 0x0a, 0x08, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12,
+	0x34, 0x0a, 0x05, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e,
+	0x2e, 0x65, 0x6d, 0x6f, 0x72, 0x74, 0x61, 0x6c, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x70,
+	0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x52, 0x05,
+	0x72, 0x6f, 0x6c, 0x65, 0x73, 0x12, 0x5d, 0x0a, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x43, 0x2e, 0x65, 0x6d, 0x6f, 0x72, 0x74, 0x61, 0x6c,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x50, 0x65, 0x72, 0x6d,
+	0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e,
+	0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x6f, 0x6c, 0x65, 0x73, 0x52, 0x07, 0x70, 0x6c, 0x61,
+	0x79, 0x65, 0x72, 0x73, 0x1a, 0x45, 0x0a, 0x0b, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x6f,
+	0x6c, 0x65, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x64,
+	0x12, 0x19, 0x0a, 0x08, 0x72, 0x6f, 0x6c, 0x65, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x07, 0x72, 0x6f, 0x6c, 0x65, 0x49, 0x64, 0x73, 0x42, 0x08, 0x0a, 0x06, 0x75,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x32, 0xa6, 0x01, 0x0a, 0x16, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x12, 0x8b, 0x01, 0x0a, 0x16, 0x57, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x36, 0x2e, 0x65, 0x6d,
+	0x6f, 0x72, 0x74, 0x61, 0x6c, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x70, 0x65, 0x72, 0x6d, 0x69,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6c, 0x61, 0x79, 0x65,
+	0x72, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x37, 0x2e, 0x65, 0x6d, 0x6f, 0x72, 0x74, 0x61, 0x6c, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x42, 0x26,
+	0x5a, 0x24, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2d, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_permissionapi_watch_proto_rawDescOnce sync.Once
+	file_permissionapi_watch_proto_rawDescData = file_permissionapi_watch_proto_rawDesc
+)
+
+func file_permissionapi_watch_proto_rawDescGZIP() []byte {
+	file_permissionapi_watch_proto_rawDescOnce.Do(func() {
+		file_permissionapi_watch_proto_rawDescData = protoimpl.X.CompressGZIP(file_permissionapi_watch_proto_rawDescData)
+	})
+	return file_permissionapi_watch_proto_rawDescData
+}
+
+var file_permissionapi_watch_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_permissionapi_watch_proto_goTypes = []interface{}{
+	(*WatchPlayerPermissionsRequest)(nil),              // 0: emortal.grpc.permission.WatchPlayerPermissionsRequest
+	(*WatchPlayerPermissionsResponse)(nil),             // 1: emortal.grpc.permission.WatchPlayerPermissionsResponse
+	(*WatchPlayerPermissionsResponse_Snapshot)(nil),    // 2: emortal.grpc.permission.WatchPlayerPermissionsResponse.Snapshot
+	(*WatchPlayerPermissionsResponse_PlayerRoles)(nil), // 3: emortal.grpc.permission.WatchPlayerPermissionsResponse.PlayerRoles
+	(*permission.RoleUpdateMessage)(nil),               // 4: emortal.message.permission.RoleUpdateMessage
+	(*permission.PlayerRolesUpdateMessage)(nil),        // 5: emortal.message.permission.PlayerRolesUpdateMessage
+	(*permission1.Role)(nil),                           // 6: emortal.model.permission.Role
+}
+var file_permissionapi_watch_proto_depIdxs = []int32{
+	2, // 0: emortal.grpc.permission.WatchPlayerPermissionsResponse.snapshot:type_name -> emortal.grpc.permission.WatchPlayerPermissionsResponse.Snapshot
+	4, // 1: emortal.grpc.permission.WatchPlayerPermissionsResponse.role_update:type_name -> emortal.message.permission.RoleUpdateMessage
+	5, // 2: emortal.grpc.permission.WatchPlayerPermissionsResponse.player_roles_update:type_name -> emortal.message.permission.PlayerRolesUpdateMessage
+	6, // 3: emortal.grpc.permission.WatchPlayerPermissionsResponse.Snapshot.roles:type_name -> emortal.model.permission.Role
+	3, // 4: emortal.grpc.permission.WatchPlayerPermissionsResponse.Snapshot.players:type_name -> emortal.grpc.permission.WatchPlayerPermissionsResponse.PlayerRoles
+	0, // 5: emortal.grpc.permission.PermissionWatchService.WatchPlayerPermissions:input_type -> emortal.grpc.permission.WatchPlayerPermissionsRequest
+	1, // 6: emortal.grpc.permission.PermissionWatchService.WatchPlayerPermissions:output_type -> emortal.grpc.permission.WatchPlayerPermissionsResponse
+	6, // [6:7] is the sub-list for method output_type
+	5, // [5:6] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_permissionapi_watch_proto_init() }
+func file_permissionapi_watch_proto_init() {
+	if File_permissionapi_watch_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_permissionapi_watch_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchPlayerPermissionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_permissionapi_watch_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchPlayerPermissionsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_permissionapi_watch_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchPlayerPermissionsResponse_Snapshot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_permissionapi_watch_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchPlayerPermissionsResponse_PlayerRoles); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_permissionapi_watch_proto_msgTypes[1].OneofWrappers = []interface{}{
+		(*WatchPlayerPermissionsResponse_Snapshot_)(nil),
+		(*WatchPlayerPermissionsResponse_RoleUpdate)(nil),
+		(*WatchPlayerPermissionsResponse_PlayerRolesUpdate)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_permissionapi_watch_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_permissionapi_watch_proto_goTypes,
+		DependencyIndexes: file_permissionapi_watch_proto_depIdxs,
+		MessageInfos:      file_permissionapi_watch_proto_msgTypes,
+	}.Build()
+	File_permissionapi_watch_proto = out.File
+	file_permissionapi_watch_proto_rawDesc = nil
+	file_permissionapi_watch_proto_goTypes = nil
+	file_permissionapi_watch_proto_depIdxs = nil
+}
@@ -0,0 +1,143 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: permissionapi/watch.proto
+
+package permissionapi
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	PermissionWatchService_WatchPlayerPermissions_FullMethodName = "/emortal.grpc.permission.PermissionWatchService/WatchPlayerPermissions"
+)
+
+// PermissionWatchServiceClient is the client API for PermissionWatchService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PermissionWatchServiceClient interface {
+	// WatchPlayerPermissions sends a snapshot of the watched players' roles and the roles they hold,
+	// followed by every change applied by any replica until the client disconnects.
+	// The stream is aborted if the client falls too far behind, after which it should watch again for a new snapshot.
+	WatchPlayerPermissions(ctx context.Context, in *WatchPlayerPermissionsRequest, opts ...grpc.CallOption) (PermissionWatchService_WatchPlayerPermissionsClient, error)
+}
+
+type permissionWatchServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPermissionWatchServiceClient(cc grpc.ClientConnInterface) PermissionWatchServiceClient {
+	return &permissionWatchServiceClient{cc}
+}
+
+func (c *permissionWatchServiceClient) WatchPlayerPermissions(ctx context.Context, in *WatchPlayerPermissionsRequest, opts ...grpc.CallOption) (PermissionWatchService_WatchPlayerPermissionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PermissionWatchService_ServiceDesc.Streams[0], PermissionWatchService_WatchPlayerPermissions_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &permissionWatchServiceWatchPlayerPermissionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PermissionWatchService_WatchPlayerPermissionsClient interface {
+	Recv() (*WatchPlayerPermissionsResponse, error)
+	grpc.ClientStream
+}
+
+type permissionWatchServiceWatchPlayerPermissionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *permissionWatchServiceWatchPlayerPermissionsClient) Recv() (*WatchPlayerPermissionsResponse, error) {
+	m := new(WatchPlayerPermissionsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// PermissionWatchServiceServer is the server API for PermissionWatchService service.
+// All implementations must embed UnimplementedPermissionWatchServiceServer
+// for forward compatibility
+type PermissionWatchServiceServer interface {
+	// WatchPlayerPermissions sends a snapshot of the watched players' roles and the roles they hold,
+	// followed by every change applied by any replica until the client disconnects.
+	// The stream is aborted if the client falls too far behind, after which it should watch again for a new snapshot.
+	WatchPlayerPermissions(*WatchPlayerPermissionsRequest, PermissionWatchService_WatchPlayerPermissionsServer) error
+	mustEmbedUnimplementedPermissionWatchServiceServer()
+}
+
+// UnimplementedPermissionWatchServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedPermissionWatchServiceServer struct {
+}
+
+func (UnimplementedPermissionWatchServiceServer) WatchPlayerPermissions(*WatchPlayerPermissionsRequest, PermissionWatchService_WatchPlayerPermissionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchPlayerPermissions not implemented")
+}
+func (UnimplementedPermissionWatchServiceServer) mustEmbedUnimplementedPermissionWatchServiceServer() {
+}
+
+// UnsafePermissionWatchServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PermissionWatchServiceServer will
+// result in compilation errors.
+type UnsafePermissionWatchServiceServer interface {
+	mustEmbedUnimplementedPermissionWatchServiceServer()
+}
+
+func RegisterPermissionWatchServiceServer(s grpc.ServiceRegistrar, srv PermissionWatchServiceServer) {
+	s.RegisterService(&PermissionWatchService_ServiceDesc, srv)
+}
+
+func _PermissionWatchService_WatchPlayerPermissions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchPlayerPermissionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PermissionWatchServiceServer).WatchPlayerPermissions(m, &permissionWatchServiceWatchPlayerPermissionsServer{stream})
+}
+
+type PermissionWatchService_WatchPlayerPermissionsServer interface {
+	Send(*WatchPlayerPermissionsResponse) error
+	grpc.ServerStream
+}
+
+type permissionWatchServiceWatchPlayerPermissionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *permissionWatchServiceWatchPlayerPermissionsServer) Send(m *WatchPlayerPermissionsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// PermissionWatchService_ServiceDesc is the grpc.ServiceDesc for PermissionWatchService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PermissionWatchService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "emortal.grpc.permission.PermissionWatchService",
+	HandlerType: (*PermissionWatchServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchPlayerPermissions",
+			Handler:       _PermissionWatchService_WatchPlayerPermissions_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "permissionapi/watch.proto",
+}
@@ -8,25 +8,33 @@ import (
 )
 
 func main() {
-	cfg := config.LoadGlobalConfig()
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		log.Fatalf("failed to load config: %s", err)
+	}
 
-	unsugared, err := createLogger(cfg)
+	level := zap.NewAtomicLevelAt(cfg.Tunables().LogLevel)
+	unsugared, err := createLogger(cfg, level)
 	if err != nil {
 		log.Fatal(err)
 	}
 	logger := unsugared.Sugar()
 
-	app.Run(cfg, logger)
+	logger.Infow("loaded config", "file", cfg.File, "config", cfg.Redacted())
+	for _, renamed := range cfg.Deprecated {
+		logger.Warnw("config key is deprecated, use the new key instead", "key", renamed.Deprecated, "new", renamed.Key)
+	}
+
+	app.Run(cfg, logger, level)
 }
 
-func createLogger(cfg config.Config) (logger *zap.Logger, err error) {
+// createLogger creates a logger whose level can be changed through level when the config is reloaded
+func createLogger(cfg config.Config, level zap.AtomicLevel) (*zap.Logger, error) {
+	zapCfg := zap.NewProductionConfig()
 	if cfg.Development {
-		logger, err = zap.NewDevelopment()
-	} else {
-		logger, err = zap.NewProduction()
+		zapCfg = zap.NewDevelopmentConfig()
 	}
-	if err != nil {
-		return nil, err
-	}
-	return logger, nil
+	zapCfg.Level = level
+
+	return zapCfg.Build()
 }
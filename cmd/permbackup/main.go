@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+	"os"
+	"permission-service/internal/backup"
+	"permission-service/internal/config"
+	"permission-service/internal/messaging/notifier"
+	"permission-service/internal/repository"
+	"sync"
+	"time"
+)
+
+const usage = `Usage: permbackup [flags] <command>
+
+Commands:
+  backup <archive>
+  restore <archive> [--dry-run] [--publish]
+
+Restore replaces the collections in the archive, so the service should be stopped while it runs.
+
+Flags:
+`
+
+// publishActor is the actor of the role updates published after a restore
+const publishActor = "restore"
+
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	pflag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		pflag.PrintDefaults()
+	}
+
+	dryRun := pflag.Bool("dry-run", false, "Validate the archive and print what a restore would change without changing anything")
+	publish := pflag.Bool("publish", false, "Publish the restored roles to Kafka so caches resync")
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: failed to load config: %s\n", err)
+		os.Exit(2)
+	}
+
+	if err := run(cfg, pflag.Args(), *dryRun, *publish); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		if errors.Is(err, errUsage) {
+			pflag.Usage()
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(cfg config.Config, args []string, dryRun bool, publish bool) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+
+	logger := zap.NewNop().Sugar()
+	if cfg.Development {
+		unsugared, err := zap.NewDevelopment()
+		if err != nil {
+			return err
+		}
+		logger = unsugared.Sugar()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	// Disconnects and flushes the Kafka writers before exiting
+	defer wg.Wait()
+	defer cancel()
+
+	store, err := repository.NewMongoCollectionStore(ctx, logger, wg, cfg.MongoDB)
+	if err != nil {
+		return fmt.Errorf("failed to connect to mongo: %w", err)
+	}
+
+	switch args[0] {
+	case "backup":
+		return runBackup(ctx, store, args[1])
+	case "restore":
+		return runRestore(ctx, wg, logger, cfg, store, args[1], dryRun, publish)
+	default:
+		return errUsage
+	}
+}
+
+func runBackup(ctx context.Context, store repository.CollectionStore, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := backup.Backup(ctx, store, f, time.Now())
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// Don't leave a partial archive that could be mistaken for a backup
+		_ = os.Remove(path)
+		return err
+	}
+
+	for _, c := range manifest.Collections {
+		fmt.Printf("%s: %d documents\n", c.Name, c.Documents)
+	}
+	fmt.Printf("wrote %s\n", path)
+	return nil
+}
+
+func runRestore(ctx context.Context, wg *sync.WaitGroup, logger *zap.SugaredLogger, cfg config.Config,
+	store repository.CollectionStore, path string, dryRun bool, publish bool) error {
+
+	archive, err := backup.Open(path)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	contents, err := backup.Validate(archive, cfg.Bootstrap.DefaultRole.Id)
+	if err != nil {
+		return fmt.Errorf("invalid archive %s: %w", path, err)
+	}
+
+	plan, err := backup.NewPlan(ctx, store, archive, contents)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("archive created at %s\n", archive.Manifest.CreatedAt.Format(time.RFC3339))
+	plan.Print(os.Stdout)
+
+	if dryRun {
+		fmt.Println("dry run, no changes made")
+		return nil
+	}
+
+	if err := backup.Restore(ctx, store, archive); err != nil {
+		return err
+	}
+	fmt.Println("restored")
+
+	if !publish {
+		return nil
+	}
+
+	repo, err := repository.NewMongoRepository(ctx, logger, wg, cfg.MongoDB, cfg.Bootstrap.DefaultRole.Id)
+	if err != nil {
+		return fmt.Errorf("failed to create repository: %w", err)
+	}
+
+	notif := notifier.NewKafkaNotifier(ctx, wg, logger, cfg.Kafka, repo)
+	meta := notifier.ChangeMeta{Actor: publishActor, Reason: "restored from " + path, Timestamp: time.Now()}
+	if err := backup.Publish(ctx, notif, plan, meta); err != nil {
+		return fmt.Errorf("failed to publish roles: %w", err)
+	}
+
+	fmt.Printf("published %d roles\n", len(contents.Roles)+len(plan.DeletedRoles))
+	return nil
+}
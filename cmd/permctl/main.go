@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc/status"
+	"os"
+	"permission-service/internal/config"
+	"permission-service/internal/permctl"
+)
+
+func main() {
+	pflag.Usage = func() {
+		fmt.Fprint(os.Stderr, permctl.Usage)
+		pflag.PrintDefaults()
+	}
+
+	flags := permctl.DefineCommandFlags(pflag.CommandLine)
+	cfg := config.LoadClientConfig()
+
+	if err := run(cfg, flags); err != nil {
+		fail(err)
+	}
+}
+
+func run(cfg config.ClientConfig, flags *permctl.CommandFlags) error {
+	conn, err := permctl.Dial(cfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	cli, err := permctl.New(conn, flags, os.Stdout, cfg.Output)
+	if err != nil {
+		return err
+	}
+
+	// The timeout applies to each RPC rather than the whole command
+	return cli.Run(context.Background(), pflag.Args())
+}
+
+func fail(err error) {
+	if errors.Is(err, permctl.ErrUsage) {
+		fmt.Fprintln(os.Stderr, err)
+		pflag.Usage()
+		os.Exit(2)
+	}
+
+	if st, ok := status.FromError(err); ok {
+		fmt.Fprintf(os.Stderr, "error: %s: %s\n", st.Code(), st.Message())
+	} else {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+	}
+	os.Exit(1)
+}
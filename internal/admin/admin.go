@@ -0,0 +1,32 @@
+package admin
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"permission-service/internal/utils/runtime"
+)
+
+// Path the dashboard is served under by the HTTP gateway
+const Path = "/admin/"
+
+//go:embed static
+var static embed.FS
+
+// Handler serves the admin dashboard under Path. The dashboard itself is static and holds no data,
+// every role and player it shows is fetched from the HTTP gateway with the token the user signs in with.
+func Handler() http.Handler {
+	root, err := fs.Sub(static, "static")
+	runtime.Must(err)
+	files := http.StripPrefix(Path, http.FileServer(http.FS(root)))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The token is kept in session storage, so no other origin may script or frame the page
+		w.Header().Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		w.Header().Set("Cache-Control", "no-cache")
+
+		files.ServeHTTP(w, r)
+	})
+}
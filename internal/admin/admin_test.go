@@ -0,0 +1,37 @@
+package admin
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		path            string
+		wantStatus      int
+		wantContentType string
+	}{
+		{path: "/admin/", wantStatus: http.StatusOK, wantContentType: "text/html; charset=utf-8"},
+		{path: "/admin/app.js", wantStatus: http.StatusOK, wantContentType: "text/javascript; charset=utf-8"},
+		{path: "/admin/style.css", wantStatus: http.StatusOK, wantContentType: "text/css; charset=utf-8"},
+		{path: "/admin/missing.js", wantStatus: http.StatusNotFound},
+	}
+
+	handler := Handler()
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+			assert.Equal(t, test.wantStatus, rec.Code)
+			if test.wantContentType != "" {
+				assert.Equal(t, test.wantContentType, rec.Header().Get("Content-Type"))
+			}
+			assert.Contains(t, rec.Header().Get("Content-Security-Policy"), "frame-ancestors 'none'")
+		})
+	}
+}
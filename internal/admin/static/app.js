@@ -0,0 +1,296 @@
+'use strict';
+
+// The dashboard only talks to the HTTP gateway, every request is authenticated with the signed in token.
+const session = {
+    get token() { return sessionStorage.getItem('token'); },
+    get actor() { return sessionStorage.getItem('actor'); },
+};
+
+let roles = [];
+let editing = null;
+
+const $ = (id) => document.getElementById(id);
+
+function el(tag, props = {}, ...children) {
+    const node = document.createElement(tag);
+    Object.assign(node, props);
+    node.append(...children);
+    return node;
+}
+
+function showError(message) {
+    const error = $('error');
+    error.textContent = message;
+    error.hidden = !message;
+}
+
+async function api(method, path, body, reason) {
+    const headers = {'Authorization': 'Bearer ' + session.token};
+    if (session.actor) headers['x-actor-id'] = session.actor;
+    if (reason) headers['x-change-reason'] = reason;
+    if (body !== undefined) headers['Content-Type'] = 'application/json';
+
+    const res = await fetch(path, {method, headers, body: body === undefined ? undefined : JSON.stringify(body)});
+    const json = await res.json().catch(() => ({}));
+
+    if (res.status === 401) {
+        signOut();
+    }
+    if (!res.ok) {
+        throw new Error(json.message || `${res.status} ${res.statusText}`);
+    }
+    return json;
+}
+
+// run reports errors of an action instead of leaving the page half updated without explanation
+async function run(action) {
+    showError('');
+    try {
+        await action();
+    } catch (e) {
+        showError(e.message);
+    }
+}
+
+function showView(view) {
+    for (const name of ['sign-in', 'roles', 'players']) {
+        $(name).hidden = name !== view;
+    }
+    for (const button of document.querySelectorAll('nav [data-view]')) {
+        button.classList.toggle('active', button.dataset.view === view);
+    }
+    $('nav').hidden = view === 'sign-in';
+}
+
+function signOut() {
+    sessionStorage.clear();
+    roles = [];
+    editing = null;
+    showView('sign-in');
+}
+
+// Roles
+
+async function loadRoles() {
+    const res = await api('GET', '/v1/roles');
+    roles = (res.roles || []).sort((a, b) => b.priority - a.priority || a.id.localeCompare(b.id));
+    renderRoles();
+}
+
+function renderRoles() {
+    $('role-list').replaceChildren(...roles.map((role) => {
+        const row = el('tr', {className: 'selectable'},
+            el('td', {textContent: role.priority}),
+            el('td', {textContent: role.id}),
+            el('td', {textContent: role.displayName || ''}),
+            el('td', {textContent: (role.permissions || []).length}),
+        );
+        row.classList.toggle('selected', editing !== null && editing.id === role.id);
+        row.addEventListener('click', () => editRole(role));
+        return row;
+    }));
+
+    for (const select of [$('grant-role'), $('bulk-role')]) {
+        select.replaceChildren(...roles.map((role) => el('option', {value: role.id, textContent: role.id})));
+    }
+}
+
+function editRole(role) {
+    // Work on a copy so unsaved changes are discarded by selecting another role
+    editing = {
+        id: role.id,
+        original: new Map((role.permissions || []).map((p) => [p.node, p.state])),
+        nodes: new Map((role.permissions || []).map((p) => [p.node, p.state])),
+    };
+
+    const form = $('role-form');
+    form.priority.value = role.priority;
+    form.displayName.value = role.displayName || '';
+    form.reason.value = '';
+    $('role-editor-id').textContent = role.id;
+    $('role-editor').hidden = false;
+
+    renderNodes();
+    renderRoles();
+}
+
+function renderNodes() {
+    const nodes = [...editing.nodes.entries()].sort(([a], [b]) => a.localeCompare(b));
+
+    $('node-list').replaceChildren(...nodes.map(([node, state]) => {
+        const toggle = el('button', {type: 'button', textContent: state, className: state.toLowerCase()});
+        toggle.addEventListener('click', () => {
+            editing.nodes.set(node, state === 'ALLOW' ? 'DENY' : 'ALLOW');
+            renderNodes();
+        });
+
+        const remove = el('button', {type: 'button', textContent: 'Remove'});
+        remove.addEventListener('click', () => {
+            editing.nodes.delete(node);
+            renderNodes();
+        });
+
+        return el('tr', {}, el('td', {textContent: node}), el('td', {}, toggle), el('td', {}, remove));
+    }));
+}
+
+function addNode() {
+    const input = $('new-node');
+    const node = input.value.trim();
+    if (!node) return;
+
+    if (!editing.nodes.has(node)) {
+        editing.nodes.set(node, 'ALLOW');
+    }
+    input.value = '';
+    renderNodes();
+}
+
+async function saveRole(form) {
+    const role = roles.find((r) => r.id === editing.id);
+    const body = {
+        priority: Number(form.priority.value),
+        setPermissions: [],
+        unsetPermissions: [],
+    };
+
+    // Only send what changed, so concurrent edits to other nodes aren't overwritten
+    if ((role.displayName || '') !== form.displayName.value) {
+        body.displayName = form.displayName.value;
+    }
+    for (const [node, state] of editing.nodes) {
+        if (editing.original.get(node) !== state) {
+            body.setPermissions.push({node, state});
+        }
+    }
+    for (const node of editing.original.keys()) {
+        if (!editing.nodes.has(node)) {
+            body.unsetPermissions.push(node);
+        }
+    }
+
+    const res = await api('PATCH', '/v1/roles/' + encodeURIComponent(editing.id), body, form.reason.value);
+    await loadRoles();
+    editRole(res.role);
+}
+
+async function deleteRole(form) {
+    if (!confirm(`Delete ${editing.id}? Players holding it lose it.`)) return;
+
+    await api('DELETE', '/v1/roles/' + encodeURIComponent(editing.id), undefined, form.reason.value);
+    editing = null;
+    $('role-editor').hidden = true;
+    await loadRoles();
+}
+
+async function createRole(form) {
+    const body = {id: form.id.value.trim(), priority: Number(form.priority.value)};
+    if (form.displayName.value) {
+        body.displayName = form.displayName.value;
+    }
+
+    const res = await api('POST', '/v1/roles', body);
+    form.reset();
+    await loadRoles();
+    editRole(res.role);
+}
+
+// Players
+
+async function loadPlayer(playerId) {
+    const res = await api('GET', '/v1/players/' + encodeURIComponent(playerId) + '/roles');
+
+    $('player-role-list').replaceChildren(...(res.roleIds || []).map((roleId) => {
+        const remove = el('button', {type: 'button', textContent: 'Remove'});
+        remove.addEventListener('click', () => run(async () => {
+            if (!confirm(`Remove ${roleId} from ${playerId}?`)) return;
+            await api('DELETE', rolePath(playerId, roleId));
+            await loadPlayer(playerId);
+        }));
+
+        return el('tr', {}, el('td', {textContent: roleId}), el('td', {}, remove));
+    }));
+    $('player-display-role').textContent = res.activeDisplayNameRoleId || 'none';
+    $('player-roles').hidden = false;
+}
+
+// bulkUpdate isn't atomic, so every player's result is shown, including any that FAILED and can be retried
+async function bulkUpdate(form) {
+    const playerIds = form.playerIds.value.split('\n').map((id) => id.trim()).filter((id) => id);
+    const body = {changeType: form.changeType.value, playerIds};
+
+    const res = await api('POST', '/v1/roles/' + encodeURIComponent(form.roleId.value) + '/players', body, form.reason.value);
+    $('bulk-result-list').replaceChildren(...(res.results || []).map((result) =>
+        el('tr', {}, el('td', {textContent: result.playerId}), el('td', {textContent: result.status}))));
+    $('bulk-results').hidden = false;
+}
+
+function rolePath(playerId, roleId) {
+    return '/v1/players/' + encodeURIComponent(playerId) + '/roles/' + encodeURIComponent(roleId);
+}
+
+document.addEventListener('DOMContentLoaded', () => {
+    $('sign-in-form').addEventListener('submit', (e) => {
+        e.preventDefault();
+        sessionStorage.setItem('token', e.target.token.value);
+        sessionStorage.setItem('actor', e.target.actor.value.trim());
+        e.target.reset();
+
+        run(async () => {
+            await loadRoles();
+            showView('roles');
+        });
+    });
+
+    $('sign-out').addEventListener('click', signOut);
+    for (const button of document.querySelectorAll('nav [data-view]')) {
+        button.addEventListener('click', () => showView(button.dataset.view));
+    }
+
+    $('add-node').addEventListener('click', addNode);
+    $('new-node').addEventListener('keydown', (e) => {
+        if (e.key === 'Enter') {
+            e.preventDefault();
+            addNode();
+        }
+    });
+
+    $('role-form').addEventListener('submit', (e) => {
+        e.preventDefault();
+        run(() => saveRole(e.target));
+    });
+    $('delete-role').addEventListener('click', () => run(() => deleteRole($('role-form'))));
+    $('create-role-form').addEventListener('submit', (e) => {
+        e.preventDefault();
+        run(() => createRole(e.target));
+    });
+
+    let playerId = null;
+    $('player-form').addEventListener('submit', (e) => {
+        e.preventDefault();
+        playerId = e.target.playerId.value.trim();
+        run(() => loadPlayer(playerId));
+    });
+    $('grant-form').addEventListener('submit', (e) => {
+        e.preventDefault();
+        run(async () => {
+            await api('PUT', rolePath(playerId, e.target.roleId.value), undefined, e.target.reason.value);
+            e.target.reason.value = '';
+            await loadPlayer(playerId);
+        });
+    });
+
+    $('bulk-form').addEventListener('submit', (e) => {
+        e.preventDefault();
+        run(() => bulkUpdate(e.target));
+    });
+
+    if (session.token) {
+        run(async () => {
+            await loadRoles();
+            showView('roles');
+        });
+    } else {
+        showView('sign-in');
+    }
+});
@@ -4,13 +4,17 @@ import (
 	"context"
 	"go.uber.org/zap"
 	"permission-service/internal/config"
-	"permission-service/internal/kafka/notifier"
+	"permission-service/internal/kafka/consumer"
+	"permission-service/internal/messaging/notifier"
+	"permission-service/internal/metrics"
 	"permission-service/internal/repository"
 	"permission-service/internal/service"
+	"permission-service/internal/tracing"
+	"slices"
 	"sync"
 )
 
-func Run(cfg config.Config, logger *zap.SugaredLogger) {
+func Run(cfg config.Config, logger *zap.SugaredLogger, level zap.AtomicLevel) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	wg := &sync.WaitGroup{}
@@ -18,14 +22,64 @@ func Run(cfg config.Config, logger *zap.SugaredLogger) {
 	delayedCtx, repoCancel := context.WithCancel(ctx)
 	delayedWg := &sync.WaitGroup{}
 
-	repo, err := repository.NewMongoRepository(delayedCtx, logger, delayedWg, cfg.MongoDB)
+	// Flushed after everything else has shut down, so spans of the final requests are exported
+	if err := tracing.Init(delayedCtx, delayedWg, logger, cfg.Tracing); err != nil {
+		logger.Fatalw("failed to initialise tracing", "error", err)
+	}
+
+	repo, err := repository.NewMongoRepository(delayedCtx, logger, delayedWg, cfg.MongoDB, cfg.Bootstrap.DefaultRole.Id)
 	if err != nil {
 		logger.Fatalw("failed to create repository", "error", err)
 	}
+	repo = tracing.TraceRepository(metrics.InstrumentRepository(repo))
+
+	if err := service.Bootstrap(ctx, logger, cfg.Bootstrap, repo); err != nil {
+		logger.Fatalw("failed to bootstrap roles", "error", err)
+	}
+
+	err = config.WatchGlobalConfig(ctx, wg, logger, cfg, func(tunables config.Tunables) {
+		level.SetLevel(tunables.LogLevel)
+		repo.SetTimeouts(tunables.RepositoryTimeout, tunables.RepositoryBulkTimeout)
+	})
+	if err != nil {
+		logger.Fatalw("failed to watch config", "error", err)
+	}
+
+	// events receives every change applied by any replica, for streaming to watchers
+	events := notifier.NewMemoryNotifier()
+	notif := createNotifier(delayedCtx, delayedWg, logger, cfg, repo, events)
+
+	if cfg.MetricsPort != 0 {
+		metrics.RegisterNotifier(notif)
+		metrics.RunGaugeUpdater(ctx, wg, logger, repo)
+		metrics.RunServer(ctx, wg, logger, cfg.MetricsPort)
+	}
+
+	svc := service.NewPermissionService(logger, repo, notif)
+	roleSvc := service.NewRoleService(logger, repo, notif, cfg.Bootstrap.DefaultRole.Id)
+	saSvc := service.NewServiceAccountService(logger, repo)
+	watchSvc := service.NewWatchService(ctx, logger, repo, events)
+
+	if cfg.RoleSync.Path != "" {
+		if err := service.RunRoleSync(ctx, wg, logger, cfg.RoleSync, repo, svc); err != nil {
+			logger.Fatalw("failed to start role sync", "error", err)
+		}
+	}
+
+	if slices.Contains(cfg.Notifier.Backends, "kafka") {
+		consumer.NewKafkaConsumer(ctx, wg, logger, cfg.Kafka, svc)
+		consumer.NewKafkaEventListener(ctx, wg, logger, cfg.Kafka, events)
+	} else {
+		logger.Info("kafka notifier backend disabled, not consuming permission commands")
+	}
 
-	notif := notifier.NewKafkaNotifier(delayedCtx, delayedWg, logger, cfg.Kafka)
+	healthChecks := []service.HealthCheck{{Name: "mongodb", Required: true, Check: repo.Ping}}
+	if slices.Contains(cfg.Notifier.Backends, "kafka") {
+		// Permissions can still be read without kafka, so it doesn't take the replica out of rotation
+		healthChecks = append(healthChecks, service.HealthCheck{Name: "kafka", Check: notif.CheckHealth})
+	}
 
-	service.RunServices(ctx, logger, wg, cfg, repo, notif)
+	service.RunServices(ctx, logger, wg, cfg, repo, svc, roleSvc, saSvc, watchSvc, healthChecks...)
 
 	wg.Wait()
 	logger.Info("shutting down")
@@ -34,3 +88,32 @@ func Run(cfg config.Config, logger *zap.SugaredLogger) {
 	repoCancel()
 	delayedWg.Wait()
 }
+
+func createNotifier(ctx context.Context, wg *sync.WaitGroup, logger *zap.SugaredLogger, cfg config.Config,
+	repo repository.Repository, events notifier.MemoryNotifier) notifier.FanOutNotifier {
+
+	backends := make([]notifier.Backend, 0, len(cfg.Notifier.Backends))
+	for _, name := range cfg.Notifier.Backends {
+		var notif notifier.Notifier
+		switch name {
+		case "kafka":
+			notif = notifier.NewKafkaNotifier(ctx, wg, logger, cfg.Kafka, repo)
+		case "webhook":
+			notif = notifier.NewWebhookNotifier(ctx, wg, logger, cfg.Notifier.Webhook)
+		case "log":
+			notif = notifier.NewLogNotifier(logger)
+		default:
+			logger.Fatalw("unknown notifier backend", "backend", name)
+		}
+
+		backends = append(backends, notifier.Backend{Name: name, Notifier: notif})
+	}
+
+	// Without kafka, changes made by other replicas can't be seen, so watchers only get this replica's changes
+	if !slices.Contains(cfg.Notifier.Backends, "kafka") {
+		backends = append(backends, notifier.Backend{Name: "watch", Notifier: events})
+	}
+
+	logger.Infow("created notifier", "backends", cfg.Notifier.Backends)
+	return notifier.NewFanOutNotifier(backends...)
+}
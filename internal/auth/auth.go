@@ -0,0 +1,312 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	mongoDb "go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"permission-service/internal/config"
+	"permission-service/internal/repository"
+	"permission-service/internal/repository/model"
+	"strings"
+)
+
+const (
+	authorizationMetadataKey = "authorization"
+	bearerPrefix             = "Bearer "
+
+	wildcardMethod = "*"
+
+	// ServiceAccountTokenPrefix tells service account tokens apart from the static tokens of configured identities
+	ServiceAccountTokenPrefix = "psa_"
+)
+
+var (
+	ErrUnauthenticated  = status.Error(codes.Unauthenticated, "missing or invalid credentials")
+	ErrPermissionDenied = status.Error(codes.PermissionDenied, "identity is not allowed to call this method")
+
+	healthMethods = []string{healthpb.Health_Check_FullMethodName, healthpb.Health_Watch_FullMethodName}
+)
+
+// Identity is an authenticated caller of the API.
+type Identity struct {
+	Name string
+	// Subject is set when the identity holds roles itself, e.g. a service account
+	Subject *model.Subject
+
+	methods map[string]struct{}
+}
+
+// CanCall returns true if the identity is allowed to call the given gRPC method.
+func (i *Identity) CanCall(fullMethod string) bool {
+	return methodAllowed(i.methods, fullMethod)
+}
+
+type identityKey struct{}
+
+// IdentityFromContext returns the identity that made the request, if the request was authenticated.
+func IdentityFromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(*Identity)
+	return identity, ok
+}
+
+// ContextWithIdentity attaches an authenticated identity to the context.
+func ContextWithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+type Authenticator struct {
+	identities    []*identityEntry
+	publicMethods map[string]struct{}
+
+	// repo looks up service accounts by token. Service account tokens are rejected if nil.
+	repo repository.Repository
+}
+
+type identityEntry struct {
+	identity       *Identity
+	token          []byte
+	clientCertName string
+}
+
+func NewAuthenticator(cfg config.AuthConfig) (*Authenticator, error) {
+	a := &Authenticator{
+		identities:    make([]*identityEntry, 0, len(cfg.Identities)),
+		publicMethods: toSet(cfg.PublicMethods),
+	}
+
+	// Probes can't present credentials, and health reveals nothing about permissions
+	for _, method := range healthMethods {
+		a.publicMethods[method] = struct{}{}
+	}
+
+	for _, idCfg := range cfg.Identities {
+		if idCfg.Name == "" {
+			return nil, errors.New("identity name must not be empty")
+		}
+		if idCfg.Token == "" && idCfg.ClientCertName == "" {
+			return nil, fmt.Errorf("identity %s has neither a token nor a client certificate name", idCfg.Name)
+		}
+
+		entry := &identityEntry{
+			identity:       &Identity{Name: idCfg.Name, methods: toSet(idCfg.Methods)},
+			clientCertName: idCfg.ClientCertName,
+		}
+		if idCfg.Token != "" {
+			entry.token = []byte(idCfg.Token)
+		}
+
+		a.identities = append(a.identities, entry)
+	}
+
+	return a, nil
+}
+
+// WithServiceAccounts allows service accounts to authenticate with their API tokens.
+// Service accounts may call any method; what they can change is decided by their roles and nodes.
+func (a *Authenticator) WithServiceAccounts(repo repository.Repository) *Authenticator {
+	a.repo = repo
+	return a
+}
+
+// Authorize authenticates the caller and checks it may call fullMethod.
+// The returned context carries the identity if one was found, even for public methods.
+func (a *Authenticator) Authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	identity, err := a.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if identity != nil {
+		ctx = ContextWithIdentity(ctx, identity)
+	}
+
+	if methodAllowed(a.publicMethods, fullMethod) {
+		return ctx, nil
+	}
+
+	if identity == nil {
+		return nil, ErrUnauthenticated
+	}
+	if !identity.CanCall(fullMethod) {
+		return nil, ErrPermissionDenied
+	}
+
+	return ctx, nil
+}
+
+func (a *Authenticator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := a.Authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func (a *Authenticator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.Authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// AuthenticateToken returns the identity owning a bearer token, or nil if the token is unknown.
+func (a *Authenticator) AuthenticateToken(ctx context.Context, token string) (*Identity, error) {
+	if strings.HasPrefix(token, ServiceAccountTokenPrefix) {
+		return a.authenticateServiceAccount(ctx, token)
+	}
+
+	for _, entry := range a.identities {
+		if entry.token != nil && subtle.ConstantTimeCompare(entry.token, []byte(token)) == 1 {
+			return entry.identity, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (a *Authenticator) authenticateServiceAccount(ctx context.Context, token string) (*Identity, error) {
+	if a.repo == nil {
+		return nil, nil
+	}
+
+	// Looking up by hash means the comparison never sees the token itself
+	account, err := a.repo.GetServiceAccountByTokenHash(ctx, HashToken(token))
+	if err != nil {
+		if errors.Is(err, mongoDb.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, status.Error(codes.Internal, "failed to look up service account")
+	}
+
+	subject := model.ServiceAccountSubject(account.Id)
+	return &Identity{
+		Name:    subject.String(),
+		Subject: &subject,
+		methods: map[string]struct{}{wildcardMethod: {}},
+	}, nil
+}
+
+// GenerateServiceAccountToken creates a new random API token for a service account.
+func GenerateServiceAccountToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return ServiceAccountTokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the hex SHA-256 of a token, which is what is stored for service accounts.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// AuthenticateCertificate returns the identity matching a verified client certificate, or nil if there is none.
+func (a *Authenticator) AuthenticateCertificate(cert *x509.Certificate) *Identity {
+	names := append([]string{cert.Subject.CommonName}, cert.DNSNames...)
+
+	for _, entry := range a.identities {
+		if entry.clientCertName == "" {
+			continue
+		}
+		for _, name := range names {
+			if name == entry.clientCertName {
+				return entry.identity
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a *Authenticator) authenticate(ctx context.Context) (*Identity, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, value := range md.Get(authorizationMetadataKey) {
+			if token, ok := strings.CutPrefix(value, bearerPrefix); ok {
+				identity, err := a.AuthenticateToken(ctx, token)
+				if err != nil {
+					return nil, err
+				}
+				if identity != nil {
+					return identity, nil
+				}
+			}
+		}
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil, nil
+	}
+
+	// Only certificates that were verified against the client CA are trusted
+	for _, chain := range tlsInfo.State.VerifiedChains {
+		if len(chain) == 0 {
+			continue
+		}
+		if identity := a.AuthenticateCertificate(chain[0]); identity != nil {
+			return identity, nil
+		}
+	}
+
+	return nil, nil
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// methodAllowed matches a full gRPC method ("/package.Service/Method") against a set of
+// full methods, short method names and the wildcard.
+func methodAllowed(methods map[string]struct{}, fullMethod string) bool {
+	if _, ok := methods[wildcardMethod]; ok {
+		return true
+	}
+	if _, ok := methods[fullMethod]; ok {
+		return true
+	}
+
+	shortName := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	_, ok := methods[shortName]
+	return ok
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+
+	return set
+}
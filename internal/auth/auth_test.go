@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"permission-service/internal/config"
+	"permission-service/internal/repository"
+	"permission-service/internal/repository/model"
+	"strings"
+	"testing"
+)
+
+const (
+	getAllRolesMethod = "/emortal.grpc.permission.PermissionService/GetAllRoles"
+	updateRoleMethod  = "/emortal.grpc.permission.PermissionService/UpdateRole"
+)
+
+var testAuthConfig = config.AuthConfig{
+	Enabled: true,
+	Identities: []config.IdentityConfig{
+		{Name: "store", Token: "store-token", Methods: []string{"AddRoleToPlayer", "RemoveRoleFromPlayer"}},
+		{Name: "admin", ClientCertName: "admin.internal", Methods: []string{"*"}},
+		{Name: "dashboard", Token: "dashboard-token", Methods: []string{updateRoleMethod}},
+	},
+	PublicMethods: []string{"GetAllRoles"},
+}
+
+func tokenContext(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func certContext(cert *x509.Certificate) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}},
+	})
+}
+
+func TestAuthenticator_Authorize(t *testing.T) {
+	tests := []struct {
+		name string
+
+		ctx    context.Context
+		method string
+
+		wantIdentity string
+		wantCode     codes.Code
+	}{
+		{
+			name:     "public method without credentials",
+			ctx:      context.Background(),
+			method:   getAllRolesMethod,
+			wantCode: codes.OK,
+		},
+		{
+			name:         "public method keeps identity",
+			ctx:          tokenContext("store-token"),
+			method:       getAllRolesMethod,
+			wantIdentity: "store",
+			wantCode:     codes.OK,
+		},
+		{
+			name:     "health check without credentials",
+			ctx:      context.Background(),
+			method:   "/grpc.health.v1.Health/Check",
+			wantCode: codes.OK,
+		},
+		{
+			name:     "private method without credentials",
+			ctx:      context.Background(),
+			method:   updateRoleMethod,
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "unknown token",
+			ctx:      tokenContext("invalid"),
+			method:   updateRoleMethod,
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "token not allowed to call method",
+			ctx:      tokenContext("store-token"),
+			method:   updateRoleMethod,
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:         "token allowed by full method name",
+			ctx:          tokenContext("dashboard-token"),
+			method:       updateRoleMethod,
+			wantIdentity: "dashboard",
+			wantCode:     codes.OK,
+		},
+		{
+			name:         "client certificate by common name",
+			ctx:          certContext(&x509.Certificate{Subject: pkix.Name{CommonName: "admin.internal"}}),
+			method:       updateRoleMethod,
+			wantIdentity: "admin",
+			wantCode:     codes.OK,
+		},
+		{
+			name:         "client certificate by DNS SAN",
+			ctx:          certContext(&x509.Certificate{DNSNames: []string{"other", "admin.internal"}}),
+			method:       updateRoleMethod,
+			wantIdentity: "admin",
+			wantCode:     codes.OK,
+		},
+		{
+			name:     "unknown client certificate",
+			ctx:      certContext(&x509.Certificate{Subject: pkix.Name{CommonName: "unknown"}}),
+			method:   updateRoleMethod,
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	a, err := NewAuthenticator(testAuthConfig)
+	assert.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := a.Authorize(tt.ctx, tt.method)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if err != nil {
+				return
+			}
+
+			identity, ok := IdentityFromContext(ctx)
+			if tt.wantIdentity == "" {
+				assert.False(t, ok)
+			} else {
+				assert.True(t, ok)
+				assert.Equal(t, tt.wantIdentity, identity.Name)
+			}
+		})
+	}
+}
+
+func TestAuthenticator_UnaryServerInterceptor(t *testing.T) {
+	a, err := NewAuthenticator(testAuthConfig)
+	assert.NoError(t, err)
+
+	interceptor := a.UnaryServerInterceptor()
+	handler := func(ctx context.Context, req any) (any, error) {
+		identity, _ := IdentityFromContext(ctx)
+		return identity.Name, nil
+	}
+
+	res, err := interceptor(tokenContext("dashboard-token"), nil, &grpc.UnaryServerInfo{FullMethod: updateRoleMethod}, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, "dashboard", res)
+
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: updateRoleMethod}, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestNewAuthenticator_InvalidIdentity(t *testing.T) {
+	_, err := NewAuthenticator(config.AuthConfig{Identities: []config.IdentityConfig{{Name: "no-credentials"}}})
+	assert.Error(t, err)
+
+	_, err = NewAuthenticator(config.AuthConfig{Identities: []config.IdentityConfig{{Token: "no-name"}}})
+	assert.Error(t, err)
+}
+
+func TestAuthenticator_ServiceAccountToken(t *testing.T) {
+	token, err := GenerateServiceAccountToken()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, ServiceAccountTokenPrefix))
+
+	mockCntrl := gomock.NewController(t)
+	mockRepo := repository.NewMockRepository(mockCntrl)
+	mockRepo.EXPECT().GetServiceAccountByTokenHash(gomock.Any(), HashToken(token)).Return(&model.ServiceAccount{Id: "discord-bot"}, nil)
+	mockRepo.EXPECT().GetServiceAccountByTokenHash(gomock.Any(), gomock.Any()).Return(nil, mongo.ErrNoDocuments)
+
+	a, err := NewAuthenticator(testAuthConfig)
+	assert.NoError(t, err)
+	a.WithServiceAccounts(mockRepo)
+
+	ctx, err := a.Authorize(tokenContext(token), updateRoleMethod)
+	assert.NoError(t, err)
+
+	identity, ok := IdentityFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "service:discord-bot", identity.Name)
+	assert.Equal(t, model.ServiceAccountSubject("discord-bot"), *identity.Subject)
+
+	_, err = a.Authorize(tokenContext(ServiceAccountTokenPrefix+"unknown"), updateRoleMethod)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,269 @@
+package backup
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"io"
+	"os"
+	"time"
+)
+
+// FormatVersion is the version of the archive layout. Archives of other versions can't be restored.
+const FormatVersion = 1
+
+const (
+	manifestFile = "manifest.json"
+	// maxDocumentSize is the largest document MongoDB stores, with room for its extended JSON form
+	maxDocumentSize = 32 * 1024 * 1024
+)
+
+// Manifest describes the contents of an archive. It's written last, once the checksums are known.
+type Manifest struct {
+	Version     int                  `json:"version"`
+	CreatedAt   time.Time            `json:"createdAt"`
+	Collections []CollectionManifest `json:"collections"`
+}
+
+type CollectionManifest struct {
+	Name string `json:"name"`
+	// File holds a document per line as canonical extended JSON, so BSON types (e.g. UUIDs) survive the round trip
+	File      string `json:"file"`
+	Documents int64  `json:"documents"`
+	// SHA256 is the hex SHA-256 of File's uncompressed content
+	SHA256 string `json:"sha256"`
+}
+
+func (m *Manifest) collection(name string) (CollectionManifest, bool) {
+	for _, c := range m.Collections {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return CollectionManifest{}, false
+}
+
+// Writer writes a zip archive of collections.
+type Writer struct {
+	zw       *zip.Writer
+	manifest Manifest
+}
+
+func NewWriter(w io.Writer, createdAt time.Time) *Writer {
+	return &Writer{
+		zw:       zip.NewWriter(w),
+		manifest: Manifest{Version: FormatVersion, CreatedAt: createdAt.UTC()},
+	}
+}
+
+// WriteCollection adds a collection to the archive, with the documents export passes to its callback.
+func (w *Writer) WriteCollection(name string, export func(fn func(doc bson.Raw) error) error) error {
+	file := "collections/" + name + ".jsonl"
+	f, err := w.zw.Create(file)
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.New()
+	out := bufio.NewWriter(io.MultiWriter(f, hash))
+	count := int64(0)
+
+	err = export(func(doc bson.Raw) error {
+		line, err := bson.MarshalExtJSON(doc, true, false)
+		if err != nil {
+			return fmt.Errorf("failed to encode document of %s: %w", name, err)
+		}
+
+		count++
+		if _, err := out.Write(line); err != nil {
+			return err
+		}
+		return out.WriteByte('\n')
+	})
+	if err != nil {
+		return err
+	}
+	if err := out.Flush(); err != nil {
+		return err
+	}
+
+	w.manifest.Collections = append(w.manifest.Collections, CollectionManifest{
+		Name:      name,
+		File:      file,
+		Documents: count,
+		SHA256:    hex.EncodeToString(hash.Sum(nil)),
+	})
+	return nil
+}
+
+// Close writes the manifest and finishes the archive. It returns the manifest written.
+func (w *Writer) Close() (Manifest, error) {
+	f, err := w.zw.Create(manifestFile)
+	if err != nil {
+		return w.manifest, err
+	}
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(w.manifest); err != nil {
+		return w.manifest, err
+	}
+
+	return w.manifest, w.zw.Close()
+}
+
+// Archive is an archive opened for restoring.
+type Archive struct {
+	Manifest Manifest
+
+	files  map[string]*zip.File
+	closer io.Closer
+}
+
+// Open opens the archive at path, reading its manifest.
+func Open(path string) (*Archive, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	archive, err := Read(f, info.Size())
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("invalid archive %s: %w", path, err)
+	}
+
+	archive.closer = f
+	return archive, nil
+}
+
+// Read reads the manifest of an archive, checking every collection it lists is present.
+func Read(r io.ReaderAt, size int64) (*Archive, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &Archive{files: make(map[string]*zip.File, len(zr.File))}
+	for _, f := range zr.File {
+		archive.files[f.Name] = f
+	}
+
+	manifest, ok := archive.files[manifestFile]
+	if !ok {
+		return nil, errors.New("missing manifest")
+	}
+
+	data, err := readFile(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, &archive.Manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	if archive.Manifest.Version != FormatVersion {
+		return nil, fmt.Errorf("unsupported archive version %d, expected %d", archive.Manifest.Version, FormatVersion)
+	}
+
+	names := make(map[string]bool, len(archive.Manifest.Collections))
+	for _, c := range archive.Manifest.Collections {
+		if c.Name == "" || names[c.Name] {
+			return nil, fmt.Errorf("invalid or duplicate collection %q", c.Name)
+		}
+		names[c.Name] = true
+
+		if _, ok := archive.files[c.File]; !ok {
+			return nil, fmt.Errorf("missing file %s of collection %s", c.File, c.Name)
+		}
+	}
+
+	return archive, nil
+}
+
+func (a *Archive) Close() error {
+	if a.closer == nil {
+		return nil
+	}
+	return a.closer.Close()
+}
+
+// Verify checks the checksum and document count of every collection, and that every document can be decoded.
+func (a *Archive) Verify() error {
+	errs := make([]error, 0)
+	for _, c := range a.Manifest.Collections {
+		if err := a.Documents(c.Name, func(bson.Raw) error { return nil }); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Documents calls fn with every document of the collection. It fails after the last document if the
+// collection doesn't match its checksum or document count, so callers relying on the content should Verify first.
+func (a *Archive) Documents(collection string, fn func(doc bson.Raw) error) error {
+	c, ok := a.Manifest.collection(collection)
+	if !ok {
+		return fmt.Errorf("collection %s is not in the archive", collection)
+	}
+
+	rc, err := a.files[c.File].Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	hash := sha256.New()
+	scanner := bufio.NewScanner(io.TeeReader(rc, hash))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxDocumentSize)
+
+	count := int64(0)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		count++
+
+		var doc bson.Raw
+		if err := bson.UnmarshalExtJSON(line, true, &doc); err != nil {
+			return fmt.Errorf("invalid document %d of %s: %w", count, collection, err)
+		}
+		if err := fn(doc); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", collection, err)
+	}
+
+	if sum := hex.EncodeToString(hash.Sum(nil)); sum != c.SHA256 {
+		return fmt.Errorf("checksum of %s doesn't match the manifest", collection)
+	}
+	if count != c.Documents {
+		return fmt.Errorf("%s has %d documents, the manifest lists %d", collection, count, c.Documents)
+	}
+	return nil
+}
+
+func readFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return io.ReadAll(rc)
+}
@@ -0,0 +1,35 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"io"
+	"permission-service/internal/repository"
+	"time"
+)
+
+const (
+	rolesCollection   = "roles"
+	playersCollection = "players"
+)
+
+// Backup writes every collection of the database to w as an archive, returning its manifest.
+func Backup(ctx context.Context, store repository.CollectionStore, w io.Writer, createdAt time.Time) (Manifest, error) {
+	names, err := store.CollectionNames(ctx)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to list collections: %w", err)
+	}
+
+	aw := NewWriter(w, createdAt)
+	for _, name := range names {
+		err := aw.WriteCollection(name, func(fn func(doc bson.Raw) error) error {
+			return store.ExportCollection(ctx, name, fn)
+		})
+		if err != nil {
+			return Manifest{}, fmt.Errorf("failed to back up %s: %w", name, err)
+		}
+	}
+
+	return aw.Close()
+}
@@ -0,0 +1,268 @@
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"github.com/emortalmc/proto-specs/gen/go/message/permission"
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"permission-service/internal/messaging/notifier"
+	"permission-service/internal/repository/model"
+	"sort"
+	"testing"
+	"time"
+)
+
+// fakeStore holds collections in memory
+type fakeStore struct {
+	collections map[string][]bson.Raw
+}
+
+func (s *fakeStore) CollectionNames(context.Context) ([]string, error) {
+	names := make([]string, 0, len(s.collections))
+	for name := range s.collections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *fakeStore) CountDocuments(_ context.Context, collection string) (int64, error) {
+	return int64(len(s.collections[collection])), nil
+}
+
+func (s *fakeStore) ExportCollection(_ context.Context, collection string, fn func(doc bson.Raw) error) error {
+	for _, doc := range s.collections[collection] {
+		if err := fn(doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fakeStore) ReplaceCollection(_ context.Context, collection string, docs func(yield func(doc bson.Raw) error) error) error {
+	replaced := make([]bson.Raw, 0)
+	if err := docs(func(doc bson.Raw) error {
+		replaced = append(replaced, doc)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	s.collections[collection] = replaced
+	return nil
+}
+
+func mustMarshal(t *testing.T, v any) bson.Raw {
+	doc, err := bson.Marshal(v)
+	require.NoError(t, err)
+	return doc
+}
+
+func playerDoc(t *testing.T, id uuid.UUID, roles ...string) bson.Raw {
+	return mustMarshal(t, bson.M{"_id": primitive.Binary{Subtype: bson.TypeBinaryUUID, Data: id[:]}, "roles": roles})
+}
+
+func newTestStore(t *testing.T) *fakeStore {
+	return &fakeStore{collections: map[string][]bson.Raw{
+		"roles": {
+			mustMarshal(t, &model.Role{Id: model.DefaultRoleId, Permissions: []model.PermissionNode{}}),
+			mustMarshal(t, &model.Role{Id: "vip", Priority: 10, Permissions: []model.PermissionNode{
+				{Node: "chat.colour", State: protoModel.PermissionNode_ALLOW},
+			}}),
+		},
+		"players": {
+			playerDoc(t, uuid.MustParse("069a79f4-44e9-4726-a5be-fca90e38aaf5"), model.DefaultRoleId, "vip"),
+			playerDoc(t, uuid.MustParse("61699b2e-d327-4a01-9f1e-0ea8c3f06bc6"), model.DefaultRoleId, "deleted"),
+		},
+		"serviceAccounts": {mustMarshal(t, bson.M{"_id": "discord-bot", "tokenHash": "abc"})},
+	}}
+}
+
+func backUp(t *testing.T, store *fakeStore) *bytes.Reader {
+	buf := &bytes.Buffer{}
+	manifest, err := Backup(context.Background(), store, buf, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	assert.Equal(t, FormatVersion, manifest.Version)
+	require.Len(t, manifest.Collections, 3)
+	assert.Equal(t, CollectionManifest{Name: "players", File: "collections/players.jsonl", Documents: 2, SHA256: manifest.Collections[0].SHA256}, manifest.Collections[0])
+
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestBackupRestore(t *testing.T) {
+	source := newTestStore(t)
+	data := backUp(t, source)
+
+	archive, err := Read(data, data.Size())
+	require.NoError(t, err)
+
+	contents, err := Validate(archive, model.DefaultRoleId)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1 players hold role deleted, which isn't in the archive"}, contents.Warnings)
+
+	// The target has diverged from the backup since it was taken
+	target := &fakeStore{collections: map[string][]bson.Raw{
+		"roles": {
+			mustMarshal(t, &model.Role{Id: model.DefaultRoleId, Permissions: []model.PermissionNode{}}),
+			mustMarshal(t, &model.Role{Id: "vip", Priority: 20, Permissions: []model.PermissionNode{}}),
+			mustMarshal(t, &model.Role{Id: "helper", Priority: 5, Permissions: []model.PermissionNode{}}),
+		},
+		"players":  {},
+		"sessions": {mustMarshal(t, bson.M{"_id": "unrelated"})},
+	}}
+
+	plan, err := NewPlan(context.Background(), target, archive, contents)
+	require.NoError(t, err)
+	assert.Equal(t, []CollectionChange{
+		{Name: "players", Current: 0, Restored: 2},
+		{Name: "roles", Current: 3, Restored: 2},
+		{Name: "serviceAccounts", Current: 0, Restored: 1},
+	}, plan.Collections)
+	assert.Equal(t, []string{"sessions"}, plan.Untouched)
+	assert.Empty(t, plan.CreatedRoles)
+	assert.Equal(t, []string{"vip"}, plan.ChangedRoles)
+	assert.Equal(t, []string{"helper"}, plan.DeletedRoles)
+
+	require.NoError(t, Restore(context.Background(), target, archive))
+	for name, docs := range source.collections {
+		assert.Equal(t, docs, target.collections[name], name)
+	}
+	assert.Len(t, target.collections["sessions"], 1)
+
+	mockNotif := notifier.NewMockNotifier(gomock.NewController(t))
+	meta := notifier.ChangeMeta{Actor: "restore"}
+	mockNotif.EXPECT().RoleUpdate(gomock.Any(), gomock.Any(), gomock.Any(), permission.RoleUpdateMessage_MODIFY, meta).Times(2).Return(nil)
+	mockNotif.EXPECT().RoleUpdate(gomock.Any(), gomock.Nil(), gomock.Any(), permission.RoleUpdateMessage_DELETE, meta).Return(nil)
+
+	require.NoError(t, Publish(context.Background(), mockNotif, plan, meta))
+}
+
+// rewrite copies the archive, passing the content of every file through edit
+func rewrite(t *testing.T, data *bytes.Reader, edit func(name string, content []byte) []byte) *bytes.Reader {
+	zr, err := zip.NewReader(data, data.Size())
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for _, f := range zr.File {
+		content, err := readFile(f)
+		require.NoError(t, err)
+
+		w, err := zw.Create(f.Name)
+		require.NoError(t, err)
+		_, err = w.Write(edit(f.Name, content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestValidate_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		store   func(s *fakeStore)
+		edit    func(name string, content []byte) []byte
+		wantErr string
+	}{
+		{
+			name: "tampered collection",
+			edit: func(name string, content []byte) []byte {
+				if name == "collections/roles.jsonl" {
+					return bytes.Replace(content, []byte("chat.colour"), []byte("chat.format"), 1)
+				}
+				return content
+			},
+			wantErr: "checksum of roles doesn't match the manifest",
+		},
+		{
+			name: "missing default role",
+			store: func(s *fakeStore) {
+				s.collections["roles"] = s.collections["roles"][1:]
+			},
+			wantErr: "archive has no default role",
+		},
+		{
+			name: "role without id",
+			store: func(s *fakeStore) {
+				s.collections["roles"] = append(s.collections["roles"], mustMarshal(t, &model.Role{}))
+			},
+			wantErr: `invalid role "": id must not be empty`,
+		},
+		{
+			name: "invalid player id",
+			store: func(s *fakeStore) {
+				s.collections["players"] = append(s.collections["players"], mustMarshal(t, bson.M{"_id": "notch"}))
+			},
+			wantErr: `invalid player "notch": id is not a UUID`,
+		},
+		{
+			name: "missing collection",
+			store: func(s *fakeStore) {
+				delete(s.collections, "players")
+			},
+			wantErr: "archive has no players collection",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore(t)
+			if tt.store != nil {
+				tt.store(store)
+			}
+
+			buf := &bytes.Buffer{}
+			_, err := Backup(context.Background(), store, buf, time.Now())
+			require.NoError(t, err)
+
+			data := bytes.NewReader(buf.Bytes())
+			if tt.edit != nil {
+				data = rewrite(t, data, tt.edit)
+			}
+
+			archive, err := Read(data, data.Size())
+			require.NoError(t, err)
+
+			_, err = Validate(archive, model.DefaultRoleId)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidate_LegacyRole(t *testing.T) {
+	// Roles created before the current id and node rules restore as they are
+	store := newTestStore(t)
+	store.collections["roles"] = append(store.collections["roles"], mustMarshal(t, &model.Role{Id: "Legacy Role", Permissions: []model.PermissionNode{
+		{Node: "*.command", State: protoModel.PermissionNode_ALLOW},
+	}}))
+	data := backUp(t, store)
+
+	archive, err := Read(data, data.Size())
+	require.NoError(t, err)
+
+	contents, err := Validate(archive, model.DefaultRoleId)
+	require.NoError(t, err)
+	assert.Len(t, contents.Roles, 3)
+}
+
+func TestRead_UnsupportedVersion(t *testing.T) {
+	data := rewrite(t, backUp(t, newTestStore(t)), func(name string, content []byte) []byte {
+		if name == manifestFile {
+			return bytes.Replace(content, []byte(`"version": 1`), []byte(`"version": 2`), 1)
+		}
+		return content
+	})
+
+	_, err := Read(data, data.Size())
+	assert.EqualError(t, err, "unsupported archive version 2, expected 1")
+}
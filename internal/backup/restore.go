@@ -0,0 +1,255 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/emortalmc/proto-specs/gen/go/message/permission"
+	"go.mongodb.org/mongo-driver/bson"
+	"io"
+	"permission-service/internal/messaging/notifier"
+	"permission-service/internal/repository"
+	"permission-service/internal/repository/model"
+	"slices"
+	"sort"
+)
+
+// Contents is what Validate found in an archive.
+type Contents struct {
+	Roles []*model.Role
+	// Warnings don't prevent a restore, e.g. players holding roles that aren't in the archive
+	Warnings []string
+}
+
+// Validate verifies the archive's checksums, then checks it holds a valid roles and players collection, including
+// the default role. Other collections are restored as they are.
+func Validate(a *Archive, defaultRoleId string) (*Contents, error) {
+	if err := a.Verify(); err != nil {
+		return nil, err
+	}
+
+	for _, name := range []string{rolesCollection, playersCollection} {
+		if _, ok := a.Manifest.collection(name); !ok {
+			return nil, fmt.Errorf("archive has no %s collection", name)
+		}
+	}
+
+	contents := &Contents{}
+	roleIds := make(map[string]bool)
+
+	err := a.Documents(rolesCollection, func(doc bson.Raw) error {
+		role := &model.Role{}
+		if err := bson.Unmarshal(doc, role); err != nil {
+			return fmt.Errorf("invalid role %s: %w", doc.Lookup("_id"), err)
+		}
+		// Only the structure is checked, as roles created before the current validation rules must still restore
+		if role.Id == "" {
+			return fmt.Errorf("invalid role %s: id must not be empty", doc.Lookup("_id"))
+		}
+
+		roleIds[role.Id] = true
+		contents.Roles = append(contents.Roles, role)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !roleIds[defaultRoleId] {
+		return nil, fmt.Errorf("archive has no %s role", defaultRoleId)
+	}
+
+	unknownRoles := make(map[string]int)
+	err = a.Documents(playersCollection, func(doc bson.Raw) error {
+		var player struct {
+			Id    bson.RawValue `bson:"_id"`
+			Roles []string      `bson:"roles"`
+		}
+		if err := bson.Unmarshal(doc, &player); err != nil {
+			return fmt.Errorf("invalid player %s: %w", doc.Lookup("_id"), err)
+		}
+		if subtype, data, ok := player.Id.BinaryOK(); !ok || subtype != bson.TypeBinaryUUID || len(data) != 16 {
+			return fmt.Errorf("invalid player %s: id is not a UUID", doc.Lookup("_id"))
+		}
+
+		for _, roleId := range player.Roles {
+			if !roleIds[roleId] {
+				unknownRoles[roleId]++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, roleId := range sortedKeys(unknownRoles) {
+		contents.Warnings = append(contents.Warnings, fmt.Sprintf("%d players hold role %s, which isn't in the archive", unknownRoles[roleId], roleId))
+	}
+
+	return contents, nil
+}
+
+// CollectionChange is how a restore changes a collection.
+type CollectionChange struct {
+	Name    string
+	Current int64
+	// Restored is the number of documents the collection will hold
+	Restored int64
+}
+
+// Plan is what restoring an archive would change.
+type Plan struct {
+	Collections []CollectionChange
+	// Untouched are collections of the database that aren't in the archive, which are left as they are
+	Untouched []string
+
+	CreatedRoles []string
+	ChangedRoles []string
+	DeletedRoles []string
+
+	Warnings []string
+
+	current  []*model.Role
+	restored []*model.Role
+}
+
+// NewPlan compares the database with a validated archive.
+func NewPlan(ctx context.Context, store repository.CollectionStore, a *Archive, contents *Contents) (*Plan, error) {
+	plan := &Plan{Warnings: contents.Warnings, restored: contents.Roles}
+
+	names, err := store.CollectionNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+
+	for _, c := range a.Manifest.Collections {
+		current := int64(0)
+		if slices.Contains(names, c.Name) {
+			if current, err = store.CountDocuments(ctx, c.Name); err != nil {
+				return nil, fmt.Errorf("failed to count %s: %w", c.Name, err)
+			}
+		}
+		plan.Collections = append(plan.Collections, CollectionChange{Name: c.Name, Current: current, Restored: c.Documents})
+	}
+	for _, name := range names {
+		if _, ok := a.Manifest.collection(name); !ok {
+			plan.Untouched = append(plan.Untouched, name)
+		}
+	}
+
+	err = store.ExportCollection(ctx, rolesCollection, func(doc bson.Raw) error {
+		role := &model.Role{}
+		if err := bson.Unmarshal(doc, role); err != nil {
+			return fmt.Errorf("invalid role %s: %w", doc.Lookup("_id"), err)
+		}
+		plan.current = append(plan.current, role)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current roles: %w", err)
+	}
+
+	currentById := rolesById(plan.current)
+	restoredById := rolesById(plan.restored)
+	for _, role := range plan.restored {
+		previous, ok := currentById[role.Id]
+		switch {
+		case !ok:
+			plan.CreatedRoles = append(plan.CreatedRoles, role.Id)
+		case !model.DiffRoles(previous, role).IsEmpty():
+			plan.ChangedRoles = append(plan.ChangedRoles, role.Id)
+		}
+	}
+	for _, role := range plan.current {
+		if _, ok := restoredById[role.Id]; !ok {
+			plan.DeletedRoles = append(plan.DeletedRoles, role.Id)
+		}
+	}
+
+	return plan, nil
+}
+
+func (p *Plan) Print(w io.Writer) {
+	for _, c := range p.Collections {
+		fmt.Fprintf(w, "%s: %d documents, %d after restoring\n", c.Name, c.Current, c.Restored)
+	}
+	for _, name := range p.Untouched {
+		fmt.Fprintf(w, "%s: not in the archive, left as is\n", name)
+	}
+
+	for _, id := range p.CreatedRoles {
+		fmt.Fprintf(w, "+ role %s\n", id)
+	}
+	for _, id := range p.ChangedRoles {
+		fmt.Fprintf(w, "~ role %s\n", id)
+	}
+	for _, id := range p.DeletedRoles {
+		fmt.Fprintf(w, "- role %s\n", id)
+	}
+
+	for _, warning := range p.Warnings {
+		fmt.Fprintf(w, "warning: %s\n", warning)
+	}
+}
+
+// Restore replaces every collection in the archive with its backed up documents. Each collection is replaced
+// in one step, but collections are replaced one after another, so the service should be stopped first.
+func Restore(ctx context.Context, store repository.CollectionStore, a *Archive) error {
+	for _, c := range a.Manifest.Collections {
+		err := store.ReplaceCollection(ctx, c.Name, func(yield func(doc bson.Raw) error) error {
+			return a.Documents(c.Name, yield)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to restore %s: %w", c.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Publish notifies of the state of every role after a restore, so caches resync. Player role changes aren't
+// published, as a restore can change any number of players.
+func Publish(ctx context.Context, notif notifier.Notifier, plan *Plan, meta notifier.ChangeMeta) error {
+	currentById := rolesById(plan.current)
+	restoredById := rolesById(plan.restored)
+
+	errs := make([]error, 0)
+	for _, role := range plan.restored {
+		previous, changeType := currentById[role.Id], permission.RoleUpdateMessage_MODIFY
+		if previous == nil {
+			changeType = permission.RoleUpdateMessage_CREATE
+		}
+
+		if err := notif.RoleUpdate(ctx, previous, role, changeType, meta); err != nil {
+			errs = append(errs, fmt.Errorf("role %s: %w", role.Id, err))
+		}
+	}
+	for _, role := range plan.current {
+		if _, ok := restoredById[role.Id]; ok {
+			continue
+		}
+		if err := notif.RoleUpdate(ctx, nil, role, permission.RoleUpdateMessage_DELETE, meta); err != nil {
+			errs = append(errs, fmt.Errorf("role %s: %w", role.Id, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func rolesById(roles []*model.Role) map[string]*model.Role {
+	byId := make(map[string]*model.Role, len(roles))
+	for _, role := range roles {
+		byId[role.Id] = role
+	}
+	return byId
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
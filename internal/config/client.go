@@ -0,0 +1,115 @@
+package config
+
+import (
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"permission-service/internal/utils/runtime"
+	"strings"
+	"time"
+)
+
+const (
+	clientEnvPrefix = "permctl"
+
+	clientAddressFlag       = "address"
+	clientTokenFlag         = "token"
+	clientTLSFlag           = "tls"
+	clientTLSCAFileFlag     = "tls-ca-file"
+	clientTLSCertFileFlag   = "tls-cert-file"
+	clientTLSKeyFileFlag    = "tls-key-file"
+	clientTLSServerNameFlag = "tls-server-name"
+	clientActorFlag         = "actor"
+	clientReasonFlag        = "reason"
+	clientOutputFlag        = "output"
+	clientTimeoutFlag       = "timeout"
+)
+
+// ClientConfig is the configuration of permctl, the admin command-line tool.
+type ClientConfig struct {
+	// Address is the host:port of the service's gRPC listener
+	Address string
+	// Token is sent as a bearer token in the "authorization" metadata
+	Token string
+
+	TLS ClientTLSConfig
+
+	// Actor and Reason are recorded against every change made (x-actor-id and x-change-reason metadata)
+	Actor  string
+	Reason string
+
+	// Output is the format results are printed in: table or json
+	Output string
+	// Timeout limits each RPC, a command may make several
+	Timeout time.Duration
+}
+
+type ClientTLSConfig struct {
+	Enabled bool
+	// CAFile verifies the server certificate. The system roots are used if empty.
+	CAFile string
+	// CertFile and KeyFile are the client certificate used for mTLS, if set
+	CertFile   string
+	KeyFile    string
+	ServerName string
+}
+
+// LoadClientConfig parses the permctl flags, falling back to PERMCTL_ prefixed environment variables
+// (e.g. PERMCTL_TOKEN). Flags of individual commands must be defined on pflag.CommandLine before calling it.
+func LoadClientConfig() ClientConfig {
+	viper.SetDefault(clientAddressFlag, "localhost:10010")
+	viper.SetDefault(clientTokenFlag, "")
+	viper.SetDefault(clientTLSFlag, false)
+	viper.SetDefault(clientTLSCAFileFlag, "")
+	viper.SetDefault(clientTLSCertFileFlag, "")
+	viper.SetDefault(clientTLSKeyFileFlag, "")
+	viper.SetDefault(clientTLSServerNameFlag, "")
+	viper.SetDefault(clientActorFlag, "")
+	viper.SetDefault(clientReasonFlag, "")
+	viper.SetDefault(clientOutputFlag, "table")
+	viper.SetDefault(clientTimeoutFlag, 10*time.Second)
+
+	pflag.String(clientAddressFlag, viper.GetString(clientAddressFlag), "Permission service gRPC address")
+	pflag.String(clientTokenFlag, viper.GetString(clientTokenFlag), "Bearer token (identity or service account token)")
+	pflag.Bool(clientTLSFlag, viper.GetBool(clientTLSFlag), "Connect with TLS")
+	pflag.String(clientTLSCAFileFlag, viper.GetString(clientTLSCAFileFlag), "CA file used to verify the server certificate")
+	pflag.String(clientTLSCertFileFlag, viper.GetString(clientTLSCertFileFlag), "Client certificate file for mTLS")
+	pflag.String(clientTLSKeyFileFlag, viper.GetString(clientTLSKeyFileFlag), "Client key file for mTLS")
+	pflag.String(clientTLSServerNameFlag, viper.GetString(clientTLSServerNameFlag), "Server name the certificate is verified against, if not the address host")
+	pflag.String(clientActorFlag, viper.GetString(clientActorFlag), "Player UUID or service:<id> changes are made on behalf of")
+	pflag.String(clientReasonFlag, viper.GetString(clientReasonFlag), "Reason recorded with changes")
+	pflag.StringP(clientOutputFlag, "o", viper.GetString(clientOutputFlag), "Output format (table, json)")
+	pflag.Duration(clientTimeoutFlag, viper.GetDuration(clientTimeoutFlag), "Timeout of each request")
+	pflag.Parse()
+
+	// Bind the viper flags to environment variables
+	viper.SetEnvPrefix(clientEnvPrefix)
+	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	runtime.Must(viper.BindPFlags(pflag.CommandLine))
+	runtime.Must(viper.BindEnv(clientAddressFlag))
+	runtime.Must(viper.BindEnv(clientTokenFlag))
+	runtime.Must(viper.BindEnv(clientTLSFlag))
+	runtime.Must(viper.BindEnv(clientTLSCAFileFlag))
+	runtime.Must(viper.BindEnv(clientTLSCertFileFlag))
+	runtime.Must(viper.BindEnv(clientTLSKeyFileFlag))
+	runtime.Must(viper.BindEnv(clientTLSServerNameFlag))
+	runtime.Must(viper.BindEnv(clientActorFlag))
+	runtime.Must(viper.BindEnv(clientReasonFlag))
+	runtime.Must(viper.BindEnv(clientOutputFlag))
+	runtime.Must(viper.BindEnv(clientTimeoutFlag))
+
+	return ClientConfig{
+		Address: viper.GetString(clientAddressFlag),
+		Token:   viper.GetString(clientTokenFlag),
+		TLS: ClientTLSConfig{
+			Enabled:    viper.GetBool(clientTLSFlag),
+			CAFile:     viper.GetString(clientTLSCAFileFlag),
+			CertFile:   viper.GetString(clientTLSCertFileFlag),
+			KeyFile:    viper.GetString(clientTLSKeyFileFlag),
+			ServerName: viper.GetString(clientTLSServerNameFlag),
+		},
+		Actor:   viper.GetString(clientActorFlag),
+		Reason:  viper.GetString(clientReasonFlag),
+		Output:  viper.GetString(clientOutputFlag),
+		Timeout: viper.GetDuration(clientTimeoutFlag),
+	}
+}
@@ -1,27 +1,166 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"net/url"
+	"os"
 	"permission-service/internal/utils/runtime"
 	"strings"
+	"time"
 )
 
 const (
-	kafkaHostFlag   = "kafka-host"
-	kafkaPortFlag   = "kafka-port"
-	mongoDBURIFlag  = "mongodb-uri"
-	developmentFlag = "development"
-	grpcPortFlag    = "port"
+	// configFileFlag is the YAML/JSON/TOML config file. Only flags, environment variables and defaults are used if not set.
+	configFileFlag = "config"
+	configFileEnv  = "CONFIG_FILE"
+
+	kafkaHostKey   = "kafka.host"
+	kafkaPortKey   = "kafka.port"
+	mongoDBURIKey  = "mongodb.uri"
+	developmentKey = "development"
+	logLevelKey    = "log.level"
+	grpcPortKey    = "grpc.port"
+	metricsPortKey = "metrics.port"
+
+	mongoDBTimeoutKey     = "mongodb.timeout"
+	mongoDBBulkTimeoutKey = "mongodb.bulk-timeout"
+
+	httpPortKey        = "http.port"
+	httpCORSOriginsKey = "http.cors-origins"
+	httpAdminUIKey     = "http.admin-ui"
+
+	notifierBackendsKey    = "notifier.backends"
+	webhookEndpointsKey    = "notifier.webhook.endpoints"
+	webhookMaxRetriesKey   = "notifier.webhook.max-retries"
+	webhookRetryBackoffKey = "notifier.webhook.retry-backoff"
+	webhookTimeoutKey      = "notifier.webhook.timeout"
+
+	authEnabledKey       = "auth.enabled"
+	authIdentitiesKey    = "auth.identities"
+	authPublicMethodsKey = "auth.public-methods"
+	authPlayerPermsKey   = "auth.player-permissions"
+	tlsCertFileKey       = "tls.cert-file"
+	tlsKeyFileKey        = "tls.key-file"
+	tlsClientCAFileKey   = "tls.client-ca-file"
+
+	tracingExporterKey     = "tracing.exporter"
+	tracingFileKey         = "tracing.file"
+	tracingOTLPEndpointKey = "tracing.otlp-endpoint"
+	tracingOTLPInsecureKey = "tracing.otlp-insecure"
+	tracingSampleRatioKey  = "tracing.sample-ratio"
+
+	roleSyncPathKey     = "role-sync.path"
+	roleSyncIntervalKey = "role-sync.interval"
+
+	bootstrapDefaultRoleKey            = "bootstrap.default-role"
+	bootstrapDefaultRoleIdKey          = bootstrapDefaultRoleKey + ".id"
+	bootstrapDefaultRoleDisplayNameKey = bootstrapDefaultRoleKey + ".display-name"
+	bootstrapDefaultRoleAllowKey       = bootstrapDefaultRoleKey + ".allow"
+	bootstrapDefaultRoleDenyKey        = bootstrapDefaultRoleKey + ".deny"
+	bootstrapRolesKey                  = "bootstrap.roles"
 )
 
+// option is a config key. Every key can be set in the config file (nested, e.g. kafka: {host: ...}), by a flag
+// (--kafka-host) and by an environment variable (KAFKA_HOST), which take precedence in reverse order.
+type option struct {
+	key string
+	// value is the default, and its type the type of the flag
+	value any
+	usage string
+}
+
+var globalOptions = []option{
+	{kafkaHostKey, "localhost", "Kafka host"},
+	{kafkaPortKey, 9092, "Kafka port"},
+	{mongoDBURIKey, "mongodb://localhost:27017", "MongoDB URI"},
+	{developmentKey, true, "Development mode"},
+	{logLevelKey, "", "Log level (debug, info, warn or error), empty for debug in development mode and info otherwise"},
+	{mongoDBTimeoutKey, 5 * time.Second, "Timeout of MongoDB operations on a single document"},
+	{mongoDBBulkTimeoutKey, 30 * time.Second, "Timeout of MongoDB operations on many documents"},
+	{grpcPortKey, 10010, "gRPC port"},
+	{metricsPortKey, 8081, "Prometheus metrics HTTP port, 0 to disable"},
+	{httpPortKey, 8080, "HTTP/JSON gateway port, 0 to disable"},
+	{httpCORSOriginsKey, []string{}, "Origins allowed to call the HTTP gateway from a browser"},
+	{httpAdminUIKey, false, "Serve the admin dashboard at /admin/ on the HTTP gateway, needs auth.enabled outside development"},
+	{notifierBackendsKey, []string{"kafka"}, "Notifier backends (kafka, webhook, log)"},
+	{webhookEndpointsKey, "[]", "Webhook endpoints as a JSON array of {url, secret, events}"},
+	{webhookMaxRetriesKey, 5, "Webhook delivery retries"},
+	{webhookRetryBackoffKey, time.Second, "Webhook initial retry backoff"},
+	{webhookTimeoutKey, 10 * time.Second, "Webhook request timeout"},
+	{authEnabledKey, false, "Require authentication for non-public RPCs"},
+	{authIdentitiesKey, "[]", "Identities as a JSON array of {name, token, clientCertName, methods}"},
+	{authPublicMethodsKey, []string{}, "RPCs that can be called without authenticating"},
+	{authPlayerPermsKey, false, "Check the acting player's permission nodes and role priority before mutations"},
+	{tlsCertFileKey, "", "TLS certificate file"},
+	{tlsKeyFileKey, "", "TLS key file"},
+	{tlsClientCAFileKey, "", "CA file used to verify mTLS client certificates"},
+	{tracingExporterKey, "none", "Trace exporter (none, stdout, file, otlp)"},
+	{tracingFileKey, "traces.json", "File spans are written to by the file exporter"},
+	{tracingOTLPEndpointKey, "localhost:4317", "OTLP gRPC collector endpoint"},
+	{tracingOTLPInsecureKey, false, "Connect to the OTLP collector without TLS"},
+	{tracingSampleRatioKey, 1.0, "Fraction of new traces that are sampled"},
+	{roleSyncPathKey, "", "Role set file or directory to reconcile roles with, empty to disable"},
+	{roleSyncIntervalKey, time.Minute, "Interval between role sync reconciles when the files haven't changed"},
+	{bootstrapDefaultRoleIdKey, "default", "Id of the role every player holds"},
+	{bootstrapDefaultRoleDisplayNameKey, "{{.Username}}", "Display name template of the default role, empty for none"},
+	{bootstrapDefaultRoleAllowKey, []string{}, "Permission nodes the default role allows when it's created"},
+	{bootstrapDefaultRoleDenyKey, []string{}, "Permission nodes the default role denies when it's created"},
+	{bootstrapRolesKey, "[]", "Other roles created at startup as a JSON array of {id, priority, display-name, allow, deny}"},
+}
+
+// RenamedKey is a config key that was renamed. The deprecated key is still read if the new key isn't set.
+type RenamedKey struct {
+	Key        string
+	Deprecated string
+}
+
+var renamedKeys = []RenamedKey{
+	{grpcPortKey, "port"},
+	{notifierBackendsKey, "notifier.backend"},
+	{webhookEndpointsKey, "webhook.endpoints"},
+	{webhookMaxRetriesKey, "webhook.max-retries"},
+	{webhookRetryBackoffKey, "webhook.retry-backoff"},
+	{webhookTimeoutKey, "webhook.timeout"},
+}
+
+// flagName is the flag of a config key, e.g. --kafka-host for kafka.host
+func flagName(key string) string {
+	return strings.ReplaceAll(key, ".", "-")
+}
+
 type Config struct {
+	// File is the config file loaded, empty if none
+	File string
+
 	Kafka   KafkaConfig
 	MongoDB MongoDBConfig
 
+	Notifier NotifierConfig
+
+	Auth AuthConfig
+	TLS  TLSConfig
+
+	Tracing TracingConfig
+
+	Gateway GatewayConfig
+
+	RoleSync RoleSyncConfig
+
+	Bootstrap BootstrapConfig
+
+	// Deprecated are the renamed keys the config was read from, so they can be logged
+	Deprecated []RenamedKey
+
 	Development bool
+	// LogLevel overrides the level implied by Development if set
+	LogLevel string
 
 	GRPCPort int
+	// MetricsPort serves Prometheus metrics over HTTP. Disabled if 0.
+	MetricsPort int
 }
 
 type KafkaConfig struct {
@@ -31,39 +170,362 @@ type KafkaConfig struct {
 
 type MongoDBConfig struct {
 	URI string
+
+	// Timeout bounds operations on a single document, BulkTimeout operations on many (e.g. bulk role grants)
+	Timeout     time.Duration
+	BulkTimeout time.Duration
+}
+
+type NotifierConfig struct {
+	// Backends are the notifier backends events are fanned out to (kafka, webhook or log), each listed once
+	Backends []string
+
+	Webhook WebhookConfig
+}
+
+type WebhookConfig struct {
+	Endpoints []WebhookEndpointConfig
+
+	// MaxRetries is the number of times a failed delivery is retried before it is dropped
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, doubled for every subsequent retry
+	RetryBackoff time.Duration
+	Timeout      time.Duration
+}
+
+type WebhookEndpointConfig struct {
+	URL string `json:"url"`
+	// Secret is used to HMAC-SHA256 sign every payload, so must not be empty
+	Secret string `json:"secret"`
+	// Events filters which events are sent to the endpoint (e.g. "role.modify", "player_role.add").
+	// All events are sent if empty.
+	Events []string `json:"events"`
+}
+
+type AuthConfig struct {
+	// Enabled requires every non-public RPC to be made by a known identity
+	Enabled bool
+
+	Identities []IdentityConfig
+	// PublicMethods can be called without authenticating (e.g. "GetAllRoles", "GetPlayerRoles")
+	PublicMethods []string
+
+	// PlayerPermissions requires the player a mutation is made on behalf of (x-actor-id metadata)
+	// to hold the matching permission node and outrank the roles they change. Mutations made with neither an actor
+	// nor a service account are denied. Needs Enabled, as the actor is only trusted from authenticated callers.
+	PlayerPermissions bool
+}
+
+// IdentityConfig is a caller of the API, authenticated by a bearer token or an mTLS client certificate.
+type IdentityConfig struct {
+	Name string `json:"name"`
+
+	// Token is a static bearer token sent in the "authorization" metadata
+	Token string `json:"token"`
+	// ClientCertName is matched against the common name and DNS SANs of a verified client certificate
+	ClientCertName string `json:"clientCertName"`
+
+	// Methods the identity may call, by short name (e.g. "UpdateRole") or full gRPC method. "*" allows all.
+	Methods []string `json:"methods"`
+}
+
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile is used to verify client certificates. mTLS identities are disabled if empty.
+	ClientCAFile string
+}
+
+// GatewayConfig is the HTTP/JSON gateway to the PermissionService. It uses the same auth and TLS config as gRPC.
+type GatewayConfig struct {
+	// Port the gateway listens on. Disabled if 0.
+	Port int
+	// CORSOrigins are the browser origins allowed to call the gateway with credentials. "*" allows any origin,
+	// but without credentials.
+	CORSOrigins []string
+	// AdminUI serves the web admin dashboard at /admin/. It can modify permissions, so it needs auth outside development.
+	AdminUI bool
+}
+
+// RoleSyncConfig makes a roles file or directory the source of truth for the roles it declares.
+type RoleSyncConfig struct {
+	// Path is a YAML/JSON role set file, or a directory of them. Disabled if empty.
+	Path string
+	// Interval between reconciles when the files haven't changed, undoing changes made to the database directly
+	Interval time.Duration
+}
+
+// BootstrapConfig declares roles created at startup if they don't exist, so fresh environments have a usable set
+// of roles. Existing roles are never changed, so editing a role here has no effect once it has been created.
+type BootstrapConfig struct {
+	// DefaultRole is held by every player. Changing its id doesn't move players holding the previous default role.
+	DefaultRole BootstrapRoleConfig
+	Roles       []BootstrapRoleConfig
+}
+
+// BootstrapRoleConfig uses the same kebab-case keys as bootstrap.default-role when read from a list.
+type BootstrapRoleConfig struct {
+	Id       string `json:"id" mapstructure:"id"`
+	Priority uint32 `json:"priority" mapstructure:"priority"`
+	// DisplayName is the display name template (e.g. "<red>[Admin] {{.Username}}"), none if empty
+	DisplayName string `json:"display-name" mapstructure:"display-name"`
+
+	// Allow and Deny are the permission nodes the role allows and denies
+	Allow []string `json:"allow" mapstructure:"allow"`
+	Deny  []string `json:"deny" mapstructure:"deny"`
+}
+
+type TracingConfig struct {
+	// Exporter is where spans are sent: none, stdout, file or otlp
+	Exporter string
+	// File spans are appended to as JSON when the exporter is file
+	File string
+
+	// OTLPEndpoint is the host:port of an OTLP gRPC collector
+	OTLPEndpoint string
+	OTLPInsecure bool
+
+	// SampleRatio is the fraction of traces started by this service that are sampled.
+	// Traces started by callers follow the caller's sampling decision.
+	SampleRatio float64
+}
+
+// LoadGlobalConfig parses the command line and loads the config from the file given by --config (or CONFIG_FILE),
+// environment variables and flags, and validates it. Flags of individual commands must be defined on pflag.CommandLine
+// before calling it.
+func LoadGlobalConfig() (Config, error) {
+	return loadGlobalConfig(viper.GetViper(), pflag.CommandLine, os.Args[1:])
+}
+
+func loadGlobalConfig(v *viper.Viper, flags *pflag.FlagSet, args []string) (Config, error) {
+	flags.String(configFileFlag, "", "Config file (YAML, JSON or TOML) with nested keys, e.g. kafka.host for --kafka-host")
+	for _, opt := range globalOptions {
+		defineFlag(flags, opt)
+	}
+	for _, renamed := range renamedKeys {
+		opt := findOption(renamed.Key)
+		defineFlag(flags, option{renamed.Deprecated, opt.value, opt.usage})
+		runtime.Must(flags.MarkDeprecated(flagName(renamed.Deprecated), fmt.Sprintf("use --%s instead", flagName(renamed.Key))))
+	}
+	if err := flags.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+	for _, opt := range globalOptions {
+		// Renamed keys fall back to their flag default instead, so IsSet tells whether they were set
+		if !isRenamed(opt.key) {
+			v.SetDefault(opt.key, opt.value)
+		}
+		runtime.Must(v.BindPFlag(opt.key, flags.Lookup(flagName(opt.key))))
+		runtime.Must(v.BindEnv(opt.key))
+	}
+	for _, renamed := range renamedKeys {
+		runtime.Must(v.BindPFlag(renamed.Deprecated, flags.Lookup(flagName(renamed.Deprecated))))
+		runtime.Must(v.BindEnv(renamed.Deprecated))
+	}
+	runtime.Must(v.BindPFlag(configFileFlag, flags.Lookup(configFileFlag)))
+	runtime.Must(v.BindEnv(configFileFlag, configFileEnv))
+
+	if file := v.GetString(configFileFlag); file != "" {
+		v.SetConfigFile(file)
+		if err := v.ReadInConfig(); err != nil {
+			return Config{}, fmt.Errorf("failed to read config file %s: %w", file, err)
+		}
+	}
+
+	cfg, err := readGlobalConfig(v)
+	if err != nil {
+		return Config{}, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+func findOption(key string) option {
+	for _, opt := range globalOptions {
+		if opt.key == key {
+			return opt
+		}
+	}
+	panic(fmt.Sprintf("unknown config key %s", key))
+}
+
+func isRenamed(key string) bool {
+	for _, renamed := range renamedKeys {
+		if renamed.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveRenamedKeys returns the key each renamed key is read from, which is the deprecated key if only it is set,
+// and the renamed keys whose deprecated key is read.
+func resolveRenamedKeys(v *viper.Viper) (map[string]string, []RenamedKey) {
+	keys := make(map[string]string, len(renamedKeys))
+	var deprecated []RenamedKey
+	for _, renamed := range renamedKeys {
+		keys[renamed.Key] = renamed.Key
+		if !v.IsSet(renamed.Key) && v.IsSet(renamed.Deprecated) {
+			keys[renamed.Key] = renamed.Deprecated
+			deprecated = append(deprecated, renamed)
+		}
+	}
+	return keys, deprecated
+}
+
+func defineFlag(flags *pflag.FlagSet, opt option) {
+	name := flagName(opt.key)
+	switch value := opt.value.(type) {
+	case string:
+		flags.String(name, value, opt.usage)
+	case int:
+		flags.Int(name, value, opt.usage)
+	case bool:
+		flags.Bool(name, value, opt.usage)
+	case float64:
+		flags.Float64(name, value, opt.usage)
+	case time.Duration:
+		flags.Duration(name, value, opt.usage)
+	case []string:
+		flags.StringSlice(name, value, opt.usage)
+	default:
+		panic(fmt.Sprintf("unsupported type %T of config key %s", value, opt.key))
+	}
 }
 
-func LoadGlobalConfig() Config {
-	viper.SetDefault(kafkaHostFlag, "localhost")
-	viper.SetDefault(kafkaPortFlag, 9092)
-	viper.SetDefault(mongoDBURIFlag, "mongodb://localhost:27017")
-	viper.SetDefault(developmentFlag, true)
-	viper.SetDefault(grpcPortFlag, 10010)
-
-	pflag.String(kafkaHostFlag, viper.GetString(kafkaHostFlag), "Kafka host")
-	pflag.Int32(kafkaPortFlag, viper.GetInt32(kafkaPortFlag), "Kafka port")
-	pflag.String(mongoDBURIFlag, viper.GetString(mongoDBURIFlag), "MongoDB URI")
-	pflag.Bool(developmentFlag, viper.GetBool(developmentFlag), "Development mode")
-	pflag.Int32(grpcPortFlag, viper.GetInt32(grpcPortFlag), "gRPC port")
-	pflag.Parse()
-
-	// Bind the viper flags to environment variables
-	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
-	runtime.Must(viper.BindEnv(kafkaHostFlag))
-	runtime.Must(viper.BindEnv(kafkaPortFlag))
-	runtime.Must(viper.BindEnv(mongoDBURIFlag))
-	runtime.Must(viper.BindEnv(developmentFlag))
-	runtime.Must(viper.BindEnv(grpcPortFlag))
+// readGlobalConfig builds the config from the values v has loaded.
+func readGlobalConfig(v *viper.Viper) (Config, error) {
+	keys, deprecated := resolveRenamedKeys(v)
+
+	var webhookEndpoints []WebhookEndpointConfig
+	if err := unmarshalList(v, keys[webhookEndpointsKey], &webhookEndpoints); err != nil {
+		return Config{}, err
+	}
+
+	var identities []IdentityConfig
+	if err := unmarshalList(v, authIdentitiesKey, &identities); err != nil {
+		return Config{}, err
+	}
+
+	var bootstrapRoles []BootstrapRoleConfig
+	if err := unmarshalList(v, bootstrapRolesKey, &bootstrapRoles); err != nil {
+		return Config{}, err
+	}
 
 	return Config{
+		File: v.ConfigFileUsed(),
 		Kafka: KafkaConfig{
-			Host: viper.GetString(kafkaHostFlag),
-			Port: int(viper.GetInt32(kafkaPortFlag)),
+			Host: v.GetString(kafkaHostKey),
+			Port: v.GetInt(kafkaPortKey),
 		},
 		MongoDB: MongoDBConfig{
-			URI: viper.GetString(mongoDBURIFlag),
+			URI:         v.GetString(mongoDBURIKey),
+			Timeout:     v.GetDuration(mongoDBTimeoutKey),
+			BulkTimeout: v.GetDuration(mongoDBBulkTimeoutKey),
+		},
+		Notifier: NotifierConfig{
+			Backends: v.GetStringSlice(keys[notifierBackendsKey]),
+			Webhook: WebhookConfig{
+				Endpoints:    webhookEndpoints,
+				MaxRetries:   v.GetInt(keys[webhookMaxRetriesKey]),
+				RetryBackoff: v.GetDuration(keys[webhookRetryBackoffKey]),
+				Timeout:      v.GetDuration(keys[webhookTimeoutKey]),
+			},
+		},
+		Auth: AuthConfig{
+			Enabled:           v.GetBool(authEnabledKey),
+			Identities:        identities,
+			PublicMethods:     v.GetStringSlice(authPublicMethodsKey),
+			PlayerPermissions: v.GetBool(authPlayerPermsKey),
+		},
+		TLS: TLSConfig{
+			CertFile:     v.GetString(tlsCertFileKey),
+			KeyFile:      v.GetString(tlsKeyFileKey),
+			ClientCAFile: v.GetString(tlsClientCAFileKey),
+		},
+		Tracing: TracingConfig{
+			Exporter:     v.GetString(tracingExporterKey),
+			File:         v.GetString(tracingFileKey),
+			OTLPEndpoint: v.GetString(tracingOTLPEndpointKey),
+			OTLPInsecure: v.GetBool(tracingOTLPInsecureKey),
+			SampleRatio:  v.GetFloat64(tracingSampleRatioKey),
+		},
+		Gateway: GatewayConfig{
+			Port:        v.GetInt(httpPortKey),
+			CORSOrigins: v.GetStringSlice(httpCORSOriginsKey),
+			AdminUI:     v.GetBool(httpAdminUIKey),
+		},
+		RoleSync: RoleSyncConfig{
+			Path:     v.GetString(roleSyncPathKey),
+			Interval: v.GetDuration(roleSyncIntervalKey),
 		},
-		Development: viper.GetBool(developmentFlag),
-		GRPCPort:    int(viper.GetInt32(grpcPortFlag)),
+		Bootstrap: BootstrapConfig{
+			DefaultRole: BootstrapRoleConfig{
+				Id:          v.GetString(bootstrapDefaultRoleIdKey),
+				DisplayName: v.GetString(bootstrapDefaultRoleDisplayNameKey),
+				Allow:       v.GetStringSlice(bootstrapDefaultRoleAllowKey),
+				Deny:        v.GetStringSlice(bootstrapDefaultRoleDenyKey),
+			},
+			Roles: bootstrapRoles,
+		},
+		Deprecated:  deprecated,
+		Development: v.GetBool(developmentKey),
+		LogLevel:    v.GetString(logLevelKey),
+		GRPCPort:    v.GetInt(keys[grpcPortKey]),
+		MetricsPort: v.GetInt(metricsPortKey),
+	}, nil
+}
+
+// unmarshalList reads a list of objects, which flags and environment variables give as a JSON string
+// and config files as a native list.
+func unmarshalList(v *viper.Viper, key string, out any) error {
+	var err error
+	if s, ok := v.Get(key).(string); ok {
+		err = json.Unmarshal([]byte(s), out)
+	} else {
+		err = v.UnmarshalKey(key, out)
+	}
+
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return nil
+}
+
+const redacted = "REDACTED"
+
+// Redacted returns a copy of the config with secrets (tokens, webhook secrets and the MongoDB password) replaced,
+// so it can be logged.
+func (c Config) Redacted() Config {
+	if u, err := url.Parse(c.MongoDB.URI); err != nil {
+		// Can't tell where the password is
+		c.MongoDB.URI = redacted
+	} else if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), redacted)
+		c.MongoDB.URI = u.String()
+	}
+
+	endpoints := make([]WebhookEndpointConfig, len(c.Notifier.Webhook.Endpoints))
+	for i, endpoint := range c.Notifier.Webhook.Endpoints {
+		if endpoint.Secret != "" {
+			endpoint.Secret = redacted
+		}
+		endpoints[i] = endpoint
 	}
+	c.Notifier.Webhook.Endpoints = endpoints
+
+	identities := make([]IdentityConfig, len(c.Auth.Identities))
+	for i, identity := range c.Auth.Identities {
+		if identity.Token != "" {
+			identity.Token = redacted
+		}
+		identities[i] = identity
+	}
+	c.Auth.Identities = identities
+
+	return c
 }
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"os"
+	"os/signal"
+	"permission-service/internal/utils/filewatch"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Tunables are the settings that are safe to change while running: the log level and the MongoDB timeouts.
+// They're applied when the config is reloaded, other changes need a restart.
+type Tunables struct {
+	LogLevel zapcore.Level
+
+	RepositoryTimeout     time.Duration
+	RepositoryBulkTimeout time.Duration
+}
+
+func (c Config) Tunables() Tunables {
+	level := zapcore.InfoLevel
+	if c.Development {
+		level = zapcore.DebugLevel
+	}
+	if c.LogLevel != "" {
+		// Validated when loaded
+		level, _ = zapcore.ParseLevel(c.LogLevel)
+	}
+
+	return Tunables{
+		LogLevel:              level,
+		RepositoryTimeout:     c.MongoDB.Timeout,
+		RepositoryBulkTimeout: c.MongoDB.BulkTimeout,
+	}
+}
+
+// withTunables returns a copy of the config with the tunables of other.
+func (c Config) withTunables(other Config) Config {
+	c.LogLevel = other.LogLevel
+	c.MongoDB.Timeout = other.MongoDB.Timeout
+	c.MongoDB.BulkTimeout = other.MongoDB.BulkTimeout
+	return c
+}
+
+// WatchGlobalConfig reloads the config loaded by LoadGlobalConfig when its file changes or on SIGHUP, and passes
+// the new tunables to apply. An invalid config is logged and ignored, keeping the current tunables.
+func WatchGlobalConfig(ctx context.Context, wg *sync.WaitGroup, logger *zap.SugaredLogger, cfg Config, apply func(Tunables)) error {
+	r := &reloader{v: viper.GetViper(), logger: logger, current: cfg, apply: apply}
+
+	// Left nil without a file, so only SIGHUP reloads
+	var changes <-chan struct{}
+	if cfg.File != "" {
+		var err error
+		if changes, err = filewatch.Watch(ctx, wg, logger, cfg.File); err != nil {
+			return fmt.Errorf("failed to watch config file: %w", err)
+		}
+	}
+
+	// Handled even without a file to reload, as SIGHUP would otherwise terminate the process
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer signal.Stop(hangup)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hangup:
+				r.reload()
+			case <-changes:
+				r.reload()
+			}
+		}
+	}()
+
+	logger.Infow("watching config for changes", "file", cfg.File)
+	return nil
+}
+
+type reloader struct {
+	v      *viper.Viper
+	logger *zap.SugaredLogger
+
+	current Config
+	apply   func(Tunables)
+}
+
+// reload re-reads the config file. Flags and environment variables still take precedence over it.
+func (r *reloader) reload() {
+	if r.current.File != "" {
+		if err := r.v.ReadInConfig(); err != nil {
+			r.logger.Errorw("failed to reload config, keeping the current config", "file", r.current.File, "error", err)
+			return
+		}
+	}
+
+	next, err := readGlobalConfig(r.v)
+	if err == nil {
+		err = next.Validate()
+	}
+	if err != nil {
+		r.logger.Errorw("invalid config, keeping the current config", "file", r.current.File, "error", err)
+		return
+	}
+
+	if !reflect.DeepEqual(r.current.withTunables(next), next) {
+		r.logger.Warnw("config changed other than the tunables, which needs a restart to apply", "file", r.current.File)
+	}
+
+	if next.Tunables() == r.current.Tunables() {
+		return
+	}
+
+	r.current = r.current.withTunables(next)
+	// Logged before applying, as the new log level may hide it
+	r.logger.Infow("reloaded config", "file", r.current.File, "tunables", r.current.Tunables())
+	r.apply(r.current.Tunables())
+}
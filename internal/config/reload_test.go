@@ -0,0 +1,59 @@
+package config
+
+import (
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestReloader(t *testing.T, contents string) (*reloader, string, *[]Tunables) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o644))
+
+	v := viper.New()
+	cfg, err := loadGlobalConfig(v, pflag.NewFlagSet("test", pflag.ContinueOnError), []string{"--config", path})
+	require.NoError(t, err)
+
+	applied := &[]Tunables{}
+	r := &reloader{v: v, logger: zap.NewNop().Sugar(), current: cfg, apply: func(tunables Tunables) {
+		*applied = append(*applied, tunables)
+	}}
+	return r, path, applied
+}
+
+func TestReloader_Reload(t *testing.T) {
+	r, path, applied := newTestReloader(t, "development: false\nport: 10001\n")
+	assert.Equal(t, Tunables{LogLevel: zapcore.InfoLevel, RepositoryTimeout: 5 * time.Second, RepositoryBulkTimeout: 30 * time.Second}, r.current.Tunables())
+
+	// The port needs a restart, so only the tunables are applied
+	require.NoError(t, os.WriteFile(path, []byte("development: false\nport: 10002\nlog:\n  level: warn\nmongodb:\n  timeout: 2s\n"), 0o644))
+	r.reload()
+
+	want := Tunables{LogLevel: zapcore.WarnLevel, RepositoryTimeout: 2 * time.Second, RepositoryBulkTimeout: 30 * time.Second}
+	assert.Equal(t, []Tunables{want}, *applied)
+	assert.Equal(t, want, r.current.Tunables())
+	assert.Equal(t, 10001, r.current.GRPCPort)
+
+	// Nothing is applied if the tunables haven't changed
+	r.reload()
+	assert.Len(t, *applied, 1)
+}
+
+func TestReloader_Reload_Invalid(t *testing.T) {
+	r, path, applied := newTestReloader(t, "log:\n  level: info\n")
+
+	require.NoError(t, os.WriteFile(path, []byte("log:\n  level: verbose\n"), 0o644))
+	r.reload()
+	require.NoError(t, os.WriteFile(path, []byte("log: [not, a, map"), 0o644))
+	r.reload()
+
+	assert.Empty(t, *applied)
+	assert.Equal(t, "info", r.current.LogLevel)
+}
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/connstring"
+	"go.uber.org/zap/zapcore"
+	"net/url"
+	"permission-service/internal/validation"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	notifierBackends = []string{"kafka", "webhook", "log"}
+	tracingExporters = []string{"none", "stdout", "file", "otlp"}
+)
+
+// configErrors collects every invalid config key, so they can all be fixed at once.
+type configErrors struct {
+	errs []error
+}
+
+func (e *configErrors) add(key string, format string, args ...any) {
+	e.errs = append(e.errs, fmt.Errorf("invalid %s: %s", key, fmt.Sprintf(format, args...)))
+}
+
+func (e *configErrors) port(key string, port int, optional bool) {
+	switch {
+	case optional && port == 0:
+	case port < 1 || port > 65535:
+		e.add(key, "%d is not a port between 1 and 65535", port)
+	}
+}
+
+func (e *configErrors) positive(key string, d time.Duration) {
+	if d <= 0 {
+		e.add(key, "must be positive")
+	}
+}
+
+// Validate returns every invalid value in the config joined into one error, or nil if it's valid.
+func (c Config) Validate() error {
+	e := &configErrors{}
+
+	e.port(kafkaPortKey, c.Kafka.Port, false)
+	if c.Kafka.Host == "" && slices.Contains(c.Notifier.Backends, "kafka") {
+		e.add(kafkaHostKey, "must not be empty when the kafka notifier backend is enabled")
+	}
+
+	if _, err := connstring.ParseAndValidate(c.MongoDB.URI); err != nil {
+		// The driver's error includes the URI, which may hold a password
+		e.add(mongoDBURIKey, "not a valid MongoDB connection string")
+	}
+	e.positive(mongoDBTimeoutKey, c.MongoDB.Timeout)
+	e.positive(mongoDBBulkTimeoutKey, c.MongoDB.BulkTimeout)
+
+	if c.LogLevel != "" {
+		if _, err := zapcore.ParseLevel(c.LogLevel); err != nil {
+			e.add(logLevelKey, "unknown level %q", c.LogLevel)
+		}
+	}
+
+	e.port(grpcPortKey, c.GRPCPort, false)
+	e.port(metricsPortKey, c.MetricsPort, true)
+	e.port(httpPortKey, c.Gateway.Port, true)
+	if c.MetricsPort != 0 && c.MetricsPort == c.GRPCPort {
+		e.add(metricsPortKey, "must differ from %s", grpcPortKey)
+	}
+	if c.Gateway.Port != 0 && (c.Gateway.Port == c.GRPCPort || c.Gateway.Port == c.MetricsPort) {
+		e.add(httpPortKey, "must differ from %s and %s", grpcPortKey, metricsPortKey)
+	}
+
+	c.validateNotifier(e)
+	c.validateAuth(e)
+
+	if c.Tracing.Exporter != "" && !slices.Contains(tracingExporters, c.Tracing.Exporter) {
+		e.add(tracingExporterKey, "unknown exporter %q, expected one of %s", c.Tracing.Exporter, strings.Join(tracingExporters, ", "))
+	}
+	if c.Tracing.Exporter == "file" && c.Tracing.File == "" {
+		e.add(tracingFileKey, "must not be empty when the file exporter is used")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		e.add(tracingSampleRatioKey, "must be between 0 and 1")
+	}
+
+	if c.RoleSync.Interval < 0 {
+		e.add(roleSyncIntervalKey, "must not be negative")
+	}
+
+	c.validateBootstrap(e)
+
+	return errors.Join(e.errs...)
+}
+
+func (c Config) validateNotifier(e *configErrors) {
+	seen := make(map[string]bool, len(c.Notifier.Backends))
+	for _, backend := range c.Notifier.Backends {
+		switch {
+		case !slices.Contains(notifierBackends, backend):
+			e.add(notifierBackendsKey, "unknown backend %q, expected one of %s", backend, strings.Join(notifierBackends, ", "))
+		case seen[backend]:
+			// Each backend has one set of metrics, so a second would overwrite the first's
+			e.add(notifierBackendsKey, "backend %q is listed more than once", backend)
+		}
+		seen[backend] = true
+	}
+
+	webhook := c.Notifier.Webhook
+	if len(webhook.Endpoints) == 0 && slices.Contains(c.Notifier.Backends, "webhook") {
+		e.add(webhookEndpointsKey, "must not be empty when the webhook notifier backend is enabled")
+	}
+	for i, endpoint := range webhook.Endpoints {
+		if u, err := url.Parse(endpoint.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			e.add(fmt.Sprintf("%s[%d].url", webhookEndpointsKey, i), "%q is not an http or https URL", endpoint.URL)
+		}
+		if endpoint.Secret == "" {
+			e.add(fmt.Sprintf("%s[%d].secret", webhookEndpointsKey, i), "must not be empty, as every payload is signed")
+		}
+	}
+	if webhook.MaxRetries < 0 {
+		e.add(webhookMaxRetriesKey, "must not be negative")
+	}
+	if webhook.RetryBackoff < 0 {
+		e.add(webhookRetryBackoffKey, "must not be negative")
+	}
+	e.positive(webhookTimeoutKey, webhook.Timeout)
+}
+
+func (c Config) validateAuth(e *configErrors) {
+	names := make(map[string]bool, len(c.Auth.Identities))
+	for i, identity := range c.Auth.Identities {
+		key := fmt.Sprintf("%s[%d]", authIdentitiesKey, i)
+		switch {
+		case identity.Name == "":
+			e.add(key+".name", "must not be empty")
+		case names[identity.Name]:
+			e.add(key+".name", "%s is defined more than once", identity.Name)
+		}
+		names[identity.Name] = true
+
+		if identity.Token == "" && identity.ClientCertName == "" {
+			e.add(key, "must have a token or clientCertName")
+		}
+	}
+
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		e.add(tlsCertFileKey, "%s and %s must be set together", tlsCertFileKey, tlsKeyFileKey)
+	}
+	if c.TLS.ClientCAFile != "" && c.TLS.CertFile == "" {
+		e.add(tlsClientCAFileKey, "needs %s to be set", tlsCertFileKey)
+	}
+
+	// Without auth the actor is only the unauthenticated x-actor-id metadata, so anyone could claim to be an admin
+	if c.Auth.PlayerPermissions && !c.Auth.Enabled {
+		e.add(authPlayerPermsKey, "needs %s, as callers could act as any player", authEnabledKey)
+	}
+	if c.Gateway.AdminUI && !c.Auth.Enabled && !c.Development {
+		e.add(httpAdminUIKey, "needs %s, as anyone who can reach it could modify permissions", authEnabledKey)
+	}
+}
+
+// validateBootstrap checks the bootstrap roles against the rules for roles created through the API.
+func (c Config) validateBootstrap(e *configErrors) {
+	v := &validation.Violations{}
+
+	// The default role's priority is always 0, so it isn't checked
+	validateBootstrapRole(v, bootstrapDefaultRoleKey, c.Bootstrap.DefaultRole, false)
+	seen := map[string]bool{c.Bootstrap.DefaultRole.Id: true}
+
+	for i, role := range c.Bootstrap.Roles {
+		key := fmt.Sprintf("%s[%d]", bootstrapRolesKey, i)
+		if seen[role.Id] {
+			v.Add(key+".id", "must be unique")
+		}
+		seen[role.Id] = true
+
+		validateBootstrapRole(v, key, role, true)
+	}
+
+	v.Each(func(field string, description string) {
+		e.add(field, "%s", description)
+	})
+}
+
+func validateBootstrapRole(v *validation.Violations, key string, role BootstrapRoleConfig, checkPriority bool) {
+	v.Id(key+".id", role.Id)
+	if checkPriority {
+		v.Priority(key+".priority", role.Priority)
+	}
+	if role.DisplayName != "" {
+		v.DisplayName(key+".display-name", role.DisplayName)
+	}
+
+	allowed := make(map[string]bool, len(role.Allow))
+	for i, node := range role.Allow {
+		v.Node(fmt.Sprintf("%s.allow[%d]", key, i), node)
+		allowed[node] = true
+	}
+	for i, node := range role.Deny {
+		nodeKey := fmt.Sprintf("%s.deny[%d]", key, i)
+		v.Node(nodeKey, node)
+		if allowed[node] {
+			v.Add(nodeKey, "must not also be allowed")
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/emortalmc/proto-specs/gen/go/grpc/permission"
+	"github.com/emortalmc/proto-specs/gen/go/nongenerated/kafkautils"
+	"github.com/segmentio/kafka-go"
+	otelCodes "go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"io"
+	"permission-service/internal/auth"
+	"permission-service/internal/config"
+	"permission-service/internal/service"
+	"permission-service/internal/tracing"
+	"sync"
+	"time"
+)
+
+const (
+	commandsTopic = "permission-commands"
+	consumerGroup = "permission-service"
+
+	// CommandsActor is the actor of changes made by commands, which aren't otherwise authenticated
+	CommandsActor = "kafka:" + commandsTopic
+
+	actorHeader  = "X-Actor"
+	reasonHeader = "X-Reason"
+
+	minRetryBackoff = 500 * time.Millisecond
+	maxRetryBackoff = 30 * time.Second
+)
+
+// permanentError marks a command that can never succeed (bad payload, unknown role, etc.)
+// Such commands are logged and committed so they don't block the partition.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e permanentError) Unwrap() error {
+	return e.err
+}
+
+var commandsIdentity = &auth.Identity{Name: CommandsActor}
+
+type commandHandler func(ctx context.Context, msg proto.Message) error
+
+type kafkaConsumer struct {
+	logger *zap.SugaredLogger
+	reader *kafka.Reader
+
+	svc permission.PermissionServiceServer
+
+	handlers map[protoreflect.FullName]commandHandler
+}
+
+// NewKafkaConsumer starts a consumer group reading permission commands from Kafka.
+// Commands are applied through the given service, and their offsets are only committed
+// once the command has been applied (or has failed permanently).
+//
+// Commands are trusted: they're applied directly through svc, skipping the authentication and authorization
+// interceptors of the gRPC server, so only trusted services should be able to write to the commands topic.
+func NewKafkaConsumer(ctx context.Context, wg *sync.WaitGroup, logger *zap.SugaredLogger, cfg config.KafkaConfig,
+	svc permission.PermissionServiceServer) {
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     []string{fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)},
+		GroupID:     consumerGroup,
+		Topic:       commandsTopic,
+		Logger:      kafkautils.CreateLogger(logger),
+		ErrorLogger: kafkautils.CreateErrorLogger(logger),
+	})
+
+	c := newKafkaConsumer(logger, reader, svc)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		c.run(ctx)
+
+		logger.Info("shutting down kafka consumer")
+		if err := reader.Close(); err != nil {
+			logger.Errorw("failed to close kafka reader", "error", err)
+		}
+	}()
+}
+
+func newKafkaConsumer(logger *zap.SugaredLogger, reader *kafka.Reader, svc permission.PermissionServiceServer) *kafkaConsumer {
+	c := &kafkaConsumer{
+		logger: logger,
+		reader: reader,
+		svc:    svc,
+	}
+
+	c.handlers = map[protoreflect.FullName]commandHandler{
+		(&permission.AddRoleToPlayerRequest{}).ProtoReflect().Descriptor().FullName():      c.handleAddRoleToPlayer,
+		(&permission.RemoveRoleFromPlayerRequest{}).ProtoReflect().Descriptor().FullName(): c.handleRemoveRoleFromPlayer,
+	}
+
+	return c
+}
+
+func (c *kafkaConsumer) run(ctx context.Context) {
+	fetchBackoff := minRetryBackoff
+	for {
+		m, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, io.EOF) {
+				return
+			}
+			// Backs off so a broker outage doesn't become a busy loop of errors
+			c.logger.Errorw("failed to fetch message", "error", err, "backoff", fetchBackoff)
+			if !sleep(ctx, fetchBackoff) {
+				return
+			}
+			fetchBackoff = min(fetchBackoff*2, maxRetryBackoff)
+			continue
+		}
+		fetchBackoff = minRetryBackoff
+
+		if !c.processWithRetry(ctx, &m) {
+			// Context was cancelled before the command could be applied.
+			// The offset is left uncommitted so the command is redelivered.
+			return
+		}
+
+		if err := c.reader.CommitMessages(ctx, m); err != nil {
+			c.logger.Errorw("failed to commit message", "error", err, "partition", m.Partition, "offset", m.Offset)
+		}
+	}
+}
+
+// processWithRetry applies a message, retrying transient failures with backoff.
+// It returns false only if the context was cancelled before the message was settled.
+func (c *kafkaConsumer) processWithRetry(ctx context.Context, m *kafka.Message) bool {
+	backoff := minRetryBackoff
+	for {
+		err := c.handleMessage(ctx, m)
+		if err == nil {
+			return true
+		}
+
+		var permErr permanentError
+		if errors.As(err, &permErr) {
+			c.logger.Errorw("dropping permission command", "error", err, "partition", m.Partition, "offset", m.Offset)
+			return true
+		}
+
+		c.logger.Warnw("failed to apply permission command, retrying", "error", err, "partition", m.Partition,
+			"offset", m.Offset, "backoff", backoff)
+
+		if !sleep(ctx, backoff) {
+			return false
+		}
+		backoff = min(backoff*2, maxRetryBackoff)
+	}
+}
+
+// sleep waits for d, returning false if ctx is cancelled first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (c *kafkaConsumer) handleMessage(ctx context.Context, m *kafka.Message) error {
+	protoTypeName, err := kafkautils.ProtoTypeFromHeaders(m.Headers)
+	if err != nil {
+		return permanentError{fmt.Errorf("failed to parse proto type: %w", err)}
+	}
+
+	protoType, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(protoTypeName))
+	if err != nil {
+		return permanentError{fmt.Errorf("failed to find proto type: %w", err)}
+	}
+
+	handler, ok := c.handlers[protoType.Descriptor().FullName()]
+	if !ok { // Not a command we handle, skip it
+		return nil
+	}
+
+	ctx, span := tracing.StartKafkaReceive(ctx, m, protoTypeName)
+	defer span.End()
+
+	msg := protoType.New().Interface()
+	if err := proto.Unmarshal(m.Value, msg); err != nil {
+		return permanentError{fmt.Errorf("failed to unmarshal message: %w", err)}
+	}
+
+	ctx = auth.ContextWithIdentity(metadata.NewIncomingContext(ctx, commandMetadata(m.Headers)), commandsIdentity)
+	if err := handler(ctx, msg); err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelCodes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// commandMetadata maps the actor and reason headers of a command onto the gRPC metadata the service reads them from,
+// so they end up in the resulting notifications. The actor is who the producer says it's acting for.
+func commandMetadata(headers []kafka.Header) metadata.MD {
+	md := metadata.MD{}
+	for _, header := range headers {
+		switch header.Key {
+		case actorHeader:
+			md.Set(service.ActorMetadataKey, string(header.Value))
+		case reasonHeader:
+			md.Set(service.ReasonMetadataKey, string(header.Value))
+		}
+	}
+
+	return md
+}
+
+func (c *kafkaConsumer) handleAddRoleToPlayer(ctx context.Context, msg proto.Message) error {
+	req := msg.(*permission.AddRoleToPlayerRequest)
+
+	_, err := c.svc.AddRoleToPlayer(ctx, req)
+	if status.Code(err) == codes.AlreadyExists {
+		// Redelivered command, the player already has the role
+		return nil
+	}
+
+	return classifyError(err)
+}
+
+func (c *kafkaConsumer) handleRemoveRoleFromPlayer(ctx context.Context, msg proto.Message) error {
+	req := msg.(*permission.RemoveRoleFromPlayerRequest)
+
+	_, err := c.svc.RemoveRoleFromPlayer(ctx, req)
+	if err != nil {
+		for _, detail := range status.Convert(err).Details() {
+			if d, ok := detail.(*permission.RemoveRoleFromPlayerError); ok &&
+				d.ErrorType == permission.RemoveRoleFromPlayerError_DOES_NOT_HAVE_ROLE {
+				// Redelivered command, the role has already been removed
+				return nil
+			}
+		}
+	}
+
+	return classifyError(err)
+}
+
+// classifyError wraps errors that will never succeed on retry in a permanentError.
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied, codes.FailedPrecondition:
+		return permanentError{err}
+	default:
+		return err
+	}
+}
@@ -0,0 +1,187 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"github.com/emortalmc/proto-specs/gen/go/grpc/permission"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"permission-service/internal/auth"
+	"permission-service/internal/service"
+	"testing"
+	"time"
+)
+
+type fakePermissionService struct {
+	permission.UnimplementedPermissionServiceServer
+
+	addErr    error
+	removeErr error
+
+	addCalls    []*permission.AddRoleToPlayerRequest
+	removeCalls []*permission.RemoveRoleFromPlayerRequest
+
+	lastMetadata metadata.MD
+	lastIdentity *auth.Identity
+}
+
+func (f *fakePermissionService) AddRoleToPlayer(ctx context.Context, req *permission.AddRoleToPlayerRequest) (*permission.AddRoleToPlayerResponse, error) {
+	f.addCalls = append(f.addCalls, req)
+	f.lastMetadata, _ = metadata.FromIncomingContext(ctx)
+	f.lastIdentity, _ = auth.IdentityFromContext(ctx)
+	return &permission.AddRoleToPlayerResponse{}, f.addErr
+}
+
+func (f *fakePermissionService) RemoveRoleFromPlayer(_ context.Context, req *permission.RemoveRoleFromPlayerRequest) (*permission.RemoveRoleFromPlayerResponse, error) {
+	f.removeCalls = append(f.removeCalls, req)
+	return &permission.RemoveRoleFromPlayerResponse{}, f.removeErr
+}
+
+func createMessage(t *testing.T, msg proto.Message) *kafka.Message {
+	bytes, err := proto.Marshal(msg)
+	assert.NoError(t, err)
+
+	return &kafka.Message{
+		Value:   bytes,
+		Headers: []kafka.Header{{Key: "X-Proto-Type", Value: []byte(msg.ProtoReflect().Descriptor().FullName())}},
+	}
+}
+
+func TestKafkaConsumer_handleMessage(t *testing.T) {
+	addReq := &permission.AddRoleToPlayerRequest{PlayerId: uuid.New().String(), RoleId: "test-role"}
+	removeReq := &permission.RemoveRoleFromPlayerRequest{PlayerId: uuid.New().String(), RoleId: "test-role"}
+
+	doesNotHaveRole, _ := status.New(codes.NotFound, "player does not have role").
+		WithDetails(&permission.RemoveRoleFromPlayerError{ErrorType: permission.RemoveRoleFromPlayerError_DOES_NOT_HAVE_ROLE})
+	playerNotFound, _ := status.New(codes.NotFound, "player not found").
+		WithDetails(&permission.RemoveRoleFromPlayerError{ErrorType: permission.RemoveRoleFromPlayerError_PLAYER_NOT_FOUND})
+
+	tests := []struct {
+		name string
+
+		msg       *kafka.Message
+		addErr    error
+		removeErr error
+
+		wantAddCalls    int
+		wantRemoveCalls int
+		wantErr         bool
+		wantPermanent   bool
+	}{
+		{
+			name:         "add success",
+			msg:          createMessage(t, addReq),
+			wantAddCalls: 1,
+		},
+		{
+			name:         "add already has role is idempotent",
+			msg:          createMessage(t, addReq),
+			addErr:       status.Error(codes.AlreadyExists, "player already has role"),
+			wantAddCalls: 1,
+		},
+		{
+			name:          "add role not found is permanent",
+			msg:           createMessage(t, addReq),
+			addErr:        status.Error(codes.NotFound, "role not found"),
+			wantAddCalls:  1,
+			wantErr:       true,
+			wantPermanent: true,
+		},
+		{
+			name:         "add internal error is retryable",
+			msg:          createMessage(t, addReq),
+			addErr:       errors.New("mongo unavailable"),
+			wantAddCalls: 1,
+			wantErr:      true,
+		},
+		{
+			name:            "remove success",
+			msg:             createMessage(t, removeReq),
+			wantRemoveCalls: 1,
+		},
+		{
+			name:            "remove does not have role is idempotent",
+			msg:             createMessage(t, removeReq),
+			removeErr:       doesNotHaveRole.Err(),
+			wantRemoveCalls: 1,
+		},
+		{
+			name:            "remove player not found is permanent",
+			msg:             createMessage(t, removeReq),
+			removeErr:       playerNotFound.Err(),
+			wantRemoveCalls: 1,
+			wantErr:         true,
+			wantPermanent:   true,
+		},
+		{
+			name: "unhandled type is skipped",
+			msg:  createMessage(t, &permission.GetAllRolesRequest{}),
+		},
+		{
+			name:          "missing proto type header",
+			msg:           &kafka.Message{Value: []byte("invalid")},
+			wantErr:       true,
+			wantPermanent: true,
+		},
+		{
+			name: "invalid payload",
+			msg: &kafka.Message{
+				Value:   []byte("invalid"),
+				Headers: []kafka.Header{{Key: "X-Proto-Type", Value: []byte(addReq.ProtoReflect().Descriptor().FullName())}},
+			},
+			wantErr:       true,
+			wantPermanent: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakePermissionService{addErr: tt.addErr, removeErr: tt.removeErr}
+			c := newKafkaConsumer(zap.NewNop().Sugar(), nil, svc)
+
+			err := c.handleMessage(context.Background(), tt.msg)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+
+				var permErr permanentError
+				assert.Equal(t, tt.wantPermanent, errors.As(err, &permErr))
+			}
+
+			assert.Len(t, svc.addCalls, tt.wantAddCalls)
+			assert.Len(t, svc.removeCalls, tt.wantRemoveCalls)
+		})
+	}
+}
+
+func TestKafkaConsumer_handleMessage_Metadata(t *testing.T) {
+	msg := createMessage(t, &permission.AddRoleToPlayerRequest{PlayerId: uuid.New().String(), RoleId: "test-role"})
+	msg.Headers = append(msg.Headers,
+		kafka.Header{Key: actorHeader, Value: []byte("store-service")},
+		kafka.Header{Key: reasonHeader, Value: []byte("purchase")},
+	)
+
+	svc := &fakePermissionService{}
+	c := newKafkaConsumer(zap.NewNop().Sugar(), nil, svc)
+
+	assert.NoError(t, c.handleMessage(context.Background(), msg))
+	assert.Equal(t, []string{"store-service"}, svc.lastMetadata.Get(service.ActorMetadataKey))
+	assert.Equal(t, []string{"purchase"}, svc.lastMetadata.Get(service.ReasonMetadataKey))
+	// Commands are attributed to the topic, the producer's actor is only what it claims to act for
+	assert.Equal(t, CommandsActor, svc.lastIdentity.Name)
+}
+
+func TestSleep(t *testing.T) {
+	assert.True(t, sleep(context.Background(), time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, sleep(ctx, time.Hour))
+}
@@ -0,0 +1,199 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/emortalmc/proto-specs/gen/go/message/permission"
+	"github.com/emortalmc/proto-specs/gen/go/nongenerated/kafkautils"
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"permission-service/internal/config"
+	"permission-service/internal/messaging/notifier"
+	"permission-service/internal/repository/model"
+	"permission-service/internal/tracing"
+	"sync"
+	"time"
+)
+
+// eventTimestampHeader and onBehalfOfHeader are set by the kafka notifier alongside the actor and reason headers
+const (
+	eventTimestampHeader = "X-Event-Timestamp"
+	onBehalfOfHeader     = "X-On-Behalf-Of"
+)
+
+type eventListener struct {
+	logger *zap.SugaredLogger
+	target notifier.Notifier
+}
+
+// NewKafkaEventListener forwards the role and player role changes published by every replica to target.
+// Each replica reads every partition from the latest offset without a consumer group, so it sees every change
+// made while it runs and leaves no group behind on the broker. Partitions added while it runs aren't read.
+func NewKafkaEventListener(ctx context.Context, wg *sync.WaitGroup, logger *zap.SugaredLogger, cfg config.KafkaConfig,
+	target notifier.Notifier) {
+
+	broker := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+	l := &eventListener{logger: logger, target: target}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		partitions, ok := l.partitions(ctx, broker)
+		if !ok {
+			return
+		}
+
+		var readers sync.WaitGroup
+		for _, partition := range partitions {
+			reader := kafka.NewReader(kafka.ReaderConfig{
+				Brokers:     []string{broker},
+				Topic:       notifier.Topic,
+				Partition:   partition,
+				Logger:      kafkautils.CreateLogger(logger),
+				ErrorLogger: kafkautils.CreateErrorLogger(logger),
+			})
+			// StartOffset only applies to consumer groups
+			if err := reader.SetOffset(kafka.LastOffset); err != nil {
+				logger.Errorw("failed to set kafka reader offset", "error", err, "partition", partition)
+			}
+
+			readers.Add(1)
+			go func(reader *kafka.Reader, partition int) {
+				defer readers.Done()
+				l.run(ctx, reader)
+
+				if err := reader.Close(); err != nil {
+					logger.Errorw("failed to close kafka reader", "error", err, "partition", partition)
+				}
+			}(reader, partition)
+		}
+
+		readers.Wait()
+		logger.Info("shutting down kafka event listener")
+	}()
+}
+
+// partitions returns the partitions of the events topic, retrying with backoff until the broker answers.
+// It returns false only if ctx is cancelled first.
+func (l *eventListener) partitions(ctx context.Context, broker string) ([]int, bool) {
+	backoff := minRetryBackoff
+	for {
+		partitions, err := readPartitions(ctx, broker)
+		if err == nil {
+			return partitions, true
+		}
+		if ctx.Err() != nil {
+			return nil, false
+		}
+
+		l.logger.Errorw("failed to read permission event partitions", "error", err, "backoff", backoff)
+		if !sleep(ctx, backoff) {
+			return nil, false
+		}
+		backoff = min(backoff*2, maxRetryBackoff)
+	}
+}
+
+func readPartitions(ctx context.Context, broker string) ([]int, error) {
+	conn, err := kafka.DialContext(ctx, "tcp", broker)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	partitions, err := conn.ReadPartitions(notifier.Topic)
+	if err != nil {
+		return nil, err
+	}
+	if len(partitions) == 0 {
+		return nil, fmt.Errorf("topic %s has no partitions", notifier.Topic)
+	}
+
+	ids := make([]int, len(partitions))
+	for i, partition := range partitions {
+		ids[i] = partition.ID
+	}
+	return ids, nil
+}
+
+func (l *eventListener) run(ctx context.Context, reader *kafka.Reader) {
+	readBackoff := minRetryBackoff
+	for {
+		m, err := reader.ReadMessage(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, io.EOF) {
+				return
+			}
+			// Backs off like the commands consumer, so a broker outage doesn't become a busy loop of errors
+			l.logger.Errorw("failed to read permission event", "error", err, "backoff", readBackoff)
+			if !sleep(ctx, readBackoff) {
+				return
+			}
+			readBackoff = min(readBackoff*2, maxRetryBackoff)
+			continue
+		}
+		readBackoff = minRetryBackoff
+
+		if err := l.handleMessage(ctx, &m); err != nil {
+			l.logger.Errorw("failed to handle permission event", "error", err, "partition", m.Partition, "offset", m.Offset)
+		}
+	}
+}
+
+func (l *eventListener) handleMessage(ctx context.Context, m *kafka.Message) error {
+	protoType, err := kafkautils.ProtoTypeFromHeaders(m.Headers)
+	if err != nil {
+		return fmt.Errorf("failed to parse proto type: %w", err)
+	}
+	meta := eventMeta(m)
+
+	ctx, span := tracing.StartKafkaReceive(ctx, m, protoType)
+	defer span.End()
+
+	switch protoType {
+	case string((&permission.RoleUpdateMessage{}).ProtoReflect().Descriptor().FullName()):
+		msg := &permission.RoleUpdateMessage{}
+		if err := proto.Unmarshal(m.Value, msg); err != nil {
+			return fmt.Errorf("failed to unmarshal role update: %w", err)
+		}
+		if msg.Role == nil {
+			return nil
+		}
+
+		return l.target.RoleUpdate(ctx, nil, model.RoleFromProto(msg.Role), msg.ChangeType, meta)
+	case string((&permission.PlayerRolesUpdateMessage{}).ProtoReflect().Descriptor().FullName()):
+		msg := &permission.PlayerRolesUpdateMessage{}
+		if err := proto.Unmarshal(m.Value, msg); err != nil {
+			return fmt.Errorf("failed to unmarshal player roles update: %w", err)
+		}
+
+		return l.target.PlayerRolesUpdate(ctx, msg.PlayerId, msg.RoleId, msg.ChangeType, meta)
+	default:
+		return nil
+	}
+}
+
+func eventMeta(m *kafka.Message) notifier.ChangeMeta {
+	meta := notifier.ChangeMeta{Timestamp: m.Time}
+
+	for _, header := range m.Headers {
+		switch header.Key {
+		case actorHeader:
+			meta.Actor = string(header.Value)
+		case onBehalfOfHeader:
+			meta.OnBehalfOf = string(header.Value)
+		case reasonHeader:
+			meta.Reason = string(header.Value)
+		case eventTimestampHeader:
+			if t, err := time.Parse(time.RFC3339Nano, string(header.Value)); err == nil {
+				meta.Timestamp = t
+			}
+		}
+	}
+
+	return meta
+}
@@ -0,0 +1,60 @@
+package consumer
+
+import (
+	"context"
+	"github.com/emortalmc/proto-specs/gen/go/message/permission"
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"permission-service/internal/messaging/notifier"
+	"testing"
+	"time"
+)
+
+func TestEventListener_handleMessage(t *testing.T) {
+	target := notifier.NewMemoryNotifier()
+	events, unsubscribe := target.Subscribe(8)
+	defer unsubscribe()
+
+	l := &eventListener{logger: zap.NewNop().Sugar(), target: target}
+
+	timestamp := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	roleMsg := createMessage(t, &permission.RoleUpdateMessage{
+		ChangeType: permission.RoleUpdateMessage_MODIFY,
+		Role:       &protoModel.Role{Id: "vip", Priority: 10},
+	})
+	roleMsg.Headers = append(roleMsg.Headers,
+		kafka.Header{Key: actorHeader, Value: []byte("service:discord-bot")},
+		kafka.Header{Key: onBehalfOfHeader, Value: []byte("8d36737e-1c0a-4a71-87de-9906f577845e")},
+		kafka.Header{Key: eventTimestampHeader, Value: []byte(timestamp.Format(time.RFC3339Nano))},
+	)
+	require.NoError(t, l.handleMessage(context.Background(), roleMsg))
+
+	event := <-events
+	assert.True(t, event.IsRoleUpdate())
+	assert.Equal(t, "vip", event.Role.Id)
+	assert.Equal(t, uint32(10), event.Role.Priority)
+	assert.Equal(t, permission.RoleUpdateMessage_MODIFY, event.RoleChangeType)
+	assert.Equal(t, "service:discord-bot", event.Meta.Actor)
+	assert.Equal(t, "8d36737e-1c0a-4a71-87de-9906f577845e", event.Meta.OnBehalfOf)
+	assert.True(t, timestamp.Equal(event.Meta.Timestamp))
+
+	playerMsg := createMessage(t, &permission.PlayerRolesUpdateMessage{
+		ChangeType: permission.PlayerRolesUpdateMessage_REMOVE,
+		PlayerId:   "8d36737e-1c0a-4a71-87de-9906f577845e",
+		RoleId:     "vip",
+	})
+	require.NoError(t, l.handleMessage(context.Background(), playerMsg))
+
+	event = <-events
+	assert.False(t, event.IsRoleUpdate())
+	assert.Equal(t, "8d36737e-1c0a-4a71-87de-9906f577845e", event.PlayerId)
+	assert.Equal(t, "vip", event.RoleId)
+	assert.Equal(t, permission.PlayerRolesUpdateMessage_REMOVE, event.PlayerRolesChangeType)
+
+	// Messages of other types published to the topic are ignored
+	require.NoError(t, l.handleMessage(context.Background(), createMessage(t, &protoModel.Role{Id: "vip"})))
+	assert.Empty(t, events)
+}
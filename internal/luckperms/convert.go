@@ -0,0 +1,264 @@
+package luckperms
+
+import (
+	"fmt"
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+	"permission-service/internal/repository/model"
+	"permission-service/internal/validation"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Issue is part of the export that couldn't be imported.
+type Issue struct {
+	// Subject is the group or user the issue is with, e.g. "group admin" or "user Notch (069a79f4-...)"
+	Subject string `json:"subject"`
+	Node    string `json:"node,omitempty"`
+	Reason  string `json:"reason"`
+}
+
+// Result is an export converted to roles and players.
+type Result struct {
+	Roles   []*model.Role
+	Players []*model.Player
+	// Issues are everything that was left out, so it can be migrated by hand
+	Issues []Issue
+
+	defaultRoleId string
+}
+
+// Convert maps groups to roles and users to players, who are all given the default role. Anything without an
+// equivalent (contexts, temporary nodes, inheritance, suffixes and meta) is skipped and reported.
+func Convert(export *Export, defaultRoleId string) *Result {
+	res := &Result{defaultRoleId: defaultRoleId}
+
+	groupNames := make([]string, 0, len(export.Groups))
+	for name := range export.Groups {
+		groupNames = append(groupNames, name)
+	}
+	sort.Strings(groupNames)
+
+	roleIds := make(map[string]bool, len(groupNames))
+	for _, name := range groupNames {
+		if role := res.convertGroup(name, export.Groups[name]); role != nil {
+			res.Roles = append(res.Roles, role)
+			roleIds[role.Id] = true
+		}
+	}
+
+	userIds := make([]string, 0, len(export.Users))
+	for id := range export.Users {
+		userIds = append(userIds, id)
+	}
+	sort.Strings(userIds)
+
+	for _, id := range userIds {
+		if player := res.convertUser(id, export.Users[id], roleIds); player != nil {
+			res.Players = append(res.Players, player)
+		}
+	}
+
+	return res
+}
+
+func (r *Result) report(subject string, node string, reason string, args ...any) {
+	r.Issues = append(r.Issues, Issue{Subject: subject, Node: node, Reason: fmt.Sprintf(reason, args...)})
+}
+
+func (r *Result) convertGroup(name string, group Group) *model.Role {
+	subject := "group " + name
+
+	id := strings.ToLower(name)
+	if err := validateField(func(v *validation.Violations) { v.Id("id", id) }); err != "" {
+		r.report(subject, "", "name can't be used as a role id: %s", err)
+		return nil
+	}
+
+	role := &model.Role{Id: id, Permissions: make([]model.PermissionNode, 0)}
+	prefixPriority, prefixKey := -1, ""
+	nodes := make(map[string]bool)
+
+	for _, node := range group.Nodes {
+		if !r.checkPermanent(subject, node) {
+			continue
+		}
+
+		switch node.NodeType() {
+		case nodeTypePermission:
+			if !r.checkNode(subject, node, nodes) {
+				continue
+			}
+			role.Permissions = append(role.Permissions, model.PermissionNode{Node: node.Key, State: nodeState(node)})
+		case nodeTypeWeight:
+			weight, err := strconv.ParseUint(strings.TrimPrefix(node.Key, "weight."), 10, 32)
+			if err != nil {
+				r.report(subject, node.Key, "invalid weight")
+				continue
+			}
+			if weight > validation.MaxPriority {
+				r.report(subject, node.Key, "weight is above the maximum priority, so it's capped at %d", validation.MaxPriority)
+				weight = validation.MaxPriority
+			}
+			// LuckPerms uses the highest weight if a group has several
+			if uint32(weight) > role.Priority {
+				role.Priority = uint32(weight)
+			}
+		case nodeTypePrefix:
+			priority, prefix, ok := parsePrefix(node.Key)
+			if !ok {
+				r.report(subject, node.Key, "invalid prefix")
+				continue
+			}
+			// Roles have a single display name, so only the highest priority prefix is kept
+			if priority <= prefixPriority {
+				r.report(subject, node.Key, "lower priority prefix, only the highest priority prefix is used")
+				continue
+			}
+			if prefixKey != "" {
+				r.report(subject, prefixKey, "lower priority prefix, only the highest priority prefix is used")
+			}
+			displayName := legacyToMiniMessage(prefix) + "{{.Username}}"
+			role.DisplayName = &displayName
+			prefixPriority, prefixKey = priority, node.Key
+		case nodeTypeInheritance:
+			r.report(subject, node.Key, "roles can't inherit from other roles, the parent's nodes must be added to the role")
+		default:
+			r.report(subject, node.Key, "%s nodes aren't supported", node.NodeType())
+		}
+	}
+
+	if role.DisplayName != nil {
+		if err := validateField(func(v *validation.Violations) { v.DisplayName("display_name", *role.DisplayName) }); err != "" {
+			r.report(subject, "", "prefix can't be used as a display name: %s", err)
+			role.DisplayName = nil
+		}
+	}
+
+	return role
+}
+
+func (r *Result) convertUser(id string, user User, roleIds map[string]bool) *model.Player {
+	subject := "user " + id
+	if user.Username != "" {
+		subject = fmt.Sprintf("user %s (%s)", user.Username, id)
+	}
+
+	playerId, err := uuid.Parse(id)
+	if err != nil {
+		r.report(subject, "", "invalid UUID")
+		return nil
+	}
+
+	player := &model.Player{Id: playerId, Roles: []string{r.defaultRoleId}}
+	held := map[string]bool{r.defaultRoleId: true}
+
+	addRole := func(group string, node string) {
+		roleId := strings.ToLower(group)
+		if held[roleId] {
+			return
+		}
+		if !roleIds[roleId] {
+			r.report(subject, node, "group %s wasn't imported", group)
+			return
+		}
+
+		held[roleId] = true
+		player.Roles = append(player.Roles, roleId)
+	}
+
+	if user.PrimaryGroup != "" {
+		addRole(user.PrimaryGroup, "")
+	}
+
+	for _, node := range user.Nodes {
+		if !r.checkPermanent(subject, node) {
+			continue
+		}
+
+		switch node.NodeType() {
+		case nodeTypeInheritance:
+			if !node.Granted() {
+				r.report(subject, node.Key, "negated groups aren't supported")
+				continue
+			}
+			addRole(strings.TrimPrefix(node.Key, "group."), node.Key)
+		case nodeTypePermission:
+			r.report(subject, node.Key, "players can't hold permissions directly, only through roles")
+		default:
+			r.report(subject, node.Key, "%s nodes aren't supported", node.NodeType())
+		}
+	}
+
+	return player
+}
+
+// checkPermanent reports contextual and temporary nodes, which can't be imported.
+func (r *Result) checkPermanent(subject string, node Node) bool {
+	if len(node.Context) > 0 {
+		r.report(subject, node.Key, "contextual nodes aren't supported")
+		return false
+	}
+	if node.Expiry != 0 {
+		r.report(subject, node.Key, "temporary nodes aren't supported")
+		return false
+	}
+	return true
+}
+
+func (r *Result) checkNode(subject string, node Node, seen map[string]bool) bool {
+	if err := validateField(func(v *validation.Violations) { v.Node("node", node.Key) }); err != "" {
+		r.report(subject, node.Key, "invalid permission node: %s", err)
+		return false
+	}
+	if seen[node.Key] {
+		r.report(subject, node.Key, "node is set more than once")
+		return false
+	}
+
+	seen[node.Key] = true
+	return true
+}
+
+func nodeState(node Node) protoModel.PermissionNode_PermissionState {
+	if node.Granted() {
+		return protoModel.PermissionNode_ALLOW
+	}
+	return protoModel.PermissionNode_DENY
+}
+
+// parsePrefix parses a "prefix.<priority>.<prefix>" key. The prefix itself may contain dots.
+func parsePrefix(key string) (int, string, bool) {
+	parts := strings.SplitN(key, ".", 3)
+	if len(parts) != 3 || parts[0] != nodeTypePrefix {
+		return 0, "", false
+	}
+
+	priority, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, "", false
+	}
+
+	return priority, parts[2], true
+}
+
+// validateField runs a validation rule, returning the description of the violation if there is one.
+func validateField(check func(v *validation.Violations)) string {
+	v := &validation.Violations{}
+	check(v)
+
+	err := v.Err()
+	if err == nil {
+		return ""
+	}
+
+	for _, detail := range status.Convert(err).Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok && len(badRequest.FieldViolations) > 0 {
+			return badRequest.FieldViolations[0].Description
+		}
+	}
+	return status.Convert(err).Message()
+}
@@ -0,0 +1,121 @@
+package luckperms
+
+import (
+	"bytes"
+	"compress/gzip"
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"permission-service/internal/repository/model"
+	"permission-service/internal/utils"
+	"testing"
+)
+
+const testExport = `{
+  "metadata": {"generatedBy": "Notch", "generatedAt": "2024-01-01 12:00:00"},
+  "groups": {
+    "admin": {
+      "nodes": [
+        {"type": "weight", "key": "weight.100", "value": true},
+        {"type": "prefix", "key": "prefix.10.&7[Staff] ", "value": true},
+        {"type": "prefix", "key": "prefix.100.&c&l[Admin] ", "value": true},
+        {"type": "permission", "key": "command.ban", "value": true},
+        {"type": "permission", "key": "command.gamemode", "value": false},
+        {"type": "permission", "key": "worldedit.*", "value": true, "context": {"server": ["build"]}},
+        {"type": "permission", "key": "essentials.fly", "value": true, "expiry": 1700000000},
+        {"type": "inheritance", "key": "group.default", "value": true},
+        {"type": "suffix", "key": "suffix.10. &f", "value": true}
+      ]
+    },
+    "default": {
+      "nodes": [{"key": "chat.*"}]
+    },
+    "Bad Group": {
+      "nodes": []
+    }
+  },
+  "users": {
+    "069a79f4-44e9-4726-a5be-fca90e38aaf5": {
+      "username": "Notch",
+      "primaryGroup": "admin",
+      "nodes": [
+        {"type": "inheritance", "key": "group.admin", "value": true},
+        {"type": "inheritance", "key": "group.bad group", "value": true},
+        {"type": "permission", "key": "command.kick", "value": true}
+      ]
+    },
+    "61699b2e-d327-4a01-9f1e-0ea8c3f06bc6": {
+      "username": "jeb_",
+      "primaryGroup": "default",
+      "nodes": [{"key": "group.default"}]
+    },
+    "not-a-uuid": {"username": "broken", "primaryGroup": "default"}
+  }
+}`
+
+func TestConvert(t *testing.T) {
+	export, err := Parse([]byte(testExport), FormatJSON)
+	require.NoError(t, err)
+
+	res := Convert(export, model.DefaultRoleId)
+
+	assert.Equal(t, []*model.Role{
+		{Id: "admin", Priority: 100, DisplayName: utils.PointerOf("<red><bold>[Admin] {{.Username}}"), Permissions: []model.PermissionNode{
+			{Node: "command.ban", State: protoModel.PermissionNode_ALLOW},
+			{Node: "command.gamemode", State: protoModel.PermissionNode_DENY},
+		}},
+		{Id: "default", Permissions: []model.PermissionNode{{Node: "chat.*", State: protoModel.PermissionNode_ALLOW}}},
+	}, res.Roles)
+
+	assert.Equal(t, []*model.Player{
+		{Id: uuid.MustParse("069a79f4-44e9-4726-a5be-fca90e38aaf5"), Roles: []string{"default", "admin"}},
+		{Id: uuid.MustParse("61699b2e-d327-4a01-9f1e-0ea8c3f06bc6"), Roles: []string{"default"}},
+	}, res.Players)
+
+	reported := make([]string, len(res.Issues))
+	for i, issue := range res.Issues {
+		reported[i] = issue.Subject + ": " + issue.Node
+	}
+	assert.Equal(t, []string{
+		"group Bad Group: ",
+		"group admin: prefix.10.&7[Staff] ",
+		"group admin: worldedit.*",
+		"group admin: essentials.fly",
+		"group admin: group.default",
+		"group admin: suffix.10. &f",
+		"user Notch (069a79f4-44e9-4726-a5be-fca90e38aaf5): group.bad group",
+		"user Notch (069a79f4-44e9-4726-a5be-fca90e38aaf5): command.kick",
+		"user broken (not-a-uuid): ",
+	}, reported)
+}
+
+func TestParse_GzipYAML(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	_, err := w.Write([]byte("groups:\n  vip:\n    nodes:\n      - key: weight.10\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	export, err := Parse(buf.Bytes(), FormatFromPath("export.YML.gz"))
+	require.NoError(t, err)
+
+	res := Convert(export, model.DefaultRoleId)
+	assert.Equal(t, []*model.Role{{Id: "vip", Priority: 10, Permissions: []model.PermissionNode{}}}, res.Roles)
+	assert.Empty(t, res.Issues)
+}
+
+func TestLegacyToMiniMessage(t *testing.T) {
+	tests := map[string]string{
+		"&c[Admin] ":         "<red>[Admin] ",
+		"§6§lVIP ":           "<gold><bold>VIP ",
+		"&#ff8800Builder &r": "<#ff8800>Builder <reset>",
+		"R&D & more":         "R<light_purple> & more",
+		"<blue>already ":     "<blue>already ",
+		"trailing &":         "trailing &",
+	}
+
+	for legacy, expected := range tests {
+		assert.Equal(t, expected, legacyToMiniMessage(legacy), legacy)
+	}
+}
@@ -0,0 +1,122 @@
+package luckperms
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// Node types, as written by LuckPerms 5. Older exports leave the type out, so it's inferred from the key.
+const (
+	nodeTypePermission  = "permission"
+	nodeTypeInheritance = "inheritance"
+	nodeTypeWeight      = "weight"
+	nodeTypePrefix      = "prefix"
+	nodeTypeSuffix      = "suffix"
+	nodeTypeMeta        = "meta"
+	nodeTypeDisplayName = "display_name"
+	nodeTypeRegex       = "regex_permission"
+)
+
+// Export is the part of a LuckPerms export (/lp export) that can be imported. Tracks are ignored.
+type Export struct {
+	Groups map[string]Group `json:"groups" yaml:"groups"`
+	Users  map[string]User  `json:"users" yaml:"users"`
+}
+
+type Group struct {
+	Nodes []Node `json:"nodes" yaml:"nodes"`
+}
+
+type User struct {
+	Username     string `json:"username" yaml:"username"`
+	PrimaryGroup string `json:"primaryGroup" yaml:"primaryGroup"`
+	Nodes        []Node `json:"nodes" yaml:"nodes"`
+}
+
+type Node struct {
+	Type  string `json:"type" yaml:"type"`
+	Key   string `json:"key" yaml:"key"`
+	Value *bool  `json:"value" yaml:"value"`
+	// Expiry is the unix time a temporary node expires at, 0 if permanent
+	Expiry int64 `json:"expiry" yaml:"expiry"`
+	// Context limits where the node applies (e.g. {"server": "lobby"})
+	Context map[string]any `json:"context" yaml:"context"`
+}
+
+// NodeType returns the type of the node, inferring it from the key if the export doesn't include it.
+func (n Node) NodeType() string {
+	if n.Type != "" {
+		return n.Type
+	}
+
+	prefix, _, _ := strings.Cut(n.Key, ".")
+	switch prefix {
+	case "group":
+		return nodeTypeInheritance
+	case nodeTypeWeight, nodeTypePrefix, nodeTypeSuffix, nodeTypeMeta:
+		return prefix
+	case "displayname":
+		return nodeTypeDisplayName
+	}
+	if strings.HasPrefix(n.Key, "r=") || strings.HasPrefix(n.Key, "R=") {
+		return nodeTypeRegex
+	}
+	return nodeTypePermission
+}
+
+// Granted returns the value of the node. LuckPerms treats a node without a value as true.
+func (n Node) Granted() bool {
+	return n.Value == nil || *n.Value
+}
+
+// FormatFromPath returns the format of an export by its extension (ignoring .gz), defaulting to JSON as written by LuckPerms.
+func FormatFromPath(path string) string {
+	path = strings.TrimSuffix(strings.ToLower(path), ".gz")
+	switch filepath.Ext(path) {
+	case ".yml", ".yaml":
+		return FormatYAML
+	default:
+		return FormatJSON
+	}
+}
+
+// Parse reads an export, decompressing it first if it's gzipped (as /lp export writes it).
+func Parse(data []byte, format string) (*Export, error) {
+	// gzip magic number
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress export: %w", err)
+		}
+		if data, err = io.ReadAll(r); err != nil {
+			return nil, fmt.Errorf("failed to decompress export: %w", err)
+		}
+	}
+
+	export := &Export{}
+	switch format {
+	case FormatJSON:
+		if err := json.Unmarshal(data, export); err != nil {
+			return nil, err
+		}
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, export); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+
+	return export, nil
+}
@@ -0,0 +1,48 @@
+package luckperms
+
+import (
+	"regexp"
+	"strings"
+)
+
+// legacyCodes maps legacy formatting codes (&c, §l) to MiniMessage tags, as display names are MiniMessage templates
+var legacyCodes = map[byte]string{
+	'0': "<black>", '1': "<dark_blue>", '2': "<dark_green>", '3': "<dark_aqua>",
+	'4': "<dark_red>", '5': "<dark_purple>", '6': "<gold>", '7': "<gray>",
+	'8': "<dark_gray>", '9': "<blue>", 'a': "<green>", 'b': "<aqua>",
+	'c': "<red>", 'd': "<light_purple>", 'e': "<yellow>", 'f': "<white>",
+	'k': "<obfuscated>", 'l': "<bold>", 'm': "<strikethrough>", 'n': "<underlined>",
+	'o': "<italic>", 'r': "<reset>",
+}
+
+// legacyHexPattern matches hex colours written as &#rrggbb
+var legacyHexPattern = regexp.MustCompile(`[&§]#([0-9a-fA-F]{6})`)
+
+// legacyToMiniMessage converts legacy formatting codes in s to MiniMessage tags. Other text is left as it is.
+func legacyToMiniMessage(s string) string {
+	s = legacyHexPattern.ReplaceAllString(s, "<#$1>")
+
+	// § is two bytes in UTF-8, so it's replaced with & first to handle both the same way
+	s = strings.ReplaceAll(s, "§", "&")
+
+	b := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		if s[i] == '&' && i+1 < len(s) {
+			if tag, ok := legacyCodes[toLower(s[i+1])]; ok {
+				b.WriteString(tag)
+				i++
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+func toLower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
@@ -0,0 +1,150 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/emortalmc/proto-specs/gen/go/message/permission"
+	"permission-service/internal/repository/model"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Backend is a named Notifier that a fan-out notifier sends events to.
+type Backend struct {
+	Name     string
+	Notifier Notifier
+}
+
+// BackendStats is a snapshot of the delivery counters for a single backend.
+type BackendStats struct {
+	Sent        uint64
+	Failed      uint64
+	LastLatency time.Duration
+}
+
+type backendCounters struct {
+	sent        atomic.Uint64
+	failed      atomic.Uint64
+	lastLatency atomic.Int64
+}
+
+type fanOutNotifier struct {
+	backends []Backend
+	counters map[string]*backendCounters
+}
+
+// FanOutNotifier is a Notifier that sends every event to several backends.
+type FanOutNotifier interface {
+	Notifier
+	// HealthChecker checks every backend that depends on an external system
+	HealthChecker
+
+	// Stats returns the delivery counters for every backend, keyed by backend name.
+	Stats() map[string]BackendStats
+}
+
+// NewFanOutNotifier creates a Notifier that sends every event to all backends in parallel.
+// A failing (or panicking) backend does not prevent delivery to the others,
+// and the returned error joins the errors of every backend that failed.
+func NewFanOutNotifier(backends ...Backend) FanOutNotifier {
+	counters := make(map[string]*backendCounters, len(backends))
+	for _, b := range backends {
+		counters[b.Name] = &backendCounters{}
+	}
+
+	return &fanOutNotifier{
+		backends: backends,
+		counters: counters,
+	}
+}
+
+func (f *fanOutNotifier) RoleUpdate(ctx context.Context, previous *model.Role, role *model.Role,
+	changeType permission.RoleUpdateMessage_ChangeType, meta ChangeMeta) error {
+
+	return f.fanOut(func(n Notifier) error {
+		return n.RoleUpdate(ctx, previous, role, changeType, meta)
+	})
+}
+
+func (f *fanOutNotifier) PlayerRolesUpdate(ctx context.Context, playerId string, roleId string,
+	changeType permission.PlayerRolesUpdateMessage_ChangeType, meta ChangeMeta) error {
+
+	return f.fanOut(func(n Notifier) error {
+		return n.PlayerRolesUpdate(ctx, playerId, roleId, changeType, meta)
+	})
+}
+
+func (f *fanOutNotifier) PlayerRolesUpdates(ctx context.Context, changes []PlayerRolesChange, meta ChangeMeta) error {
+	return f.fanOut(func(n Notifier) error {
+		return n.PlayerRolesUpdates(ctx, changes, meta)
+	})
+}
+
+func (f *fanOutNotifier) CheckHealth(ctx context.Context) error {
+	errs := make([]error, 0)
+	for _, backend := range f.backends {
+		checker, ok := backend.Notifier.(HealthChecker)
+		if !ok {
+			continue
+		}
+
+		if err := checker.CheckHealth(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", backend.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (f *fanOutNotifier) Stats() map[string]BackendStats {
+	stats := make(map[string]BackendStats, len(f.counters))
+	for name, c := range f.counters {
+		stats[name] = BackendStats{
+			Sent:        c.sent.Load(),
+			Failed:      c.failed.Load(),
+			LastLatency: time.Duration(c.lastLatency.Load()),
+		}
+	}
+
+	return stats
+}
+
+func (f *fanOutNotifier) fanOut(send func(n Notifier) error) error {
+	errs := make([]error, len(f.backends))
+
+	wg := &sync.WaitGroup{}
+	for i, backend := range f.backends {
+		wg.Add(1)
+		go func(i int, backend Backend) {
+			defer wg.Done()
+			if err := f.sendTo(backend, send); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", backend.Name, err)
+			}
+		}(i, backend)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func (f *fanOutNotifier) sendTo(backend Backend, send func(n Notifier) error) (err error) {
+	counters := f.counters[backend.Name]
+	start := time.Now()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("notifier panicked: %v", r)
+		}
+
+		counters.lastLatency.Store(int64(time.Since(start)))
+		if err != nil {
+			counters.failed.Add(1)
+		} else {
+			counters.sent.Add(1)
+		}
+	}()
+
+	return send(backend.Notifier)
+}
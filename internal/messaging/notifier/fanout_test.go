@@ -0,0 +1,102 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"github.com/emortalmc/proto-specs/gen/go/message/permission"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"permission-service/internal/repository/model"
+	"testing"
+	"time"
+)
+
+type panickingNotifier struct {
+	Notifier
+}
+
+func (p panickingNotifier) RoleUpdate(context.Context, *model.Role, *model.Role, permission.RoleUpdateMessage_ChangeType, ChangeMeta) error {
+	panic("boom")
+}
+
+func TestFanOutNotifier_RoleUpdate(t *testing.T) {
+	mockCntrl := gomock.NewController(t)
+	healthy := NewMockNotifier(mockCntrl)
+	failing := NewMockNotifier(mockCntrl)
+
+	role := &model.Role{Id: "test-role"}
+	meta := ChangeMeta{Actor: "test-actor", Timestamp: time.Now()}
+	healthy.EXPECT().RoleUpdate(context.Background(), gomock.Nil(), role, permission.RoleUpdateMessage_CREATE, meta).Return(nil)
+	failing.EXPECT().RoleUpdate(context.Background(), gomock.Nil(), role, permission.RoleUpdateMessage_CREATE, meta).Return(errors.New("unavailable"))
+
+	n := NewFanOutNotifier(
+		Backend{Name: "healthy", Notifier: healthy},
+		Backend{Name: "failing", Notifier: failing},
+		Backend{Name: "panicking", Notifier: panickingNotifier{}},
+	)
+
+	err := n.RoleUpdate(context.Background(), nil, role, permission.RoleUpdateMessage_CREATE, meta)
+	assert.ErrorContains(t, err, "failing: unavailable")
+	assert.ErrorContains(t, err, "panicking: notifier panicked: boom")
+	assert.NotContains(t, err.Error(), "healthy")
+
+	stats := n.Stats()
+	assert.Equal(t, uint64(1), stats["healthy"].Sent)
+	assert.Equal(t, uint64(0), stats["healthy"].Failed)
+	assert.Equal(t, uint64(1), stats["failing"].Failed)
+	assert.Equal(t, uint64(1), stats["panicking"].Failed)
+}
+
+func TestFanOutNotifier_PlayerRolesUpdate(t *testing.T) {
+	mockCntrl := gomock.NewController(t)
+	first := NewMockNotifier(mockCntrl)
+	second := NewMockNotifier(mockCntrl)
+
+	first.EXPECT().PlayerRolesUpdate(context.Background(), "player", "role", permission.PlayerRolesUpdateMessage_ADD, ChangeMeta{}).Return(nil)
+	second.EXPECT().PlayerRolesUpdate(context.Background(), "player", "role", permission.PlayerRolesUpdateMessage_ADD, ChangeMeta{}).Return(nil)
+
+	n := NewFanOutNotifier(Backend{Name: "first", Notifier: first}, Backend{Name: "second", Notifier: second})
+
+	assert.NoError(t, n.PlayerRolesUpdate(context.Background(), "player", "role", permission.PlayerRolesUpdateMessage_ADD, ChangeMeta{}))
+	assert.Equal(t, uint64(1), n.Stats()["first"].Sent)
+	assert.Equal(t, uint64(1), n.Stats()["second"].Sent)
+}
+
+func TestFanOutNotifier_PlayerRolesUpdates(t *testing.T) {
+	mockCntrl := gomock.NewController(t)
+	backend := NewMockNotifier(mockCntrl)
+
+	changes := []PlayerRolesChange{
+		{PlayerId: "first-player", RoleId: "role", ChangeType: permission.PlayerRolesUpdateMessage_ADD},
+		{PlayerId: "second-player", RoleId: "role", ChangeType: permission.PlayerRolesUpdateMessage_ADD},
+	}
+	backend.EXPECT().PlayerRolesUpdates(context.Background(), changes, ChangeMeta{}).Return(nil)
+
+	n := NewFanOutNotifier(Backend{Name: "backend", Notifier: backend})
+
+	assert.NoError(t, n.PlayerRolesUpdates(context.Background(), changes, ChangeMeta{}))
+	assert.Equal(t, uint64(1), n.Stats()["backend"].Sent)
+}
+
+type checkedNotifier struct {
+	Notifier
+	err error
+}
+
+func (c checkedNotifier) CheckHealth(context.Context) error {
+	return c.err
+}
+
+func TestFanOutNotifier_CheckHealth(t *testing.T) {
+	n := NewFanOutNotifier(
+		Backend{Name: "log", Notifier: NewLogNotifier(nil)},
+		Backend{Name: "healthy", Notifier: checkedNotifier{}},
+	)
+	assert.NoError(t, n.CheckHealth(context.Background()))
+
+	n = NewFanOutNotifier(
+		Backend{Name: "healthy", Notifier: checkedNotifier{}},
+		Backend{Name: "unreachable", Notifier: checkedNotifier{err: errors.New("connection refused")}},
+	)
+	assert.EqualError(t, n.CheckHealth(context.Background()), "unreachable: connection refused")
+}
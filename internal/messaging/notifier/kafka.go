@@ -0,0 +1,220 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/emortalmc/proto-specs/gen/go/message/permission"
+	pbmodel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"permission-service/internal/config"
+	"permission-service/internal/repository"
+	"permission-service/internal/repository/model"
+	"permission-service/internal/tracing"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Topic is where role and player role changes are published
+const Topic = "permission-manager"
+
+// kafkaBatchSize is the most messages written at once, keeping batches well within the broker's request size limit
+const kafkaBatchSize = 500
+
+const (
+	actorHeader         = "X-Actor"
+	onBehalfOfHeader    = "X-On-Behalf-Of"
+	reasonHeader        = "X-Reason"
+	timestampHeader     = "X-Event-Timestamp"
+	changedFieldsHeader = "X-Changed-Fields"
+	roleDiffHeader      = "X-Role-Diff"
+)
+
+type kafkaNotifier struct {
+	logger *zap.SugaredLogger
+	w      *kafka.Writer
+
+	snapshots *roleSnapshotWriter
+}
+
+func NewKafkaNotifier(ctx context.Context, wg *sync.WaitGroup, logger *zap.SugaredLogger, cfg config.KafkaConfig,
+	repo repository.Repository) Notifier {
+
+	addr := kafka.TCP(fmt.Sprintf("%s:%d", cfg.Host, cfg.Port))
+
+	w := &kafka.Writer{
+		Addr:        addr,
+		Topic:       Topic,
+		Async:       true,
+		Balancer:    &kafka.LeastBytes{},
+		ErrorLogger: zap.NewStdLog(zap.L()),
+	}
+
+	// Seeded before the notifier is returned, so before the service can change any role. Only a new topic is
+	// seeded, as otherwise another replica's newer change could land before the seed and be overwritten
+	// once compacted. Replicas started together on a new topic can still race, but only that once.
+	snapshots := newRoleSnapshotWriter(logger, addr)
+	if err := snapshots.ensureTopic(ctx); err != nil {
+		logger.Errorw("failed to create role snapshot topic", "error", err)
+	} else if empty, err := snapshots.isEmpty(ctx); err != nil {
+		logger.Errorw("failed to check role snapshot topic", "error", err)
+	} else if empty {
+		if err := snapshots.seed(ctx, repo); err != nil {
+			logger.Errorw("failed to seed role snapshots", "error", err)
+		}
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		logger.Info("shutting down kafka writer")
+		if err := w.Close(); err != nil {
+			logger.Errorw("failed to close kafka writer", "error", err)
+		}
+		if err := snapshots.close(); err != nil {
+			logger.Errorw("failed to close kafka snapshot writer", "error", err)
+		}
+	}()
+
+	return &kafkaNotifier{
+		logger:    logger,
+		w:         w,
+		snapshots: snapshots,
+	}
+}
+
+func (k *kafkaNotifier) RoleUpdate(ctx context.Context, previous *model.Role, role *model.Role,
+	changeType permission.RoleUpdateMessage_ChangeType, meta ChangeMeta) error {
+
+	var protoRole *pbmodel.Role
+	if role != nil {
+		protoRole = role.ToProto()
+	}
+
+	diff := diffForChange(previous, role, changeType)
+	diffJson, err := json.Marshal(diff)
+	if err != nil {
+		return fmt.Errorf("failed to marshal role diff: %w", err)
+	}
+
+	headers := append(metaHeaders(meta),
+		kafka.Header{Key: changedFieldsHeader, Value: []byte(strings.Join(diff.ChangedFields(), ","))},
+		kafka.Header{Key: roleDiffHeader, Value: diffJson},
+	)
+
+	msg := &permission.RoleUpdateMessage{Role: protoRole, ChangeType: changeType}
+	if err := k.publishMessage(ctx, msg, headers...); err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+
+	if protoRole != nil {
+		if changeType == permission.RoleUpdateMessage_DELETE {
+			err = k.snapshots.publishTombstone(ctx, protoRole.Id)
+		} else {
+			err = k.snapshots.publish(ctx, protoRole)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to publish role snapshot: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (k *kafkaNotifier) PlayerRolesUpdate(ctx context.Context, playerId string, roleId string,
+	changeType permission.PlayerRolesUpdateMessage_ChangeType, meta ChangeMeta) error {
+
+	msg := &permission.PlayerRolesUpdateMessage{PlayerId: playerId, RoleId: roleId, ChangeType: changeType}
+	if err := k.publishMessage(ctx, msg, metaHeaders(meta)...); err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+
+	return nil
+}
+
+func (k *kafkaNotifier) PlayerRolesUpdates(ctx context.Context, changes []PlayerRolesChange, meta ChangeMeta) error {
+	headers := metaHeaders(meta)
+
+	for start := 0; start < len(changes); start += kafkaBatchSize {
+		batch := changes[start:min(start+kafkaBatchSize, len(changes))]
+
+		messages := make([]proto.Message, len(batch))
+		for i, change := range batch {
+			messages[i] = &permission.PlayerRolesUpdateMessage{PlayerId: change.PlayerId, RoleId: change.RoleId, ChangeType: change.ChangeType}
+		}
+
+		if err := k.publishMessages(ctx, messages, headers...); err != nil {
+			return fmt.Errorf("failed to publish messages: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (k *kafkaNotifier) CheckHealth(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	conn, err := kafka.DialContext(ctx, k.w.Addr.Network(), k.w.Addr.String())
+	if err != nil {
+		return fmt.Errorf("failed to dial kafka: %w", err)
+	}
+
+	return conn.Close()
+}
+
+func (k *kafkaNotifier) publishMessage(ctx context.Context, message proto.Message, headers ...kafka.Header) error {
+	return k.publishMessages(ctx, []proto.Message{message}, headers...)
+}
+
+// publishMessages writes messages of the same type in a single batch, sharing one span and the same headers.
+func (k *kafkaNotifier) publishMessages(ctx context.Context, messages []proto.Message, headers ...kafka.Header) error {
+	messageType := string(messages[0].ProtoReflect().Descriptor().FullName())
+	ctx, span := tracing.StartKafkaPublish(ctx, Topic, messageType)
+	defer span.End()
+
+	headers = append([]kafka.Header{{Key: "X-Proto-Type", Value: []byte(messageType)}}, headers...)
+	headers = tracing.InjectKafkaHeaders(ctx, headers)
+
+	kafkaMessages := make([]kafka.Message, len(messages))
+	for i, message := range messages {
+		bytes, err := proto.Marshal(message)
+		if err != nil {
+			return fmt.Errorf("failed to marshal message: %w", err)
+		}
+
+		kafkaMessages[i] = kafka.Message{Value: bytes, Headers: headers}
+	}
+
+	if err := k.w.WriteMessages(ctx, kafkaMessages...); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+
+	return nil
+}
+
+// metaHeaders carries the change metadata in headers so consumers can filter without decoding the message.
+func metaHeaders(meta ChangeMeta) []kafka.Header {
+	headers := []kafka.Header{
+		{Key: timestampHeader, Value: []byte(meta.Timestamp.UTC().Format(time.RFC3339Nano))},
+	}
+	if meta.Actor != "" {
+		headers = append(headers, kafka.Header{Key: actorHeader, Value: []byte(meta.Actor)})
+	}
+	if meta.OnBehalfOf != "" {
+		headers = append(headers, kafka.Header{Key: onBehalfOfHeader, Value: []byte(meta.OnBehalfOf)})
+	}
+	if meta.Reason != "" {
+		headers = append(headers, kafka.Header{Key: reasonHeader, Value: []byte(meta.Reason)})
+	}
+
+	return headers
+}
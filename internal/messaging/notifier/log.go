@@ -0,0 +1,48 @@
+package notifier
+
+import (
+	"context"
+	"github.com/emortalmc/proto-specs/gen/go/message/permission"
+	"go.uber.org/zap"
+	"permission-service/internal/repository/model"
+)
+
+type logNotifier struct {
+	logger *zap.SugaredLogger
+}
+
+// NewLogNotifier creates a Notifier that only logs events, useful when running without a Kafka broker.
+func NewLogNotifier(logger *zap.SugaredLogger) Notifier {
+	return &logNotifier{
+		logger: logger,
+	}
+}
+
+func (l *logNotifier) RoleUpdate(_ context.Context, previous *model.Role, role *model.Role,
+	changeType permission.RoleUpdateMessage_ChangeType, meta ChangeMeta) error {
+
+	var roleId string
+	if role != nil {
+		roleId = role.Id
+	}
+
+	l.logger.Infow("role update", "roleId", roleId, "changeType", changeType, "role", role,
+		"diff", diffForChange(previous, role, changeType), "actor", meta.Actor, "onBehalfOf", meta.OnBehalfOf, "reason", meta.Reason, "timestamp", meta.Timestamp)
+	return nil
+}
+
+func (l *logNotifier) PlayerRolesUpdate(_ context.Context, playerId string, roleId string,
+	changeType permission.PlayerRolesUpdateMessage_ChangeType, meta ChangeMeta) error {
+
+	l.logger.Infow("player roles update", "playerId", playerId, "roleId", roleId, "changeType", changeType,
+		"actor", meta.Actor, "onBehalfOf", meta.OnBehalfOf, "reason", meta.Reason, "timestamp", meta.Timestamp)
+	return nil
+}
+
+func (l *logNotifier) PlayerRolesUpdates(_ context.Context, changes []PlayerRolesChange, meta ChangeMeta) error {
+	for _, change := range changes {
+		l.logger.Infow("player roles update", "playerId", change.PlayerId, "roleId", change.RoleId, "changeType", change.ChangeType,
+			"actor", meta.Actor, "onBehalfOf", meta.OnBehalfOf, "reason", meta.Reason, "timestamp", meta.Timestamp)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package notifier
+
+import (
+	"context"
+	"github.com/emortalmc/proto-specs/gen/go/message/permission"
+	"permission-service/internal/repository/model"
+	"sync"
+)
+
+// Event is a single notification as seen by a MemoryNotifier subscriber.
+// Either Role or PlayerId/RoleId is set depending on the kind of update.
+type Event struct {
+	Meta ChangeMeta
+
+	PreviousRole   *model.Role
+	Role           *model.Role
+	RoleChangeType permission.RoleUpdateMessage_ChangeType
+
+	PlayerId              string
+	RoleId                string
+	PlayerRolesChangeType permission.PlayerRolesUpdateMessage_ChangeType
+}
+
+// IsRoleUpdate returns true if the event was created by RoleUpdate rather than PlayerRolesUpdate.
+func (e Event) IsRoleUpdate() bool {
+	return e.PlayerId == ""
+}
+
+// MemoryNotifier is a Notifier that delivers events over in-process channels.
+type MemoryNotifier interface {
+	Notifier
+
+	// Subscribe returns a channel receiving every event sent after the call, and a function to unsubscribe.
+	// A subscriber whose buffer is full is unsubscribed rather than blocking the notifier, so its channel
+	// closing before it unsubscribed means it missed events.
+	Subscribe(buffer int) (<-chan Event, func())
+}
+
+type memoryNotifier struct {
+	mu          sync.RWMutex
+	subscribers map[chan Event]*memorySubscriber
+}
+
+type memorySubscriber struct {
+	ch   chan Event
+	once sync.Once
+}
+
+func (s *memorySubscriber) close() {
+	s.once.Do(func() {
+		close(s.ch)
+	})
+}
+
+func NewMemoryNotifier() MemoryNotifier {
+	return &memoryNotifier{
+		subscribers: make(map[chan Event]*memorySubscriber),
+	}
+}
+
+func (m *memoryNotifier) RoleUpdate(_ context.Context, previous *model.Role, role *model.Role,
+	changeType permission.RoleUpdateMessage_ChangeType, meta ChangeMeta) error {
+
+	m.publish(Event{Meta: meta, PreviousRole: previous, Role: role, RoleChangeType: changeType})
+	return nil
+}
+
+func (m *memoryNotifier) PlayerRolesUpdate(_ context.Context, playerId string, roleId string,
+	changeType permission.PlayerRolesUpdateMessage_ChangeType, meta ChangeMeta) error {
+
+	m.publish(Event{Meta: meta, PlayerId: playerId, RoleId: roleId, PlayerRolesChangeType: changeType})
+	return nil
+}
+
+func (m *memoryNotifier) PlayerRolesUpdates(ctx context.Context, changes []PlayerRolesChange, meta ChangeMeta) error {
+	for _, change := range changes {
+		_ = m.PlayerRolesUpdate(ctx, change.PlayerId, change.RoleId, change.ChangeType, meta)
+	}
+	return nil
+}
+
+func (m *memoryNotifier) Subscribe(buffer int) (<-chan Event, func()) {
+	sub := &memorySubscriber{ch: make(chan Event, buffer)}
+
+	m.mu.Lock()
+	m.subscribers[sub.ch] = sub
+	m.mu.Unlock()
+
+	return sub.ch, func() {
+		m.mu.Lock()
+		delete(m.subscribers, sub.ch)
+		m.mu.Unlock()
+		sub.close()
+	}
+}
+
+func (m *memoryNotifier) publish(event Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for ch, sub := range m.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(m.subscribers, ch)
+			sub.close()
+		}
+	}
+}
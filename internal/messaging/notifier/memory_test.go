@@ -0,0 +1,41 @@
+package notifier
+
+import (
+	"context"
+	"github.com/emortalmc/proto-specs/gen/go/message/permission"
+	"github.com/stretchr/testify/assert"
+	"permission-service/internal/repository/model"
+	"testing"
+)
+
+func TestMemoryNotifier_Subscribe(t *testing.T) {
+	n := NewMemoryNotifier()
+
+	events, unsubscribe := n.Subscribe(2)
+
+	role := &model.Role{Id: "test-role"}
+	assert.NoError(t, n.RoleUpdate(context.Background(), nil, role, permission.RoleUpdateMessage_MODIFY, ChangeMeta{}))
+	assert.NoError(t, n.PlayerRolesUpdate(context.Background(), "player", "test-role", permission.PlayerRolesUpdateMessage_ADD, ChangeMeta{}))
+	// Buffer is full, this one is dropped rather than blocking and the subscriber is removed
+	assert.NoError(t, n.PlayerRolesUpdate(context.Background(), "player", "test-role", permission.PlayerRolesUpdateMessage_REMOVE, ChangeMeta{}))
+
+	roleEvent := <-events
+	assert.True(t, roleEvent.IsRoleUpdate())
+	assert.Equal(t, role, roleEvent.Role)
+	assert.Equal(t, permission.RoleUpdateMessage_MODIFY, roleEvent.RoleChangeType)
+
+	playerEvent := <-events
+	assert.False(t, playerEvent.IsRoleUpdate())
+	assert.Equal(t, "player", playerEvent.PlayerId)
+	assert.Equal(t, permission.PlayerRolesUpdateMessage_ADD, playerEvent.PlayerRolesChangeType)
+
+	// Closed without unsubscribing as events were missed
+	_, ok := <-events
+	assert.False(t, ok)
+
+	unsubscribe()
+	unsubscribe() // safe to call twice
+
+	// Publishing without subscribers must not block
+	assert.NoError(t, n.RoleUpdate(context.Background(), nil, role, permission.RoleUpdateMessage_DELETE, ChangeMeta{}))
+}
@@ -0,0 +1,52 @@
+package notifier
+
+import (
+	"context"
+	"github.com/emortalmc/proto-specs/gen/go/message/permission"
+	"permission-service/internal/repository/model"
+	"time"
+)
+
+// ChangeMeta describes who made a change, why and when.
+type ChangeMeta struct {
+	// Actor is the authenticated identity that made the change (e.g. a service name). Empty if unauthenticated.
+	Actor string
+	// OnBehalfOf is who the actor says it made the change for (e.g. a player UUID). It's claimed by the caller,
+	// not verified. Empty if none was given.
+	OnBehalfOf string
+	// Reason is a free-form explanation supplied by the actor. Empty if none was given.
+	Reason    string
+	Timestamp time.Time
+}
+
+type Notifier interface {
+	// RoleUpdate notifies that a role changed. previous is the role before the change and is nil on create.
+	// On delete, role is the role that was deleted.
+	RoleUpdate(ctx context.Context, previous *model.Role, role *model.Role, changeType permission.RoleUpdateMessage_ChangeType, meta ChangeMeta) error
+	PlayerRolesUpdate(ctx context.Context, playerId string, roleId string, changeType permission.PlayerRolesUpdateMessage_ChangeType, meta ChangeMeta) error
+	// PlayerRolesUpdates notifies of many player role changes made together, such as a bulk grant.
+	// Backends that can batch (e.g. Kafka) send them in batches rather than one at a time.
+	PlayerRolesUpdates(ctx context.Context, changes []PlayerRolesChange, meta ChangeMeta) error
+}
+
+// PlayerRolesChange is a single change of a PlayerRolesUpdates batch.
+type PlayerRolesChange struct {
+	PlayerId   string
+	RoleId     string
+	ChangeType permission.PlayerRolesUpdateMessage_ChangeType
+}
+
+// HealthChecker is implemented by notifiers that depend on an external system.
+type HealthChecker interface {
+	// CheckHealth returns an error if the external system can't be reached
+	CheckHealth(ctx context.Context) error
+}
+
+// diffForChange returns the diff described by a RoleUpdate call.
+func diffForChange(previous *model.Role, role *model.Role, changeType permission.RoleUpdateMessage_ChangeType) model.RoleDiff {
+	if changeType == permission.RoleUpdateMessage_DELETE {
+		return model.DiffRoles(role, nil)
+	}
+
+	return model.DiffRoles(previous, role)
+}
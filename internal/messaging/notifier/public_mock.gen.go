@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: internal/kafka/notifier/public.go
+// Source: internal/messaging/notifier/public.go
 
 // Package notifier is a generated GoMock package.
 package notifier
@@ -37,29 +37,80 @@ func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
 }
 
 // PlayerRolesUpdate mocks base method.
-func (m *MockNotifier) PlayerRolesUpdate(ctx context.Context, playerId, roleId string, changeType permission.PlayerRolesUpdateMessage_ChangeType) error {
+func (m *MockNotifier) PlayerRolesUpdate(ctx context.Context, playerId, roleId string, changeType permission.PlayerRolesUpdateMessage_ChangeType, meta ChangeMeta) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PlayerRolesUpdate", ctx, playerId, roleId, changeType)
+	ret := m.ctrl.Call(m, "PlayerRolesUpdate", ctx, playerId, roleId, changeType, meta)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // PlayerRolesUpdate indicates an expected call of PlayerRolesUpdate.
-func (mr *MockNotifierMockRecorder) PlayerRolesUpdate(ctx, playerId, roleId, changeType interface{}) *gomock.Call {
+func (mr *MockNotifierMockRecorder) PlayerRolesUpdate(ctx, playerId, roleId, changeType, meta interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlayerRolesUpdate", reflect.TypeOf((*MockNotifier)(nil).PlayerRolesUpdate), ctx, playerId, roleId, changeType)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlayerRolesUpdate", reflect.TypeOf((*MockNotifier)(nil).PlayerRolesUpdate), ctx, playerId, roleId, changeType, meta)
+}
+
+// PlayerRolesUpdates mocks base method.
+func (m *MockNotifier) PlayerRolesUpdates(ctx context.Context, changes []PlayerRolesChange, meta ChangeMeta) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlayerRolesUpdates", ctx, changes, meta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PlayerRolesUpdates indicates an expected call of PlayerRolesUpdates.
+func (mr *MockNotifierMockRecorder) PlayerRolesUpdates(ctx, changes, meta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlayerRolesUpdates", reflect.TypeOf((*MockNotifier)(nil).PlayerRolesUpdates), ctx, changes, meta)
 }
 
 // RoleUpdate mocks base method.
-func (m *MockNotifier) RoleUpdate(ctx context.Context, role *model.Role, changeType permission.RoleUpdateMessage_ChangeType) error {
+func (m *MockNotifier) RoleUpdate(ctx context.Context, previous, role *model.Role, changeType permission.RoleUpdateMessage_ChangeType, meta ChangeMeta) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RoleUpdate", ctx, role, changeType)
+	ret := m.ctrl.Call(m, "RoleUpdate", ctx, previous, role, changeType, meta)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RoleUpdate indicates an expected call of RoleUpdate.
-func (mr *MockNotifierMockRecorder) RoleUpdate(ctx, role, changeType interface{}) *gomock.Call {
+func (mr *MockNotifierMockRecorder) RoleUpdate(ctx, previous, role, changeType, meta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RoleUpdate", reflect.TypeOf((*MockNotifier)(nil).RoleUpdate), ctx, previous, role, changeType, meta)
+}
+
+// MockHealthChecker is a mock of HealthChecker interface.
+type MockHealthChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthCheckerMockRecorder
+}
+
+// MockHealthCheckerMockRecorder is the mock recorder for MockHealthChecker.
+type MockHealthCheckerMockRecorder struct {
+	mock *MockHealthChecker
+}
+
+// NewMockHealthChecker creates a new mock instance.
+func NewMockHealthChecker(ctrl *gomock.Controller) *MockHealthChecker {
+	mock := &MockHealthChecker{ctrl: ctrl}
+	mock.recorder = &MockHealthCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealthChecker) EXPECT() *MockHealthCheckerMockRecorder {
+	return m.recorder
+}
+
+// CheckHealth mocks base method.
+func (m *MockHealthChecker) CheckHealth(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckHealth", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckHealth indicates an expected call of CheckHealth.
+func (mr *MockHealthCheckerMockRecorder) CheckHealth(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RoleUpdate", reflect.TypeOf((*MockNotifier)(nil).RoleUpdate), ctx, role, changeType)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckHealth", reflect.TypeOf((*MockHealthChecker)(nil).CheckHealth), ctx)
 }
@@ -0,0 +1,162 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	pbmodel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"net"
+	"permission-service/internal/repository"
+	"time"
+)
+
+// snapshotTopic is a log-compacted topic holding the latest state of every role, keyed by role id.
+// Consumers can rebuild the full role table by reading it from the beginning.
+const snapshotTopic = "permission-roles"
+
+// messageWriter is the part of a kafka.Writer used to publish snapshots
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+type roleSnapshotWriter struct {
+	logger *zap.SugaredLogger
+	addr   net.Addr
+	w      messageWriter
+}
+
+func newRoleSnapshotWriter(logger *zap.SugaredLogger, addr net.Addr) *roleSnapshotWriter {
+	return &roleSnapshotWriter{
+		logger: logger,
+		addr:   addr,
+		w: &kafka.Writer{
+			Addr:  addr,
+			Topic: snapshotTopic,
+			Async: true,
+			// Messages for the same role must land on the same partition for compaction to work
+			Balancer:    &kafka.Hash{},
+			ErrorLogger: zap.NewStdLog(zap.L()),
+		},
+	}
+}
+
+// seed publishes the current state of every role, so roles that haven't changed since the topic was created
+// are still present. It must finish before any role change is published: the writer keeps the order of messages
+// for a role, so a change made after the read always lands after the seeded state rather than being overwritten.
+// That only holds for this replica's writer, so it must only be called for a topic that's never been written to.
+func (s *roleSnapshotWriter) seed(ctx context.Context, repo repository.Repository) error {
+	roles, err := repo.GetAllRoles(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get roles: %w", err)
+	}
+
+	for _, role := range roles {
+		if err := s.publish(ctx, role.ToProto()); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Infow("seeded role snapshots", "count", len(roles))
+	return nil
+}
+
+func (s *roleSnapshotWriter) ensureTopic(ctx context.Context) error {
+	client := &kafka.Client{Addr: s.addr, Timeout: 10 * time.Second}
+
+	res, err := client.CreateTopics(ctx, &kafka.CreateTopicsRequest{
+		Topics: []kafka.TopicConfig{{
+			Topic:             snapshotTopic,
+			NumPartitions:     -1,
+			ReplicationFactor: -1,
+			ConfigEntries: []kafka.ConfigEntry{
+				{ConfigName: "cleanup.policy", ConfigValue: "compact"},
+			},
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot topic: %w", err)
+	}
+
+	if err := res.Errors[snapshotTopic]; err != nil && !errors.Is(err, kafka.TopicAlreadyExists) {
+		return fmt.Errorf("failed to create snapshot topic: %w", err)
+	}
+
+	return nil
+}
+
+// isEmpty returns whether the snapshot topic has never been written to.
+func (s *roleSnapshotWriter) isEmpty(ctx context.Context) (bool, error) {
+	client := &kafka.Client{Addr: s.addr, Timeout: 10 * time.Second}
+
+	meta, err := client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{snapshotTopic}})
+	if err != nil {
+		return false, fmt.Errorf("failed to get snapshot topic metadata: %w", err)
+	}
+	if len(meta.Topics) != 1 {
+		return false, fmt.Errorf("snapshot topic %s not found", snapshotTopic)
+	}
+	if err := meta.Topics[0].Error; err != nil {
+		return false, fmt.Errorf("failed to get snapshot topic metadata: %w", err)
+	}
+
+	requests := make([]kafka.OffsetRequest, len(meta.Topics[0].Partitions))
+	for i, partition := range meta.Topics[0].Partitions {
+		requests[i] = kafka.LastOffsetOf(partition.ID)
+	}
+
+	res, err := client.ListOffsets(ctx, &kafka.ListOffsetsRequest{Topics: map[string][]kafka.OffsetRequest{snapshotTopic: requests}})
+	if err != nil {
+		return false, fmt.Errorf("failed to list snapshot topic offsets: %w", err)
+	}
+
+	return neverWritten(res.Topics[snapshotTopic])
+}
+
+// neverWritten returns whether no partition has had a message written to it. Compaction doesn't reset offsets,
+// so a topic whose roles have all been deleted still counts as written.
+func neverWritten(partitions []kafka.PartitionOffsets) (bool, error) {
+	for _, partition := range partitions {
+		if partition.Error != nil {
+			return false, fmt.Errorf("failed to list offsets of partition %d: %w", partition.Partition, partition.Error)
+		}
+		if partition.LastOffset > 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (s *roleSnapshotWriter) publish(ctx context.Context, role *pbmodel.Role) error {
+	bytes, err := proto.Marshal(role)
+	if err != nil {
+		return fmt.Errorf("failed to marshal role: %w", err)
+	}
+
+	return s.write(ctx, kafka.Message{
+		Key:     []byte(role.Id),
+		Value:   bytes,
+		Headers: []kafka.Header{{Key: "X-Proto-Type", Value: []byte(role.ProtoReflect().Descriptor().FullName())}},
+	})
+}
+
+// publishTombstone writes a nil value for the role, which removes it from the topic once compacted.
+func (s *roleSnapshotWriter) publishTombstone(ctx context.Context, roleId string) error {
+	return s.write(ctx, kafka.Message{Key: []byte(roleId)})
+}
+
+func (s *roleSnapshotWriter) write(ctx context.Context, msg kafka.Message) error {
+	if err := s.w.WriteMessages(ctx, msg); err != nil {
+		return fmt.Errorf("failed to write snapshot message: %w", err)
+	}
+
+	return nil
+}
+
+func (s *roleSnapshotWriter) close() error {
+	return s.w.Close()
+}
@@ -0,0 +1,77 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	pbmodel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"github.com/golang/mock/gomock"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"permission-service/internal/repository"
+	"permission-service/internal/repository/model"
+	"testing"
+)
+
+// recordingWriter records the messages written, in order
+type recordingWriter struct {
+	messages []kafka.Message
+}
+
+func (w *recordingWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	w.messages = append(w.messages, msgs...)
+	return nil
+}
+
+func (w *recordingWriter) Close() error {
+	return nil
+}
+
+func TestRoleSnapshotWriter_Seed(t *testing.T) {
+	w := &recordingWriter{}
+	s := &roleSnapshotWriter{logger: zap.NewNop().Sugar(), w: w}
+
+	mockRepo := repository.NewMockRepository(gomock.NewController(t))
+	mockRepo.EXPECT().GetAllRoles(gomock.Any()).Return([]*model.Role{{Id: "default"}, {Id: "vip", Priority: 10}}, nil)
+
+	require.NoError(t, s.seed(context.Background(), mockRepo))
+	// A role deleted after the seed's read is written after its seeded state, so compaction keeps the tombstone
+	require.NoError(t, s.publishTombstone(context.Background(), "vip"))
+
+	require.Len(t, w.messages, 3)
+	assert.Equal(t, "default", string(w.messages[0].Key))
+	assert.Equal(t, "vip", string(w.messages[1].Key))
+	assert.Equal(t, "vip", string(w.messages[2].Key))
+	assert.Nil(t, w.messages[2].Value)
+
+	role := &pbmodel.Role{}
+	require.NoError(t, proto.Unmarshal(w.messages[1].Value, role))
+	assert.Equal(t, uint32(10), role.Priority)
+}
+
+func TestRoleSnapshotWriter_Seed_RepositoryError(t *testing.T) {
+	w := &recordingWriter{}
+	s := &roleSnapshotWriter{logger: zap.NewNop().Sugar(), w: w}
+
+	mockRepo := repository.NewMockRepository(gomock.NewController(t))
+	mockRepo.EXPECT().GetAllRoles(gomock.Any()).Return(nil, errors.New("connection refused"))
+
+	assert.EqualError(t, s.seed(context.Background(), mockRepo), "failed to get roles: connection refused")
+	assert.Empty(t, w.messages)
+}
+
+func TestNeverWritten(t *testing.T) {
+	empty, err := neverWritten([]kafka.PartitionOffsets{{Partition: 0}, {Partition: 1}})
+	require.NoError(t, err)
+	assert.True(t, empty)
+
+	// Offsets aren't reset by compaction, so a topic of only compacted tombstones isn't empty
+	empty, err = neverWritten([]kafka.PartitionOffsets{{Partition: 0}, {Partition: 1, FirstOffset: 3, LastOffset: 3}})
+	require.NoError(t, err)
+	assert.False(t, empty)
+
+	_, err = neverWritten([]kafka.PartitionOffsets{{Partition: 0, Error: kafka.LeaderNotAvailable}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,244 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/emortalmc/proto-specs/gen/go/message/permission"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"net/http"
+	"permission-service/internal/config"
+	"permission-service/internal/repository/model"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	EventRoleCreate       = "role.create"
+	EventRoleModify       = "role.modify"
+	EventRoleDelete       = "role.delete"
+	EventPlayerRoleAdd    = "player_role.add"
+	EventPlayerRoleRemove = "player_role.remove"
+
+	signatureHeader = "X-Signature-256"
+	eventHeader     = "X-Event-Type"
+)
+
+type webhookPayload struct {
+	Type       string    `json:"type"`
+	Timestamp  time.Time `json:"timestamp"`
+	Actor      string    `json:"actor,omitempty"`
+	OnBehalfOf string    `json:"onBehalfOf,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+
+	Role json.RawMessage `json:"role,omitempty"`
+	Diff *model.RoleDiff `json:"diff,omitempty"`
+
+	PlayerId string `json:"playerId,omitempty"`
+	RoleId   string `json:"roleId,omitempty"`
+}
+
+var errWebhookClosed = errors.New("webhook notifier is shutting down")
+
+type webhookNotifier struct {
+	// ctx is cancelled on shutdown, which stops retries
+	ctx context.Context
+	// deliveryCtx outlives ctx until in-flight requests have drained, so shutdown doesn't abort them
+	deliveryCtx context.Context
+
+	logger *zap.SugaredLogger
+	client *http.Client
+
+	cfg config.WebhookConfig
+
+	// mu guards closing, so no delivery is added to inFlight once shutdown has started waiting on it
+	mu      sync.Mutex
+	closing bool
+	// inFlight tracks deliveries so shutdown can wait for them
+	inFlight sync.WaitGroup
+}
+
+// NewWebhookNotifier creates a Notifier that POSTs events as JSON to the configured endpoints.
+// Deliveries happen in the background and are retried with exponential backoff until ctx is cancelled.
+// On shutdown, requests already being sent are waited for, and new events are rejected.
+func NewWebhookNotifier(ctx context.Context, wg *sync.WaitGroup, logger *zap.SugaredLogger, cfg config.WebhookConfig) Notifier {
+	deliveryCtx, cancelDeliveries := context.WithCancel(context.WithoutCancel(ctx))
+	n := &webhookNotifier{
+		ctx:         ctx,
+		deliveryCtx: deliveryCtx,
+		logger:      logger,
+		client:      &http.Client{Timeout: cfg.Timeout},
+		cfg:         cfg,
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+
+		n.mu.Lock()
+		n.closing = true
+		n.mu.Unlock()
+
+		logger.Info("waiting for in-flight webhook deliveries")
+		n.inFlight.Wait()
+		cancelDeliveries()
+	}()
+
+	return n
+}
+
+func (w *webhookNotifier) RoleUpdate(_ context.Context, previous *model.Role, role *model.Role,
+	changeType permission.RoleUpdateMessage_ChangeType, meta ChangeMeta) error {
+
+	var eventType string
+	switch changeType {
+	case permission.RoleUpdateMessage_CREATE:
+		eventType = EventRoleCreate
+	case permission.RoleUpdateMessage_MODIFY:
+		eventType = EventRoleModify
+	case permission.RoleUpdateMessage_DELETE:
+		eventType = EventRoleDelete
+	default:
+		return fmt.Errorf("unknown role change type %s", changeType)
+	}
+
+	diff := diffForChange(previous, role, changeType)
+	payload := webhookPayload{Type: eventType, Timestamp: meta.Timestamp.UTC(), Actor: meta.Actor, OnBehalfOf: meta.OnBehalfOf,
+		Reason: meta.Reason, Diff: &diff}
+	if role != nil {
+		roleJson, err := protojson.Marshal(role.ToProto())
+		if err != nil {
+			return fmt.Errorf("failed to marshal role: %w", err)
+		}
+		payload.Role = roleJson
+	}
+
+	return w.send(payload)
+}
+
+func (w *webhookNotifier) PlayerRolesUpdate(_ context.Context, playerId string, roleId string,
+	changeType permission.PlayerRolesUpdateMessage_ChangeType, meta ChangeMeta) error {
+
+	var eventType string
+	switch changeType {
+	case permission.PlayerRolesUpdateMessage_ADD:
+		eventType = EventPlayerRoleAdd
+	case permission.PlayerRolesUpdateMessage_REMOVE:
+		eventType = EventPlayerRoleRemove
+	default:
+		return fmt.Errorf("unknown player roles change type %s", changeType)
+	}
+
+	return w.send(webhookPayload{
+		Type:       eventType,
+		Timestamp:  meta.Timestamp.UTC(),
+		Actor:      meta.Actor,
+		OnBehalfOf: meta.OnBehalfOf,
+		Reason:     meta.Reason,
+		PlayerId:   playerId,
+		RoleId:     roleId,
+	})
+}
+
+// PlayerRolesUpdates sends a payload per change, so endpoints see bulk changes the same as individual ones.
+func (w *webhookNotifier) PlayerRolesUpdates(ctx context.Context, changes []PlayerRolesChange, meta ChangeMeta) error {
+	errs := make([]error, 0)
+	for _, change := range changes {
+		if err := w.PlayerRolesUpdate(ctx, change.PlayerId, change.RoleId, change.ChangeType, meta); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (w *webhookNotifier) send(payload webhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closing {
+		return errWebhookClosed
+	}
+
+	for _, endpoint := range w.cfg.Endpoints {
+		if len(endpoint.Events) > 0 && !slices.Contains(endpoint.Events, payload.Type) {
+			continue
+		}
+
+		w.inFlight.Add(1)
+		go func(endpoint config.WebhookEndpointConfig) {
+			defer w.inFlight.Done()
+			w.deliver(endpoint, payload.Type, body)
+		}(endpoint)
+	}
+
+	return nil
+}
+
+func (w *webhookNotifier) deliver(endpoint config.WebhookEndpointConfig, eventType string, body []byte) {
+	backoff := w.cfg.RetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		retryable, err := w.post(endpoint, eventType, body)
+		if err == nil {
+			return
+		}
+
+		if !retryable || attempt >= w.cfg.MaxRetries {
+			w.logger.Errorw("failed to deliver webhook", "error", err, "url", endpoint.URL, "event", eventType,
+				"attempts", attempt+1)
+			return
+		}
+
+		select {
+		case <-w.ctx.Done():
+			w.logger.Warnw("dropping webhook delivery on shutdown", "url", endpoint.URL, "event", eventType)
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
+
+// post sends the payload once, returning whether a failure is worth retrying.
+func (w *webhookNotifier) post(endpoint config.WebhookEndpointConfig, eventType string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(w.deliveryCtx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(eventHeader, eventType)
+	req.Header.Set(signatureHeader, "sha256="+sign(endpoint.Secret, body))
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return false, nil
+	}
+
+	retryable := res.StatusCode >= 500 || res.StatusCode == http.StatusTooManyRequests
+	return retryable, fmt.Errorf("unexpected status code %d", res.StatusCode)
+}
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
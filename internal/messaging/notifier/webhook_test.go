@@ -0,0 +1,166 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/emortalmc/proto-specs/gen/go/message/permission"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"permission-service/internal/config"
+	"permission-service/internal/repository/model"
+	"permission-service/internal/utils"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type receivedWebhook struct {
+	eventType string
+	signature string
+	payload   webhookPayload
+}
+
+func createReceiver(t *testing.T, failures int32) (*httptest.Server, *[]receivedWebhook, *atomic.Int32) {
+	var mu sync.Mutex
+	received := make([]receivedWebhook, 0)
+	attempts := &atomic.Int32{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		var payload webhookPayload
+		assert.NoError(t, json.Unmarshal(body, &payload))
+
+		mu.Lock()
+		received = append(received, receivedWebhook{
+			eventType: r.Header.Get(eventHeader),
+			signature: r.Header.Get(signatureHeader),
+			payload:   payload,
+		})
+		mu.Unlock()
+
+		// Signature must match the body that was actually received
+		if sig := r.Header.Get(signatureHeader); sig != "" {
+			assert.Equal(t, "sha256="+sign("secret", body), sig)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &received, attempts
+}
+
+func createWebhookNotifier(endpoints ...config.WebhookEndpointConfig) *webhookNotifier {
+	return NewWebhookNotifier(context.Background(), &sync.WaitGroup{}, zap.NewNop().Sugar(), config.WebhookConfig{
+		Endpoints:    endpoints,
+		MaxRetries:   3,
+		RetryBackoff: time.Millisecond,
+		Timeout:      time.Second,
+	}).(*webhookNotifier)
+}
+
+func TestWebhookNotifier_RoleUpdate(t *testing.T) {
+	server, received, _ := createReceiver(t, 0)
+	n := createWebhookNotifier(config.WebhookEndpointConfig{URL: server.URL, Secret: "secret"})
+
+	previous := &model.Role{Id: "test-role", Priority: 5, DisplayName: utils.PointerOf("test")}
+	role := &model.Role{Id: "test-role", Priority: 10, DisplayName: utils.PointerOf("test")}
+	meta := ChangeMeta{Actor: "test-actor", Reason: "promotion", Timestamp: time.Now()}
+	assert.NoError(t, n.RoleUpdate(context.Background(), previous, role, permission.RoleUpdateMessage_MODIFY, meta))
+	n.inFlight.Wait()
+
+	assert.Len(t, *received, 1)
+	got := (*received)[0]
+	assert.Equal(t, EventRoleModify, got.eventType)
+	assert.Equal(t, EventRoleModify, got.payload.Type)
+	assert.NotEmpty(t, got.signature)
+	assert.JSONEq(t, `{"id":"test-role","priority":10,"displayName":"test"}`, string(got.payload.Role))
+	assert.Equal(t, "test-actor", got.payload.Actor)
+	assert.Equal(t, "promotion", got.payload.Reason)
+	assert.True(t, meta.Timestamp.Equal(got.payload.Timestamp))
+	assert.Equal(t, &model.PriorityChange{Old: 5, New: 10}, got.payload.Diff.Priority)
+	assert.Nil(t, got.payload.Diff.DisplayName)
+}
+
+func TestWebhookNotifier_EventFilter(t *testing.T) {
+	server, received, _ := createReceiver(t, 0)
+	n := createWebhookNotifier(config.WebhookEndpointConfig{URL: server.URL, Secret: "secret", Events: []string{EventPlayerRoleAdd}})
+
+	assert.NoError(t, n.RoleUpdate(context.Background(), nil, &model.Role{Id: "test-role"}, permission.RoleUpdateMessage_CREATE, ChangeMeta{}))
+	assert.NoError(t, n.PlayerRolesUpdate(context.Background(), "player", "test-role", permission.PlayerRolesUpdateMessage_REMOVE, ChangeMeta{}))
+	assert.NoError(t, n.PlayerRolesUpdate(context.Background(), "player", "test-role", permission.PlayerRolesUpdateMessage_ADD, ChangeMeta{}))
+	n.inFlight.Wait()
+
+	assert.Len(t, *received, 1)
+	got := (*received)[0]
+	assert.Equal(t, EventPlayerRoleAdd, got.payload.Type)
+	assert.Equal(t, "player", got.payload.PlayerId)
+	assert.Equal(t, "test-role", got.payload.RoleId)
+}
+
+func TestWebhookNotifier_Retry(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int32
+		wantAttempts int32
+		wantReceived int
+	}{
+		{name: "succeeds after retries", failures: 2, wantAttempts: 3, wantReceived: 1},
+		{name: "gives up after max retries", failures: 10, wantAttempts: 4, wantReceived: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, received, attempts := createReceiver(t, tt.failures)
+			n := createWebhookNotifier(config.WebhookEndpointConfig{URL: server.URL, Secret: "secret"})
+
+			assert.NoError(t, n.PlayerRolesUpdate(context.Background(), "player", "role", permission.PlayerRolesUpdateMessage_ADD, ChangeMeta{}))
+			n.inFlight.Wait()
+
+			assert.Equal(t, tt.wantAttempts, attempts.Load())
+			assert.Len(t, *received, tt.wantReceived)
+		})
+	}
+}
+
+func TestWebhookNotifier_Shutdown(t *testing.T) {
+	release := make(chan struct{})
+	var received atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		received.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	n := NewWebhookNotifier(ctx, wg, zap.NewNop().Sugar(), config.WebhookConfig{
+		Endpoints: []config.WebhookEndpointConfig{{URL: server.URL}},
+		Timeout:   time.Second,
+	})
+
+	assert.NoError(t, n.PlayerRolesUpdate(context.Background(), "player", "role", permission.PlayerRolesUpdateMessage_ADD, ChangeMeta{}))
+
+	// The request in flight when shutdown starts is still delivered, but new events are rejected
+	cancel()
+	assert.Eventually(t, func() bool {
+		return errors.Is(n.PlayerRolesUpdate(context.Background(), "player", "role", permission.PlayerRolesUpdateMessage_REMOVE, ChangeMeta{}), errWebhookClosed)
+	}, time.Second, time.Millisecond)
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), received.Load())
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"strings"
+	"time"
+)
+
+const (
+	unaryType        = "unary"
+	serverStreamType = "server_stream"
+	clientStreamType = "client_stream"
+	bidiStreamType   = "bidi_stream"
+)
+
+// UnaryServerInterceptor records the count, status code and latency of unary RPCs.
+// It should be the outermost interceptor so rejected calls are counted too.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		res, err := handler(ctx, req)
+		observeRPC(info.FullMethod, unaryType, start, err)
+
+		return res, err
+	}
+}
+
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observeRPC(info.FullMethod, streamType(info), start, err)
+
+		return err
+	}
+}
+
+func observeRPC(fullMethod string, rpcType string, start time.Time, err error) {
+	service, method := splitMethod(fullMethod)
+
+	rpcHandled.WithLabelValues(service, method, rpcType, status.Code(err).String()).Inc()
+	rpcDuration.WithLabelValues(service, method, rpcType).Observe(time.Since(start).Seconds())
+}
+
+func streamType(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return bidiStreamType
+	case info.IsClientStream:
+		return clientStreamType
+	default:
+		return serverStreamType
+	}
+}
+
+// splitMethod splits "/package.Service/Method" into the service and method names.
+func splitMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+
+	return "unknown", fullMethod
+}
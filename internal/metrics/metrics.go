@@ -0,0 +1,141 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+	"net/http"
+	"permission-service/internal/messaging/notifier"
+	"permission-service/internal/repository"
+	"sync"
+	"time"
+)
+
+const (
+	namespace = "permission_service"
+
+	gaugeUpdateInterval = 30 * time.Second
+)
+
+// Registry holds every metric of the service, plus the Go runtime and process metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	rpcHandled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_server_handled_total",
+		Help:      "RPCs completed on the server, regardless of success or failure.",
+	}, []string{"grpc_service", "grpc_method", "grpc_type", "grpc_code"})
+	rpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_server_handling_seconds",
+		Help:      "Time taken to handle RPCs, until the last message is sent for streams.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"grpc_service", "grpc_method", "grpc_type"})
+
+	repositoryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "repository_operation_seconds",
+		Help:      "Time taken by repository methods.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"method", "result"})
+
+	roles = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "roles",
+		Help:      "Number of roles.",
+	})
+	players = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "players",
+		Help:      "Estimated number of players stored, including those only holding the default role.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		rpcHandled, rpcDuration, repositoryDuration, roles, players,
+	)
+}
+
+// RegisterNotifier exposes the delivery counters of every backend of n.
+func RegisterNotifier(n notifier.FanOutNotifier) {
+	Registry.MustRegister(newNotifierCollector(n))
+}
+
+// RunServer serves the metrics over HTTP on /metrics until ctx is done.
+func RunServer(ctx context.Context, wg *sync.WaitGroup, logger *zap.SugaredLogger, port int) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry}))
+
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		logger.Infow("serving metrics", "port", port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatalw("failed to serve metrics", "error", err)
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logger.Errorw("failed to shut down metrics server", "error", err)
+		}
+	}()
+}
+
+// RunGaugeUpdater periodically refreshes the role and player counts until ctx is done.
+// They're refreshed in the background as counting on every scrape would load the database.
+func RunGaugeUpdater(ctx context.Context, wg *sync.WaitGroup, logger *zap.SugaredLogger, repo repository.Repository) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(gaugeUpdateInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := updateGauges(ctx, repo); err != nil && ctx.Err() == nil {
+				logger.Warnw("failed to update metric gauges", "error", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func updateGauges(ctx context.Context, repo repository.Repository) error {
+	allRoles, err := repo.GetAllRoles(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get roles: %w", err)
+	}
+	roles.Set(float64(len(allRoles)))
+
+	count, err := repo.CountPlayers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count players: %w", err)
+	}
+	players.Set(float64(count))
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"github.com/emortalmc/proto-specs/gen/go/message/permission"
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"permission-service/internal/messaging/notifier"
+	"permission-service/internal/repository"
+	"permission-service/internal/repository/model"
+	"strings"
+	"testing"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/emortal.grpc.permission.PermissionService/GetAllRoles"}
+
+	okBefore := testutil.ToFloat64(rpcHandled.WithLabelValues("emortal.grpc.permission.PermissionService", "GetAllRoles", unaryType, "OK"))
+	notFoundBefore := testutil.ToFloat64(rpcHandled.WithLabelValues("emortal.grpc.permission.PermissionService", "GetAllRoles", unaryType, "NotFound"))
+
+	_, err := interceptor(context.Background(), nil, info, func(context.Context, any) (any, error) { return nil, nil })
+	assert.NoError(t, err)
+	_, err = interceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		return nil, status.Error(codes.NotFound, "role not found")
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	assert.Equal(t, okBefore+1, testutil.ToFloat64(rpcHandled.WithLabelValues("emortal.grpc.permission.PermissionService", "GetAllRoles", unaryType, "OK")))
+	assert.Equal(t, notFoundBefore+1, testutil.ToFloat64(rpcHandled.WithLabelValues("emortal.grpc.permission.PermissionService", "GetAllRoles", unaryType, "NotFound")))
+}
+
+func TestSplitMethod(t *testing.T) {
+	service, method := splitMethod("/grpc.health.v1.Health/Check")
+	assert.Equal(t, "grpc.health.v1.Health", service)
+	assert.Equal(t, "Check", method)
+
+	service, method = splitMethod("Check")
+	assert.Equal(t, "unknown", service)
+	assert.Equal(t, "Check", method)
+}
+
+func TestInstrumentRepository(t *testing.T) {
+	mockCntrl := gomock.NewController(t)
+	mockRepo := repository.NewMockRepository(mockCntrl)
+
+	mockRepo.EXPECT().GetRole(gomock.Any(), "vip").Return(&model.Role{Id: "vip"}, nil)
+	mockRepo.EXPECT().GetRole(gomock.Any(), "missing").Return(nil, mongo.ErrNoDocuments)
+	mockRepo.EXPECT().GetRole(gomock.Any(), "broken").Return(nil, errors.New("connection reset"))
+
+	repo := InstrumentRepository(mockRepo)
+
+	countBefore := func(result string) uint64 {
+		return histogramCount(t, "GetRole", result)
+	}
+	success, notFound, failed := countBefore("success"), countBefore("not_found"), countBefore("error")
+
+	role, err := repo.GetRole(context.Background(), "vip")
+	assert.NoError(t, err)
+	assert.Equal(t, "vip", role.Id)
+
+	_, err = repo.GetRole(context.Background(), "missing")
+	assert.Equal(t, mongo.ErrNoDocuments, err)
+
+	_, err = repo.GetRole(context.Background(), "broken")
+	assert.Error(t, err)
+
+	assert.Equal(t, success+1, histogramCount(t, "GetRole", "success"))
+	assert.Equal(t, notFound+1, histogramCount(t, "GetRole", "not_found"))
+	assert.Equal(t, failed+1, histogramCount(t, "GetRole", "error"))
+}
+
+func histogramCount(t *testing.T, method string, result string) uint64 {
+	families, err := Registry.Gather()
+	assert.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "permission_service_repository_operation_seconds" {
+			continue
+		}
+
+		for _, m := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range m.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["method"] == method && labels["result"] == result {
+				return m.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+
+	return 0
+}
+
+type failingNotifier struct {
+	notifier.Notifier
+}
+
+func (failingNotifier) PlayerRolesUpdate(context.Context, string, string, permission.PlayerRolesUpdateMessage_ChangeType, notifier.ChangeMeta) error {
+	return errors.New("unavailable")
+}
+
+func TestNotifierCollector(t *testing.T) {
+	n := notifier.NewFanOutNotifier(
+		notifier.Backend{Name: "memory", Notifier: notifier.NewMemoryNotifier()},
+		notifier.Backend{Name: "webhook", Notifier: failingNotifier{}},
+	)
+	_ = n.PlayerRolesUpdate(context.Background(), "8d36737e-1c0a-4a71-87de-9906f577845e", "vip", permission.PlayerRolesUpdateMessage_ADD, notifier.ChangeMeta{})
+
+	expected := `
+# HELP permission_service_notifier_failed_total Events a notifier backend failed to deliver.
+# TYPE permission_service_notifier_failed_total counter
+permission_service_notifier_failed_total{backend="memory"} 0
+permission_service_notifier_failed_total{backend="webhook"} 1
+# HELP permission_service_notifier_sent_total Events successfully delivered by a notifier backend.
+# TYPE permission_service_notifier_sent_total counter
+permission_service_notifier_sent_total{backend="memory"} 1
+permission_service_notifier_sent_total{backend="webhook"} 0
+`
+	err := testutil.CollectAndCompare(newNotifierCollector(n), strings.NewReader(expected),
+		"permission_service_notifier_sent_total", "permission_service_notifier_failed_total")
+	assert.NoError(t, err)
+}
+
+func TestUpdateGauges(t *testing.T) {
+	mockCntrl := gomock.NewController(t)
+	mockRepo := repository.NewMockRepository(mockCntrl)
+
+	mockRepo.EXPECT().GetAllRoles(gomock.Any()).Return([]*model.Role{{Id: "default"}, {Id: "vip"}}, nil)
+	mockRepo.EXPECT().CountPlayers(gomock.Any()).Return(int64(42), nil)
+
+	assert.NoError(t, updateGauges(context.Background(), mockRepo))
+	assert.Equal(t, float64(2), testutil.ToFloat64(roles))
+	assert.Equal(t, float64(42), testutil.ToFloat64(players))
+}
@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"permission-service/internal/messaging/notifier"
+)
+
+var (
+	notifierSentDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "notifier", "sent_total"),
+		"Events successfully delivered by a notifier backend.", []string{"backend"}, nil)
+	notifierFailedDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "notifier", "failed_total"),
+		"Events a notifier backend failed to deliver.", []string{"backend"}, nil)
+	notifierLatencyDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "notifier", "last_latency_seconds"),
+		"Time taken by the last delivery of a notifier backend.", []string{"backend"}, nil)
+)
+
+// notifierCollector exposes the counters the fan-out notifier already keeps, rather than counting twice.
+type notifierCollector struct {
+	notifier notifier.FanOutNotifier
+}
+
+func newNotifierCollector(n notifier.FanOutNotifier) prometheus.Collector {
+	return &notifierCollector{notifier: n}
+}
+
+func (c *notifierCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- notifierSentDesc
+	ch <- notifierFailedDesc
+	ch <- notifierLatencyDesc
+}
+
+func (c *notifierCollector) Collect(ch chan<- prometheus.Metric) {
+	for backend, stats := range c.notifier.Stats() {
+		ch <- prometheus.MustNewConstMetric(notifierSentDesc, prometheus.CounterValue, float64(stats.Sent), backend)
+		ch <- prometheus.MustNewConstMetric(notifierFailedDesc, prometheus.CounterValue, float64(stats.Failed), backend)
+		ch <- prometheus.MustNewConstMetric(notifierLatencyDesc, prometheus.GaugeValue, stats.LastLatency.Seconds(), backend)
+	}
+}
@@ -0,0 +1,157 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+	"permission-service/internal/repository"
+	"permission-service/internal/repository/model"
+	"time"
+)
+
+type instrumentedRepository struct {
+	repo repository.Repository
+}
+
+// InstrumentRepository records the latency and result of every method of repo.
+func InstrumentRepository(repo repository.Repository) repository.Repository {
+	return &instrumentedRepository{repo: repo}
+}
+
+func observeRepository(method string, start time.Time, err error) {
+	result := "success"
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		// Lookups of missing documents are expected and shouldn't look like database errors
+		result = "not_found"
+	case err != nil:
+		result = "error"
+	}
+
+	repositoryDuration.WithLabelValues(method, result).Observe(time.Since(start).Seconds())
+}
+
+func (r *instrumentedRepository) Ping(ctx context.Context) (err error) {
+	defer func(start time.Time) { observeRepository("Ping", start, err) }(time.Now())
+	return r.repo.Ping(ctx)
+}
+
+func (r *instrumentedRepository) SetTimeouts(timeout time.Duration, bulkTimeout time.Duration) {
+	r.repo.SetTimeouts(timeout, bulkTimeout)
+}
+
+func (r *instrumentedRepository) GetAllRoles(ctx context.Context) (roles []*model.Role, err error) {
+	defer func(start time.Time) { observeRepository("GetAllRoles", start, err) }(time.Now())
+	return r.repo.GetAllRoles(ctx)
+}
+
+func (r *instrumentedRepository) GetRole(ctx context.Context, roleId string) (role *model.Role, err error) {
+	defer func(start time.Time) { observeRepository("GetRole", start, err) }(time.Now())
+	return r.repo.GetRole(ctx, roleId)
+}
+
+func (r *instrumentedRepository) DoesRoleExist(ctx context.Context, roleId string) (exists bool, err error) {
+	defer func(start time.Time) { observeRepository("DoesRoleExist", start, err) }(time.Now())
+	return r.repo.DoesRoleExist(ctx, roleId)
+}
+
+func (r *instrumentedRepository) CreateRole(ctx context.Context, role *model.Role) (err error) {
+	defer func(start time.Time) { observeRepository("CreateRole", start, err) }(time.Now())
+	return r.repo.CreateRole(ctx, role)
+}
+
+func (r *instrumentedRepository) UpdateRole(ctx context.Context, newRole *model.Role) (err error) {
+	defer func(start time.Time) { observeRepository("UpdateRole", start, err) }(time.Now())
+	return r.repo.UpdateRole(ctx, newRole)
+}
+
+func (r *instrumentedRepository) DeleteRole(ctx context.Context, roleId string) (err error) {
+	defer func(start time.Time) { observeRepository("DeleteRole", start, err) }(time.Now())
+	return r.repo.DeleteRole(ctx, roleId)
+}
+
+func (r *instrumentedRepository) CreateRolesIfMissing(ctx context.Context, roles []*model.Role) (created []string, err error) {
+	defer func(start time.Time) { observeRepository("CreateRolesIfMissing", start, err) }(time.Now())
+	return r.repo.CreateRolesIfMissing(ctx, roles)
+}
+
+func (r *instrumentedRepository) SetManagedRoles(ctx context.Context, roleIds []string) (err error) {
+	defer func(start time.Time) { observeRepository("SetManagedRoles", start, err) }(time.Now())
+	return r.repo.SetManagedRoles(ctx, roleIds)
+}
+
+func (r *instrumentedRepository) CountPlayers(ctx context.Context) (count int64, err error) {
+	defer func(start time.Time) { observeRepository("CountPlayers", start, err) }(time.Now())
+	return r.repo.CountPlayers(ctx)
+}
+
+func (r *instrumentedRepository) GetPlayerRoleIds(ctx context.Context, playerId uuid.UUID) (roleIds []string, err error) {
+	defer func(start time.Time) { observeRepository("GetPlayerRoleIds", start, err) }(time.Now())
+	return r.repo.GetPlayerRoleIds(ctx, playerId)
+}
+
+func (r *instrumentedRepository) AddRoleToPlayer(ctx context.Context, playerId uuid.UUID, roleId string) (err error) {
+	defer func(start time.Time) { observeRepository("AddRoleToPlayer", start, err) }(time.Now())
+	return r.repo.AddRoleToPlayer(ctx, playerId, roleId)
+}
+
+func (r *instrumentedRepository) RemoveRoleFromPlayer(ctx context.Context, playerId uuid.UUID, roleId string) (err error) {
+	defer func(start time.Time) { observeRepository("RemoveRoleFromPlayer", start, err) }(time.Now())
+	return r.repo.RemoveRoleFromPlayer(ctx, playerId, roleId)
+}
+
+func (r *instrumentedRepository) AddRoleToPlayers(ctx context.Context, playerIds []uuid.UUID, roleId string) (changed []uuid.UUID, failed []uuid.UUID, err error) {
+	defer func(start time.Time) { observeRepository("AddRoleToPlayers", start, err) }(time.Now())
+	return r.repo.AddRoleToPlayers(ctx, playerIds, roleId)
+}
+
+func (r *instrumentedRepository) RemoveRoleFromPlayers(ctx context.Context, playerIds []uuid.UUID, roleId string) (changed []uuid.UUID, failed []uuid.UUID, err error) {
+	defer func(start time.Time) { observeRepository("RemoveRoleFromPlayers", start, err) }(time.Now())
+	return r.repo.RemoveRoleFromPlayers(ctx, playerIds, roleId)
+}
+
+func (r *instrumentedRepository) GetSubjectGrants(ctx context.Context, subject model.Subject) (grants *model.SubjectGrants, err error) {
+	defer func(start time.Time) { observeRepository("GetSubjectGrants", start, err) }(time.Now())
+	return r.repo.GetSubjectGrants(ctx, subject)
+}
+
+func (r *instrumentedRepository) GetAllServiceAccounts(ctx context.Context) (accounts []*model.ServiceAccount, err error) {
+	defer func(start time.Time) { observeRepository("GetAllServiceAccounts", start, err) }(time.Now())
+	return r.repo.GetAllServiceAccounts(ctx)
+}
+
+func (r *instrumentedRepository) GetServiceAccount(ctx context.Context, id string) (account *model.ServiceAccount, err error) {
+	defer func(start time.Time) { observeRepository("GetServiceAccount", start, err) }(time.Now())
+	return r.repo.GetServiceAccount(ctx, id)
+}
+
+func (r *instrumentedRepository) GetServiceAccountByTokenHash(ctx context.Context, tokenHash string) (account *model.ServiceAccount, err error) {
+	defer func(start time.Time) { observeRepository("GetServiceAccountByTokenHash", start, err) }(time.Now())
+	return r.repo.GetServiceAccountByTokenHash(ctx, tokenHash)
+}
+
+func (r *instrumentedRepository) CreateServiceAccount(ctx context.Context, account *model.ServiceAccount) (err error) {
+	defer func(start time.Time) { observeRepository("CreateServiceAccount", start, err) }(time.Now())
+	return r.repo.CreateServiceAccount(ctx, account)
+}
+
+func (r *instrumentedRepository) UpdateServiceAccount(ctx context.Context, account *model.ServiceAccount) (err error) {
+	defer func(start time.Time) { observeRepository("UpdateServiceAccount", start, err) }(time.Now())
+	return r.repo.UpdateServiceAccount(ctx, account)
+}
+
+func (r *instrumentedRepository) DeleteServiceAccount(ctx context.Context, id string) (err error) {
+	defer func(start time.Time) { observeRepository("DeleteServiceAccount", start, err) }(time.Now())
+	return r.repo.DeleteServiceAccount(ctx, id)
+}
+
+func (r *instrumentedRepository) AddRoleToServiceAccount(ctx context.Context, id string, roleId string) (err error) {
+	defer func(start time.Time) { observeRepository("AddRoleToServiceAccount", start, err) }(time.Now())
+	return r.repo.AddRoleToServiceAccount(ctx, id, roleId)
+}
+
+func (r *instrumentedRepository) RemoveRoleFromServiceAccount(ctx context.Context, id string, roleId string) (err error) {
+	defer func(start time.Time) { observeRepository("RemoveRoleFromServiceAccount", start, err) }(time.Now())
+	return r.repo.RemoveRoleFromServiceAccount(ctx, id, roleId)
+}
@@ -0,0 +1,116 @@
+package permctl
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"os"
+	"permission-service/api/permissionapi"
+	"permission-service/internal/validation"
+	"strings"
+)
+
+// BulkUpdate is the outcome of a bulk grant or revoke, with a result per player in the order they were read.
+type BulkUpdate struct {
+	RoleId     string
+	ChangeType permissionapi.BulkUpdatePlayerRolesRequest_ChangeType
+	Results    []*permissionapi.BulkUpdatePlayerRolesResponse_Result
+}
+
+// Counts returns the number of players with each status.
+func (b BulkUpdate) Counts() map[permissionapi.BulkUpdatePlayerRolesResponse_Result_Status]int {
+	counts := make(map[permissionapi.BulkUpdatePlayerRolesResponse_Result_Status]int)
+	for _, result := range b.Results {
+		counts[result.Status]++
+	}
+	return counts
+}
+
+// bulkUpdatePlayerRoles adds or removes a role for every player listed in path, or stdin if path is "-".
+// Large lists are sent in several requests of at most validation.MaxBulkPlayers players.
+func (c *CLI) bulkUpdatePlayerRoles(ctx context.Context, action string, roleId string, path string) error {
+	changeType, ok := permissionapi.BulkUpdatePlayerRolesRequest_ChangeType_value[strings.ToUpper(action)]
+	if !ok {
+		return ErrUsage
+	}
+
+	playerIds, err := readPlayerIdList(path)
+	if err != nil {
+		return err
+	}
+	if len(playerIds) == 0 {
+		return fmt.Errorf("%w: %s contains no player ids", ErrUsage, path)
+	}
+
+	update := BulkUpdate{RoleId: roleId, ChangeType: permissionapi.BulkUpdatePlayerRolesRequest_ChangeType(changeType)}
+	for start := 0; start < len(playerIds); start += validation.MaxBulkPlayers {
+		res, err := c.roles.BulkUpdatePlayerRoles(ctx, &permissionapi.BulkUpdatePlayerRolesRequest{
+			RoleId:     roleId,
+			ChangeType: update.ChangeType,
+			PlayerIds:  playerIds[start:min(start+validation.MaxBulkPlayers, len(playerIds))],
+		})
+		if err != nil {
+			if start > 0 {
+				return fmt.Errorf("failed after updating the first %d players: %w", start, err)
+			}
+			return err
+		}
+
+		update.Results = append(update.Results, res.Results...)
+	}
+
+	if err := c.out.bulkUpdate(update); err != nil {
+		return err
+	}
+	// The update isn't atomic, so the other players keep their changes
+	if failed := update.Counts()[permissionapi.BulkUpdatePlayerRolesResponse_Result_FAILED]; failed > 0 {
+		return fmt.Errorf("failed to update %d players, retrying is safe", failed)
+	}
+	return nil
+}
+
+func readPlayerIdList(path string) ([]string, error) {
+	if path == "-" {
+		return readPlayerIds(os.Stdin)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return readPlayerIds(f)
+}
+
+// readPlayerIds reads player ids from the first column of a CSV file, which also accepts a plain list of one id
+// per line. Blank lines and a header row are skipped. Other invalid ids are kept so the service reports them.
+func readPlayerIds(r io.Reader) ([]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	playerIds := make([]string, 0)
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return playerIds, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid player id list: %w", err)
+		}
+
+		playerId := strings.TrimSpace(record[0])
+		if playerId == "" {
+			continue
+		}
+		if _, err := uuid.Parse(playerId); err != nil && first {
+			continue
+		}
+
+		playerIds = append(playerIds, playerId)
+	}
+}
@@ -0,0 +1,101 @@
+package permctl
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"os"
+	"permission-service/internal/config"
+	"time"
+)
+
+// Metadata keys read by the service, see service.ActorMetadataKey and service.ReasonMetadataKey
+const (
+	actorMetadataKey  = "x-actor-id"
+	reasonMetadataKey = "x-change-reason"
+)
+
+// Dial connects to the service, attaching the token, actor and reason from cfg to every call and limiting each to
+// cfg.Timeout.
+func Dial(cfg config.ClientConfig) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if cfg.TLS.Enabled {
+		tlsConfig, err := createTLSConfig(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	return grpc.Dial(cfg.Address,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithChainUnaryInterceptor(metadataInterceptor(cfg), timeoutInterceptor(cfg.Timeout)),
+	)
+}
+
+// timeoutInterceptor limits each RPC to timeout, so commands making many calls (e.g. imports) aren't cut short.
+func timeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func metadataInterceptor(cfg config.ClientConfig) grpc.UnaryClientInterceptor {
+	pairs := make([]string, 0, 6)
+	if cfg.Token != "" {
+		pairs = append(pairs, "authorization", "Bearer "+cfg.Token)
+	}
+	if cfg.Actor != "" {
+		pairs = append(pairs, actorMetadataKey, cfg.Actor)
+	}
+	if cfg.Reason != "" {
+		pairs = append(pairs, reasonMetadataKey, cfg.Reason)
+	}
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if len(pairs) > 0 {
+			ctx = metadata.AppendToOutgoingContext(ctx, pairs...)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func createTLSConfig(cfg config.ClientTLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName: cfg.ServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if cfg.CAFile != "" {
+		caPem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
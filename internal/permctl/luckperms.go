@@ -0,0 +1,98 @@
+package permctl
+
+import (
+	"context"
+	"fmt"
+	"github.com/emortalmc/proto-specs/gen/go/grpc/permission"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"os"
+	"permission-service/internal/luckperms"
+	"permission-service/internal/repository/model"
+	"permission-service/internal/roleset"
+)
+
+// LuckPermsImport is the outcome of importing a LuckPerms export.
+type LuckPermsImport struct {
+	Plan *roleset.Plan
+	// Players is the number of players given a role other than the default role
+	Players int
+	// Grants is the number of roles given to players, excluding the default role
+	Grants int
+	// AlreadyHeld is the number of grants skipped because the player already had the role
+	AlreadyHeld int
+	Applied     bool
+
+	Issues []luckperms.Issue
+}
+
+// importLuckPerms creates roles from the groups of a LuckPerms export and gives players the roles of their groups.
+// Everything that can't be mapped is reported rather than failing the import.
+func (c *CLI) importLuckPerms(ctx context.Context, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	format := c.flags.Format
+	if format == "" {
+		format = luckperms.FormatFromPath(path)
+	}
+
+	export, err := luckperms.Parse(data, format)
+	if err != nil {
+		return fmt.Errorf("invalid LuckPerms export %s: %w", path, err)
+	}
+	converted := luckperms.Convert(export, c.flags.DefaultRole)
+
+	current, err := c.getAllModelRoles(ctx)
+	if err != nil {
+		return err
+	}
+
+	result := LuckPermsImport{Plan: roleset.Diff(current, converted.Roles), Issues: converted.Issues}
+	for _, player := range converted.Players {
+		if len(player.Roles) > 1 {
+			result.Players++
+			result.Grants += len(player.Roles) - 1
+		}
+	}
+
+	if c.flags.DryRun {
+		return c.out.luckPerms(result)
+	}
+
+	if err := result.Plan.Apply(ctx, roleWriter{client: c.permissions}); err != nil {
+		return err
+	}
+	if result.AlreadyHeld, err = c.grantPlayerRoles(ctx, converted.Players); err != nil {
+		return err
+	}
+	result.Applied = true
+
+	return c.out.luckPerms(result)
+}
+
+// grantPlayerRoles gives players their roles, returning how many they already had so imports can be re-run.
+func (c *CLI) grantPlayerRoles(ctx context.Context, players []*model.Player) (int, error) {
+	alreadyHeld := 0
+
+	for _, player := range players {
+		for _, roleId := range player.Roles {
+			if roleId == c.flags.DefaultRole {
+				continue
+			}
+
+			_, err := c.permissions.AddRoleToPlayer(ctx, &permission.AddRoleToPlayerRequest{PlayerId: player.Id.String(), RoleId: roleId})
+			if status.Code(err) == codes.AlreadyExists {
+				alreadyHeld++
+				continue
+			}
+			if err != nil {
+				return alreadyHeld, fmt.Errorf("failed to add role %s to %s: %w", roleId, player.Id, err)
+			}
+		}
+	}
+
+	return alreadyHeld, nil
+}
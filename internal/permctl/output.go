@@ -0,0 +1,307 @@
+package permctl
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/emortalmc/proto-specs/gen/go/grpc/permission"
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"permission-service/api/permissionapi"
+	"permission-service/internal/luckperms"
+	"permission-service/internal/repository/model"
+	"permission-service/internal/roleset"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+)
+
+type printer interface {
+	roles(roles []*protoModel.Role) error
+	role(role *protoModel.Role) error
+	playerRoles(res *permission.PlayerRolesResponse) error
+	check(result PermissionCheck) error
+	// plan reports the changes of a role set import, and whether they were made
+	plan(plan *roleset.Plan, applied bool) error
+	luckPerms(result LuckPermsImport) error
+	bulkUpdate(update BulkUpdate) error
+	// done reports a change that has no result to print
+	done(message string) error
+}
+
+func newPrinter(out io.Writer, format string) (printer, error) {
+	switch format {
+	case OutputTable, "":
+		return &tablePrinter{out: out}, nil
+	case OutputJSON:
+		return &jsonPrinter{out: out}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown output format %q", ErrUsage, format)
+	}
+}
+
+type tablePrinter struct {
+	out io.Writer
+}
+
+func (p *tablePrinter) table(header string, rows [][]string) error {
+	w := tabwriter.NewWriter(p.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, header)
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+func (p *tablePrinter) roles(roles []*protoModel.Role) error {
+	rows := make([][]string, len(roles))
+	for i, role := range roles {
+		rows[i] = []string{fmt.Sprint(role.Priority), role.Id, role.GetDisplayName(), fmt.Sprint(len(role.Permissions))}
+	}
+
+	return p.table("PRIORITY\tID\tDISPLAY NAME\tNODES", rows)
+}
+
+func (p *tablePrinter) role(role *protoModel.Role) error {
+	fmt.Fprintf(p.out, "ID:           %s\nPriority:     %d\nDisplay name: %s\n\n", role.Id, role.Priority, role.GetDisplayName())
+
+	nodes := make([]*protoModel.PermissionNode, len(role.Permissions))
+	copy(nodes, role.Permissions)
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Node < nodes[j].Node })
+
+	rows := make([][]string, len(nodes))
+	for i, node := range nodes {
+		rows[i] = []string{node.Node, node.State.String()}
+	}
+
+	return p.table("NODE\tSTATE", rows)
+}
+
+func (p *tablePrinter) playerRoles(res *permission.PlayerRolesResponse) error {
+	rows := make([][]string, len(res.RoleIds))
+	for i, roleId := range res.RoleIds {
+		active := ""
+		if res.ActiveDisplayNameRoleId != nil && roleId == *res.ActiveDisplayNameRoleId {
+			active = "yes"
+		}
+		rows[i] = []string{roleId, active}
+	}
+
+	return p.table("ROLE\tDISPLAY NAME", rows)
+}
+
+func (p *tablePrinter) check(result PermissionCheck) error {
+	state := "DENY"
+	if result.Allowed {
+		state = "ALLOW"
+	}
+
+	switch {
+	case result.MatchedNode == "":
+		_, err := fmt.Fprintf(p.out, "%s: %s (not set by any role)\n", result.Node, state)
+		return err
+	case result.MatchedNode != result.Node:
+		_, err := fmt.Fprintf(p.out, "%s: %s (%s from role %s)\n", result.Node, state, result.MatchedNode, result.Role)
+		return err
+	default:
+		_, err := fmt.Fprintf(p.out, "%s: %s (from role %s)\n", result.Node, state, result.Role)
+		return err
+	}
+}
+
+func (p *tablePrinter) plan(plan *roleset.Plan, applied bool) error {
+	plan.Print(p.out)
+
+	switch {
+	case plan.IsEmpty():
+		_, err := fmt.Fprintln(p.out, "roles already match the role set")
+		return err
+	case applied:
+		_, err := fmt.Fprintf(p.out, "applied %d changes\n", len(plan.Changes))
+		return err
+	default:
+		_, err := fmt.Fprintln(p.out, "dry run, no changes made")
+		return err
+	}
+}
+
+func (p *tablePrinter) luckPerms(result LuckPermsImport) error {
+	result.Plan.Print(p.out)
+
+	if result.Applied {
+		fmt.Fprintf(p.out, "added %d roles to %d players (%d already held)\n", result.Grants-result.AlreadyHeld, result.Players, result.AlreadyHeld)
+	} else {
+		fmt.Fprintf(p.out, "%d roles to add to %d players\n", result.Grants, result.Players)
+	}
+
+	if len(result.Issues) > 0 {
+		fmt.Fprintf(p.out, "\n%d nodes or groups couldn't be imported:\n", len(result.Issues))
+
+		rows := make([][]string, len(result.Issues))
+		for i, issue := range result.Issues {
+			rows[i] = []string{issue.Subject, issue.Node, issue.Reason}
+		}
+		if err := p.table("SUBJECT\tNODE\tREASON", rows); err != nil {
+			return err
+		}
+	}
+
+	if !result.Applied {
+		_, err := fmt.Fprintln(p.out, "dry run, no changes made")
+		return err
+	}
+	return nil
+}
+
+func (p *tablePrinter) bulkUpdate(update BulkUpdate) error {
+	rows := make([][]string, len(update.Results))
+	for i, result := range update.Results {
+		rows[i] = []string{result.PlayerId, result.Status.String()}
+	}
+	if err := p.table("PLAYER\tSTATUS", rows); err != nil {
+		return err
+	}
+
+	counts := update.Counts()
+	verb := "added role %s to %d players"
+	if update.ChangeType == permissionapi.BulkUpdatePlayerRolesRequest_REMOVE {
+		verb = "removed role %s from %d players"
+	}
+	fmt.Fprintf(p.out, "\n"+verb+"\n", update.RoleId, counts[permissionapi.BulkUpdatePlayerRolesResponse_Result_UPDATED])
+
+	for _, s := range []permissionapi.BulkUpdatePlayerRolesResponse_Result_Status{
+		permissionapi.BulkUpdatePlayerRolesResponse_Result_ALREADY_HAS_ROLE,
+		permissionapi.BulkUpdatePlayerRolesResponse_Result_DOES_NOT_HAVE_ROLE,
+		permissionapi.BulkUpdatePlayerRolesResponse_Result_INVALID_PLAYER_ID,
+		permissionapi.BulkUpdatePlayerRolesResponse_Result_DUPLICATE,
+		permissionapi.BulkUpdatePlayerRolesResponse_Result_FAILED,
+	} {
+		if counts[s] > 0 {
+			if _, err := fmt.Fprintf(p.out, "%d %s\n", counts[s], strings.ToLower(strings.ReplaceAll(s.String(), "_", " "))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *tablePrinter) done(message string) error {
+	_, err := fmt.Fprintln(p.out, message)
+	return err
+}
+
+type jsonPrinter struct {
+	out io.Writer
+}
+
+func (p *jsonPrinter) proto(m proto.Message) error {
+	b, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true}.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(p.out, string(b))
+	return err
+}
+
+func (p *jsonPrinter) roles(roles []*protoModel.Role) error {
+	return p.proto(&permission.GetAllRolesResponse{Roles: roles})
+}
+
+func (p *jsonPrinter) role(role *protoModel.Role) error {
+	return p.proto(role)
+}
+
+func (p *jsonPrinter) playerRoles(res *permission.PlayerRolesResponse) error {
+	return p.proto(res)
+}
+
+func (p *jsonPrinter) check(result PermissionCheck) error {
+	return p.encode(result)
+}
+
+type jsonPlanChange struct {
+	Type roleset.ChangeType `json:"type"`
+	Role string             `json:"role"`
+	Diff model.RoleDiff     `json:"diff"`
+}
+
+type jsonPlan struct {
+	Changes    []jsonPlanChange `json:"changes"`
+	Unchanged  []string         `json:"unchanged"`
+	Undeclared []string         `json:"undeclared"`
+}
+
+func newJSONPlan(plan *roleset.Plan) jsonPlan {
+	changes := make([]jsonPlanChange, len(plan.Changes))
+	for i, change := range plan.Changes {
+		changes[i] = jsonPlanChange{Type: change.Type, Role: change.Desired.Id, Diff: change.Diff}
+	}
+
+	return jsonPlan{Changes: changes, Unchanged: plan.Unchanged, Undeclared: plan.Undeclared}
+}
+
+func (p *jsonPrinter) encode(v any) error {
+	enc := json.NewEncoder(p.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (p *jsonPrinter) plan(plan *roleset.Plan, applied bool) error {
+	return p.encode(struct {
+		jsonPlan
+		Applied bool `json:"applied"`
+	}{jsonPlan: newJSONPlan(plan), Applied: applied})
+}
+
+func (p *jsonPrinter) luckPerms(result LuckPermsImport) error {
+	return p.encode(struct {
+		Roles       jsonPlan          `json:"roles"`
+		Players     int               `json:"players"`
+		Grants      int               `json:"grants"`
+		AlreadyHeld int               `json:"alreadyHeld"`
+		Issues      []luckperms.Issue `json:"issues"`
+		Applied     bool              `json:"applied"`
+	}{
+		Roles:       newJSONPlan(result.Plan),
+		Players:     result.Players,
+		Grants:      result.Grants,
+		AlreadyHeld: result.AlreadyHeld,
+		Issues:      result.Issues,
+		Applied:     result.Applied,
+	})
+}
+
+func (p *jsonPrinter) bulkUpdate(update BulkUpdate) error {
+	type jsonResult struct {
+		PlayerId string `json:"playerId"`
+		Status   string `json:"status"`
+	}
+
+	results := make([]jsonResult, len(update.Results))
+	for i, result := range update.Results {
+		results[i] = jsonResult{PlayerId: result.PlayerId, Status: result.Status.String()}
+	}
+
+	counts := make(map[string]int)
+	for s, count := range update.Counts() {
+		counts[s.String()] = count
+	}
+
+	return p.encode(struct {
+		RoleId     string         `json:"roleId"`
+		ChangeType string         `json:"changeType"`
+		Results    []jsonResult   `json:"results"`
+		Counts     map[string]int `json:"counts"`
+	}{RoleId: update.RoleId, ChangeType: update.ChangeType.String(), Results: results, Counts: counts})
+}
+
+func (p *jsonPrinter) done(message string) error {
+	return p.encode(map[string]string{"result": message})
+}
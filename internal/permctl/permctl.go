@@ -0,0 +1,361 @@
+package permctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/emortalmc/proto-specs/gen/go/grpc/permission"
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"github.com/google/uuid"
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"permission-service/api/permissionapi"
+	"permission-service/internal/repository/model"
+	"sort"
+)
+
+const Usage = `Usage: permctl [flags] <command>
+
+Commands:
+  role list
+  role show <role-id>
+  role create <role-id> --priority <n> [--display-name <name>]
+  role update <role-id> [--priority <n>] [--display-name <name>] [--allow <node>]... [--deny <node>]... [--unset <node>]...
+  role delete <role-id>
+  role export [file] [--format yaml|json]
+  role import <file> [--dry-run] [--format yaml|json]
+  luckperms import <export-file> [--dry-run] [--format yaml|json]
+  player roles <player-id>
+  player add <player-id> <role-id>
+  player remove <player-id> <role-id>
+  player bulk add|remove <role-id> <csv-file|->
+  check <player-id> <node>
+
+Flags:
+`
+
+// ErrUsage is returned for unknown commands and missing arguments
+var ErrUsage = errors.New("invalid usage")
+
+// CommandFlags are the flags of individual commands.
+type CommandFlags struct {
+	set *pflag.FlagSet
+
+	Priority    uint32
+	DisplayName string
+	Allow       []string
+	Deny        []string
+	Unset       []string
+
+	// Format of role set and LuckPerms export files, inferred from the file extension if not set
+	Format string
+	DryRun bool
+	// DefaultRole is the id of the role every player holds, which LuckPerms imports don't grant
+	DefaultRole string
+}
+
+// DefineCommandFlags adds the command flags to set. It must be called before the flags are parsed.
+func DefineCommandFlags(set *pflag.FlagSet) *CommandFlags {
+	f := &CommandFlags{set: set}
+	set.Uint32Var(&f.Priority, "priority", 0, "Role priority, higher priority roles override lower ones")
+	set.StringVar(&f.DisplayName, "display-name", "", "Role display name template")
+	set.StringSliceVar(&f.Allow, "allow", nil, "Permission nodes to allow")
+	set.StringSliceVar(&f.Deny, "deny", nil, "Permission nodes to deny")
+	set.StringSliceVar(&f.Unset, "unset", nil, "Permission nodes to remove")
+	set.StringVar(&f.Format, "format", "", "Role set or LuckPerms export file format (yaml, json)")
+	set.BoolVar(&f.DryRun, "dry-run", false, "Print the changes an import would make without making them")
+	set.StringVar(&f.DefaultRole, "default-role", model.DefaultRoleId, "Id of the service's default role (bootstrap.default-role.id)")
+	return f
+}
+
+func (f *CommandFlags) changed(name string) bool {
+	return f.set.Changed(name)
+}
+
+type CLI struct {
+	permissions permission.PermissionServiceClient
+	roles       permissionapi.RoleServiceClient
+
+	flags  *CommandFlags
+	out    printer
+	writer io.Writer
+}
+
+func New(conn grpc.ClientConnInterface, flags *CommandFlags, out io.Writer, format string) (*CLI, error) {
+	p, err := newPrinter(out, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CLI{
+		permissions: permission.NewPermissionServiceClient(conn),
+		roles:       permissionapi.NewRoleServiceClient(conn),
+		flags:       flags,
+		out:         p,
+		writer:      out,
+	}, nil
+}
+
+// Run executes the command in args (e.g. ["role", "show", "admin"]).
+func (c *CLI) Run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return ErrUsage
+	}
+
+	switch args[0] {
+	case "role":
+		return c.runRole(ctx, args[1:])
+	case "player":
+		return c.runPlayer(ctx, args[1:])
+	case "luckperms":
+		if len(args) != 3 || args[1] != "import" {
+			return ErrUsage
+		}
+		return c.importLuckPerms(ctx, args[2])
+	case "check":
+		if len(args) != 3 {
+			return ErrUsage
+		}
+		return c.check(ctx, args[1], args[2])
+	default:
+		return ErrUsage
+	}
+}
+
+func (c *CLI) runRole(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return ErrUsage
+	}
+
+	switch {
+	case args[0] == "list" && len(args) == 1:
+		return c.listRoles(ctx)
+	case args[0] == "show" && len(args) == 2:
+		return c.showRole(ctx, args[1])
+	case args[0] == "create" && len(args) == 2:
+		return c.createRole(ctx, args[1])
+	case args[0] == "update" && len(args) == 2:
+		return c.updateRole(ctx, args[1])
+	case args[0] == "delete" && len(args) == 2:
+		return c.deleteRole(ctx, args[1])
+	case args[0] == "export" && len(args) <= 2:
+		return c.exportRoles(ctx, args[1:])
+	case args[0] == "import" && len(args) == 2:
+		return c.importRoles(ctx, args[1])
+	default:
+		return ErrUsage
+	}
+}
+
+func (c *CLI) runPlayer(ctx context.Context, args []string) error {
+	switch {
+	case len(args) == 2 && args[0] == "roles":
+		return c.playerRoles(ctx, args[1])
+	case len(args) == 3 && args[0] == "add":
+		return c.addRoleToPlayer(ctx, args[1], args[2])
+	case len(args) == 3 && args[0] == "remove":
+		return c.removeRoleFromPlayer(ctx, args[1], args[2])
+	case len(args) == 4 && args[0] == "bulk":
+		return c.bulkUpdatePlayerRoles(ctx, args[1], args[2], args[3])
+	default:
+		return ErrUsage
+	}
+}
+
+func (c *CLI) getAllRoles(ctx context.Context) ([]*protoModel.Role, error) {
+	res, err := c.permissions.GetAllRoles(ctx, &permission.GetAllRolesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	roles := res.Roles
+	sort.SliceStable(roles, func(i, j int) bool {
+		if roles[i].Priority != roles[j].Priority {
+			return roles[i].Priority > roles[j].Priority
+		}
+		return roles[i].Id < roles[j].Id
+	})
+
+	return roles, nil
+}
+
+func (c *CLI) getRole(ctx context.Context, id string) (*protoModel.Role, error) {
+	roles, err := c.getAllRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, role := range roles {
+		if role.Id == id {
+			return role, nil
+		}
+	}
+
+	return nil, status.Errorf(codes.NotFound, "role %s not found", id)
+}
+
+func (c *CLI) listRoles(ctx context.Context) error {
+	roles, err := c.getAllRoles(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.out.roles(roles)
+}
+
+func (c *CLI) showRole(ctx context.Context, id string) error {
+	role, err := c.getRole(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return c.out.role(role)
+}
+
+func (c *CLI) createRole(ctx context.Context, id string) error {
+	if !c.flags.changed("priority") {
+		return fmt.Errorf("%w: --priority is required", ErrUsage)
+	}
+
+	req := &permission.RoleCreateRequest{Id: id, Priority: c.flags.Priority}
+	if c.flags.changed("display-name") {
+		req.DisplayName = &c.flags.DisplayName
+	}
+
+	res, err := c.permissions.CreateRole(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return c.out.role(res.Role)
+}
+
+func (c *CLI) updateRole(ctx context.Context, id string) error {
+	req := &permission.RoleUpdateRequest{Id: id, UnsetPermissions: c.flags.Unset}
+	if c.flags.changed("priority") {
+		req.Priority = &c.flags.Priority
+	}
+	if c.flags.changed("display-name") {
+		req.DisplayName = &c.flags.DisplayName
+	}
+	for _, node := range c.flags.Allow {
+		req.SetPermissions = append(req.SetPermissions, &protoModel.PermissionNode{Node: node, State: protoModel.PermissionNode_ALLOW})
+	}
+	for _, node := range c.flags.Deny {
+		req.SetPermissions = append(req.SetPermissions, &protoModel.PermissionNode{Node: node, State: protoModel.PermissionNode_DENY})
+	}
+
+	if req.Priority == nil && req.DisplayName == nil && len(req.SetPermissions) == 0 && len(req.UnsetPermissions) == 0 {
+		return fmt.Errorf("%w: nothing to update", ErrUsage)
+	}
+
+	res, err := c.permissions.UpdateRole(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return c.out.role(res.Role)
+}
+
+func (c *CLI) deleteRole(ctx context.Context, id string) error {
+	if _, err := c.roles.DeleteRole(ctx, &permissionapi.DeleteRoleRequest{Id: id}); err != nil {
+		return err
+	}
+
+	return c.out.done(fmt.Sprintf("deleted role %s", id))
+}
+
+func (c *CLI) playerRoles(ctx context.Context, playerId string) error {
+	res, err := c.permissions.GetPlayerRoles(ctx, &permission.GetPlayerRolesRequest{PlayerId: playerId})
+	if err != nil {
+		return err
+	}
+
+	return c.out.playerRoles(res)
+}
+
+func (c *CLI) addRoleToPlayer(ctx context.Context, playerId string, roleId string) error {
+	_, err := c.permissions.AddRoleToPlayer(ctx, &permission.AddRoleToPlayerRequest{PlayerId: playerId, RoleId: roleId})
+	if err != nil {
+		return err
+	}
+
+	return c.out.done(fmt.Sprintf("added role %s to %s", roleId, playerId))
+}
+
+func (c *CLI) removeRoleFromPlayer(ctx context.Context, playerId string, roleId string) error {
+	_, err := c.permissions.RemoveRoleFromPlayer(ctx, &permission.RemoveRoleFromPlayerRequest{PlayerId: playerId, RoleId: roleId})
+	if err != nil {
+		return err
+	}
+
+	return c.out.done(fmt.Sprintf("removed role %s from %s", roleId, playerId))
+}
+
+// PermissionCheck is the result of resolving a node against a player's roles
+type PermissionCheck struct {
+	PlayerId string `json:"playerId"`
+	Node     string `json:"node"`
+	Allowed  bool   `json:"allowed"`
+	// MatchedNode is the node or wildcard the result was resolved from. Empty if the node isn't set by any role.
+	MatchedNode string `json:"matchedNode,omitempty"`
+	// Role is the highest priority role setting MatchedNode
+	Role string `json:"role,omitempty"`
+}
+
+// check resolves node the same way the service authorizes players, from the roles they hold.
+func (c *CLI) check(ctx context.Context, playerId string, node string) error {
+	if _, err := uuid.Parse(playerId); err != nil {
+		return fmt.Errorf("%w: player id must be a UUID", ErrUsage)
+	}
+
+	res, err := c.permissions.GetPlayerRoles(ctx, &permission.GetPlayerRolesRequest{PlayerId: playerId})
+	if err != nil {
+		return err
+	}
+
+	allRoles, err := c.getAllRoles(ctx)
+	if err != nil {
+		return err
+	}
+
+	held := make([]*model.Role, 0, len(res.RoleIds))
+	for _, role := range allRoles {
+		for _, roleId := range res.RoleIds {
+			if role.Id == roleId {
+				held = append(held, model.RoleFromProto(role))
+			}
+		}
+	}
+
+	result := PermissionCheck{PlayerId: playerId, Node: node}
+
+	state, matched, ok := model.ResolvePermissions(held, nil).Check(node)
+	if ok {
+		result.Allowed = state == protoModel.PermissionNode_ALLOW
+		result.MatchedNode = matched
+		result.Role = grantingRole(held, matched, state)
+	}
+
+	return c.out.check(result)
+}
+
+// grantingRole returns the highest priority role setting node to state.
+func grantingRole(roles []*model.Role, node string, state protoModel.PermissionNode_PermissionState) string {
+	var found *model.Role
+	for _, role := range roles {
+		for _, perm := range role.Permissions {
+			if perm.Node == node && perm.State == state && (found == nil || role.Priority >= found.Priority) {
+				found = role
+			}
+		}
+	}
+
+	if found == nil {
+		return ""
+	}
+	return found.Id
+}
@@ -0,0 +1,441 @@
+package permctl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/emortalmc/proto-specs/gen/go/grpc/permission"
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"github.com/google/uuid"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"os"
+	"path/filepath"
+	"permission-service/api/permissionapi"
+	"permission-service/internal/config"
+	"testing"
+	"time"
+)
+
+var testRoles = []*protoModel.Role{
+	{Id: "default", Priority: 0, Permissions: []*protoModel.PermissionNode{
+		{Node: "command.*", State: protoModel.PermissionNode_ALLOW},
+	}},
+	{Id: "admin", Priority: 100, Permissions: []*protoModel.PermissionNode{
+		{Node: "command.ban", State: protoModel.PermissionNode_ALLOW},
+		{Node: "command.gamemode", State: protoModel.PermissionNode_DENY},
+	}},
+	{Id: "helper", Priority: 20},
+}
+
+type fakeServer struct {
+	permission.UnimplementedPermissionServiceServer
+	permissionapi.UnimplementedRoleServiceServer
+
+	playerRoles []string
+
+	creates    []*permission.RoleCreateRequest
+	grants     []string
+	lastUpdate *permission.RoleUpdateRequest
+	lastDelete *permissionapi.DeleteRoleRequest
+	lastBulk   *permissionapi.BulkUpdatePlayerRolesRequest
+	lastMD     metadata.MD
+
+	// failBulk is a player id reported as FAILED by bulk updates
+	failBulk string
+}
+
+func (s *fakeServer) GetAllRoles(ctx context.Context, _ *permission.GetAllRolesRequest) (*permission.GetAllRolesResponse, error) {
+	s.lastMD, _ = metadata.FromIncomingContext(ctx)
+	return &permission.GetAllRolesResponse{Roles: testRoles}, nil
+}
+
+func (s *fakeServer) GetPlayerRoles(_ context.Context, _ *permission.GetPlayerRolesRequest) (*permission.PlayerRolesResponse, error) {
+	return &permission.PlayerRolesResponse{RoleIds: s.playerRoles}, nil
+}
+
+func (s *fakeServer) CreateRole(_ context.Context, req *permission.RoleCreateRequest) (*permission.CreateRoleResponse, error) {
+	s.creates = append(s.creates, req)
+	return &permission.CreateRoleResponse{Role: &protoModel.Role{Id: req.Id, Priority: req.Priority}}, nil
+}
+
+func (s *fakeServer) AddRoleToPlayer(_ context.Context, req *permission.AddRoleToPlayerRequest) (*permission.AddRoleToPlayerResponse, error) {
+	for _, roleId := range s.playerRoles {
+		if roleId == req.RoleId {
+			return nil, status.Error(codes.AlreadyExists, "player already has role")
+		}
+	}
+
+	s.grants = append(s.grants, req.PlayerId+" "+req.RoleId)
+	return &permission.AddRoleToPlayerResponse{}, nil
+}
+
+func (s *fakeServer) UpdateRole(_ context.Context, req *permission.RoleUpdateRequest) (*permission.UpdateRoleResponse, error) {
+	s.lastUpdate = req
+	return &permission.UpdateRoleResponse{Role: &protoModel.Role{Id: req.Id}}, nil
+}
+
+func (s *fakeServer) DeleteRole(_ context.Context, req *permissionapi.DeleteRoleRequest) (*permissionapi.DeleteRoleResponse, error) {
+	s.lastDelete = req
+	if req.Id == "default" {
+		return nil, status.Error(codes.FailedPrecondition, "the default role cannot be deleted")
+	}
+	return &permissionapi.DeleteRoleResponse{}, nil
+}
+
+func (s *fakeServer) BulkUpdatePlayerRoles(_ context.Context, req *permissionapi.BulkUpdatePlayerRolesRequest) (*permissionapi.BulkUpdatePlayerRolesResponse, error) {
+	s.lastBulk = req
+
+	results := make([]*permissionapi.BulkUpdatePlayerRolesResponse_Result, len(req.PlayerIds))
+	for i, playerId := range req.PlayerIds {
+		results[i] = &permissionapi.BulkUpdatePlayerRolesResponse_Result{PlayerId: playerId}
+		if _, err := uuid.Parse(playerId); err != nil {
+			results[i].Status = permissionapi.BulkUpdatePlayerRolesResponse_Result_INVALID_PLAYER_ID
+		} else if playerId == s.failBulk {
+			results[i].Status = permissionapi.BulkUpdatePlayerRolesResponse_Result_FAILED
+		}
+	}
+	return &permissionapi.BulkUpdatePlayerRolesResponse{Results: results}, nil
+}
+
+// newTestCLI runs srv over an in-memory connection, parsing args like the command line.
+func newTestCLI(t *testing.T, srv *fakeServer, cfg config.ClientConfig, args ...string) (*CLI, []string, *bytes.Buffer) {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	permission.RegisterPermissionServiceServer(s, srv)
+	permissionapi.RegisterRoleServiceServer(s, srv)
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(metadataInterceptor(cfg)),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	set := pflag.NewFlagSet("permctl", pflag.ContinueOnError)
+	flags := DefineCommandFlags(set)
+	require.NoError(t, set.Parse(args))
+
+	out := &bytes.Buffer{}
+	cli, err := New(conn, flags, out, cfg.Output)
+	require.NoError(t, err)
+
+	return cli, set.Args(), out
+}
+
+func TestCLI_RoleList(t *testing.T) {
+	cli, args, out := newTestCLI(t, &fakeServer{}, config.ClientConfig{}, "role", "list")
+
+	require.NoError(t, cli.Run(context.Background(), args))
+	assert.Equal(t, `PRIORITY  ID       DISPLAY NAME  NODES
+100       admin                  2
+20        helper                 0
+0         default                1
+`, out.String())
+}
+
+func TestCLI_RoleShow_JSON(t *testing.T) {
+	cli, args, out := newTestCLI(t, &fakeServer{}, config.ClientConfig{Output: OutputJSON}, "role", "show", "admin")
+
+	require.NoError(t, cli.Run(context.Background(), args))
+
+	var role map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &role))
+	assert.Equal(t, "admin", role["id"])
+	assert.Len(t, role["permissions"], 2)
+}
+
+func TestCLI_RoleUpdate(t *testing.T) {
+	srv := &fakeServer{}
+	cfg := config.ClientConfig{Actor: "service:discord-bot", Reason: "promotion", Token: "secret"}
+	cli, args, _ := newTestCLI(t, srv, cfg,
+		"role", "update", "helper", "--priority", "30", "--allow", "command.kick,command.mute", "--deny", "command.ban", "--unset", "chat.*")
+
+	require.NoError(t, cli.Run(context.Background(), args))
+
+	req := srv.lastUpdate
+	require.NotNil(t, req)
+	assert.Equal(t, "helper", req.Id)
+	assert.Equal(t, uint32(30), req.GetPriority())
+	assert.Nil(t, req.DisplayName)
+	assert.Equal(t, []string{"chat.*"}, req.UnsetPermissions)
+	assert.Equal(t, []*protoModel.PermissionNode{
+		{Node: "command.kick", State: protoModel.PermissionNode_ALLOW},
+		{Node: "command.mute", State: protoModel.PermissionNode_ALLOW},
+		{Node: "command.ban", State: protoModel.PermissionNode_DENY},
+	}, req.SetPermissions)
+}
+
+func TestCLI_RoleUpdate_NothingToUpdate(t *testing.T) {
+	cli, args, _ := newTestCLI(t, &fakeServer{}, config.ClientConfig{}, "role", "update", "helper")
+
+	assert.ErrorIs(t, cli.Run(context.Background(), args), ErrUsage)
+}
+
+func TestCLI_RoleDelete(t *testing.T) {
+	srv := &fakeServer{}
+	cli, args, out := newTestCLI(t, srv, config.ClientConfig{}, "role", "delete", "helper")
+
+	require.NoError(t, cli.Run(context.Background(), args))
+	assert.Equal(t, "helper", srv.lastDelete.Id)
+	assert.Equal(t, "deleted role helper\n", out.String())
+
+	cli, args, _ = newTestCLI(t, srv, config.ClientConfig{}, "role", "delete", "default")
+	assert.Equal(t, codes.FailedPrecondition, status.Code(cli.Run(context.Background(), args)))
+}
+
+func TestCLI_RoleExport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "roles.json")
+	cli, args, out := newTestCLI(t, &fakeServer{}, config.ClientConfig{}, "role", "export", path)
+
+	require.NoError(t, cli.Run(context.Background(), args))
+	assert.Equal(t, "exported 3 roles to "+path+"\n", out.String())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"roles": [
+		{"id": "admin", "priority": 100, "permissions": [
+			{"node": "command.ban", "permissionState": "ALLOW"},
+			{"node": "command.gamemode", "permissionState": "DENY"}
+		]},
+		{"id": "helper", "priority": 20, "permissions": []},
+		{"id": "default", "priority": 0, "permissions": [{"node": "command.*", "permissionState": "ALLOW"}]}
+	]}`, string(data))
+}
+
+const testRoleSet = `roles:
+  - id: admin
+    priority: 100
+    permissions:
+      - node: command.ban
+        permissionState: ALLOW
+      - node: command.gamemode
+        permissionState: DENY
+  - id: helper
+    priority: 20
+    permissions:
+      - node: command.kick
+        permissionState: ALLOW
+  - id: builder
+    priority: 10
+`
+
+func TestCLI_RoleImport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "roles.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testRoleSet), 0o644))
+
+	t.Run("dry run", func(t *testing.T) {
+		srv := &fakeServer{}
+		cli, args, out := newTestCLI(t, srv, config.ClientConfig{}, "role", "import", path, "--dry-run")
+
+		require.NoError(t, cli.Run(context.Background(), args))
+		assert.Empty(t, srv.creates)
+		assert.Nil(t, srv.lastUpdate)
+		assert.Equal(t, `~ role helper
+    + command.kick ALLOW
++ role builder
+    priority: 10
+  role default is not in the role set and will be left as is
+1 to create, 1 to update, 1 unchanged
+dry run, no changes made
+`, out.String())
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		srv := &fakeServer{}
+		cli, args, out := newTestCLI(t, srv, config.ClientConfig{Output: OutputJSON}, "role", "import", path)
+
+		require.NoError(t, cli.Run(context.Background(), args))
+		require.Len(t, srv.creates, 1)
+		assert.Equal(t, "builder", srv.creates[0].Id)
+		assert.Equal(t, uint32(10), srv.creates[0].Priority)
+		assert.Equal(t, "helper", srv.lastUpdate.Id)
+
+		var res map[string]any
+		require.NoError(t, json.Unmarshal(out.Bytes(), &res))
+		assert.Equal(t, true, res["applied"])
+		assert.Len(t, res["changes"], 2)
+	})
+}
+
+const testLuckPermsExport = `{
+  "groups": {
+    "admin": {"nodes": [{"key": "weight.100"}, {"key": "command.ban"}, {"key": "command.gamemode", "value": false}]},
+    "vip": {"nodes": [{"key": "weight.10"}, {"key": "prefix.10.&6VIP "}, {"key": "meta.colour.gold"}]}
+  },
+  "users": {
+    "069a79f4-44e9-4726-a5be-fca90e38aaf5": {"username": "Notch", "primaryGroup": "vip", "nodes": [{"key": "group.admin"}]},
+    "61699b2e-d327-4a01-9f1e-0ea8c3f06bc6": {"username": "jeb_", "primaryGroup": "default"}
+  }
+}`
+
+func TestCLI_LuckPermsImport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "luckperms.json")
+	require.NoError(t, os.WriteFile(path, []byte(testLuckPermsExport), 0o644))
+
+	t.Run("dry run", func(t *testing.T) {
+		srv := &fakeServer{}
+		cli, args, out := newTestCLI(t, srv, config.ClientConfig{}, "luckperms", "import", path, "--dry-run")
+
+		require.NoError(t, cli.Run(context.Background(), args))
+		assert.Empty(t, srv.creates)
+		assert.Empty(t, srv.grants)
+		assert.Equal(t, `+ role vip
+    priority: 10
+    display name: "<gold>VIP {{.Username}}"
+  role helper is not in the role set and will be left as is
+  role default is not in the role set and will be left as is
+1 to create, 0 to update, 1 unchanged
+2 roles to add to 1 players
+
+1 nodes or groups couldn't be imported:
+SUBJECT    NODE              REASON
+group vip  meta.colour.gold  meta nodes aren't supported
+dry run, no changes made
+`, out.String())
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		srv := &fakeServer{playerRoles: []string{"admin"}}
+		cli, args, out := newTestCLI(t, srv, config.ClientConfig{}, "luckperms", "import", path)
+
+		require.NoError(t, cli.Run(context.Background(), args))
+		require.Len(t, srv.creates, 1)
+		assert.Equal(t, "vip", srv.creates[0].Id)
+		assert.Equal(t, []string{"069a79f4-44e9-4726-a5be-fca90e38aaf5 vip"}, srv.grants)
+		assert.Contains(t, out.String(), "added 1 roles to 1 players (1 already held)\n")
+	})
+}
+
+func TestCLI_Metadata(t *testing.T) {
+	srv := &fakeServer{}
+	cfg := config.ClientConfig{Actor: "service:discord-bot", Reason: "promotion", Token: "secret"}
+	cli, args, _ := newTestCLI(t, srv, cfg, "role", "list")
+
+	require.NoError(t, cli.Run(context.Background(), args))
+	assert.Equal(t, []string{"Bearer secret"}, srv.lastMD.Get("authorization"))
+	assert.Equal(t, []string{"service:discord-bot"}, srv.lastMD.Get(actorMetadataKey))
+	assert.Equal(t, []string{"promotion"}, srv.lastMD.Get(reasonMetadataKey))
+}
+
+func TestCLI_Check(t *testing.T) {
+	playerId := uuid.NewString()
+
+	tests := []struct {
+		name        string
+		playerRoles []string
+		node        string
+		want        string
+	}{
+		{name: "exact node", playerRoles: []string{"default", "admin"}, node: "command.ban", want: "command.ban: ALLOW (from role admin)\n"},
+		{name: "higher priority role denies", playerRoles: []string{"default", "admin"}, node: "command.gamemode",
+			want: "command.gamemode: DENY (from role admin)\n"},
+		{name: "wildcard", playerRoles: []string{"default"}, node: "command.gamemode", want: "command.gamemode: ALLOW (command.* from role default)\n"},
+		{name: "unset", playerRoles: []string{"default"}, node: "chat.colour", want: "chat.colour: DENY (not set by any role)\n"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			cli, args, out := newTestCLI(t, &fakeServer{playerRoles: tt.playerRoles}, config.ClientConfig{}, "check", playerId, tt.node)
+
+			require.NoError(t, cli.Run(context.Background(), args))
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestCLI_Usage(t *testing.T) {
+	for _, args := range [][]string{{}, {"role"}, {"role", "show"}, {"player", "add", uuid.NewString()}, {"unknown"}, {"check", "notauuid", "node"}} {
+		cli, parsed, _ := newTestCLI(t, &fakeServer{}, config.ClientConfig{}, args...)
+		assert.ErrorIs(t, cli.Run(context.Background(), parsed), ErrUsage, args)
+	}
+
+	_, err := New(nil, nil, &bytes.Buffer{}, "yaml")
+	assert.ErrorIs(t, err, ErrUsage)
+}
+
+func TestCLI_PlayerBulk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "players.csv")
+	require.NoError(t, os.WriteFile(path, []byte(`uuid,username
+069a79f4-44e9-4726-a5be-fca90e38aaf5,Notch
+
+notch,notch
+ 61699b2e-d327-4a01-9f1e-0ea8c3f06bc6
+`), 0o644))
+
+	t.Run("add", func(t *testing.T) {
+		srv := &fakeServer{}
+		cli, args, out := newTestCLI(t, srv, config.ClientConfig{}, "player", "bulk", "add", "vip", path)
+
+		require.NoError(t, cli.Run(context.Background(), args))
+		assert.Equal(t, "vip", srv.lastBulk.RoleId)
+		assert.Equal(t, permissionapi.BulkUpdatePlayerRolesRequest_ADD, srv.lastBulk.ChangeType)
+		assert.Equal(t, []string{"069a79f4-44e9-4726-a5be-fca90e38aaf5", "notch", "61699b2e-d327-4a01-9f1e-0ea8c3f06bc6"}, srv.lastBulk.PlayerIds)
+		assert.Equal(t, `PLAYER                                STATUS
+069a79f4-44e9-4726-a5be-fca90e38aaf5  UPDATED
+notch                                 INVALID_PLAYER_ID
+61699b2e-d327-4a01-9f1e-0ea8c3f06bc6  UPDATED
+
+added role vip to 2 players
+1 invalid player id
+`, out.String())
+	})
+
+	t.Run("remove json", func(t *testing.T) {
+		srv := &fakeServer{}
+		cli, args, out := newTestCLI(t, srv, config.ClientConfig{Output: OutputJSON}, "player", "bulk", "remove", "vip", path)
+
+		require.NoError(t, cli.Run(context.Background(), args))
+		assert.Equal(t, permissionapi.BulkUpdatePlayerRolesRequest_REMOVE, srv.lastBulk.ChangeType)
+		assert.Contains(t, out.String(), `"counts": {
+    "INVALID_PLAYER_ID": 1,
+    "UPDATED": 2
+  }`)
+	})
+
+	t.Run("failed players", func(t *testing.T) {
+		srv := &fakeServer{failBulk: "61699b2e-d327-4a01-9f1e-0ea8c3f06bc6"}
+		cli, args, out := newTestCLI(t, srv, config.ClientConfig{}, "player", "bulk", "add", "vip", path)
+
+		// The results are still printed, as the other players were changed
+		assert.EqualError(t, cli.Run(context.Background(), args), "failed to update 1 players, retrying is safe")
+		assert.Contains(t, out.String(), "added role vip to 1 players\n1 invalid player id\n1 failed\n")
+	})
+
+	t.Run("unknown action", func(t *testing.T) {
+		cli, args, _ := newTestCLI(t, &fakeServer{}, config.ClientConfig{}, "player", "bulk", "grant", "vip", path)
+
+		assert.ErrorIs(t, cli.Run(context.Background(), args), ErrUsage)
+	})
+}
+
+func TestTimeoutInterceptor(t *testing.T) {
+	var deadlines []time.Duration
+	invoker := func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+		deadlines = append(deadlines, time.Until(deadline))
+		return nil
+	}
+
+	// Each call gets the whole timeout, however long the previous ones took
+	interceptor := timeoutInterceptor(time.Minute)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, interceptor(context.Background(), "/test", nil, nil, nil, invoker))
+	}
+	for _, d := range deadlines {
+		assert.InDelta(t, time.Minute, d, float64(time.Second))
+	}
+}
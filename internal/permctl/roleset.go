@@ -0,0 +1,101 @@
+package permctl
+
+import (
+	"context"
+	"fmt"
+	"github.com/emortalmc/proto-specs/gen/go/grpc/permission"
+	"os"
+	"permission-service/internal/repository/model"
+	"permission-service/internal/roleset"
+)
+
+// roleWriter adapts the gRPC client to roleset.RoleWriter, so imports go through the API like any other change.
+type roleWriter struct {
+	client permission.PermissionServiceClient
+}
+
+func (w roleWriter) CreateRole(ctx context.Context, req *permission.RoleCreateRequest) (*permission.CreateRoleResponse, error) {
+	return w.client.CreateRole(ctx, req)
+}
+
+func (w roleWriter) UpdateRole(ctx context.Context, req *permission.RoleUpdateRequest) (*permission.UpdateRoleResponse, error) {
+	return w.client.UpdateRole(ctx, req)
+}
+
+func (c *CLI) fileFormat(path string) string {
+	if c.flags.Format != "" {
+		return c.flags.Format
+	}
+	return roleset.FormatFromPath(path)
+}
+
+func (c *CLI) getAllModelRoles(ctx context.Context) ([]*model.Role, error) {
+	protoRoles, err := c.getAllRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]*model.Role, len(protoRoles))
+	for i, role := range protoRoles {
+		roles[i] = model.RoleFromProto(role)
+	}
+
+	return roles, nil
+}
+
+// exportRoles writes every role to the file in args, or stdout if there is none.
+func (c *CLI) exportRoles(ctx context.Context, args []string) error {
+	path := ""
+	if len(args) == 1 {
+		path = args[0]
+	}
+
+	roles, err := c.getAllModelRoles(ctx)
+	if err != nil {
+		return err
+	}
+
+	data, err := roleset.Marshal(roles, c.fileFormat(path))
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		_, err = c.writer.Write(data)
+		return err
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	return c.out.done(fmt.Sprintf("exported %d roles to %s", len(roles), path))
+}
+
+// importRoles creates and updates roles to match the file at path. Roles missing from the file are left as they are.
+func (c *CLI) importRoles(ctx context.Context, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	desired, err := roleset.Unmarshal(data, c.fileFormat(path))
+	if err != nil {
+		return fmt.Errorf("invalid role set %s: %w", path, err)
+	}
+
+	current, err := c.getAllModelRoles(ctx)
+	if err != nil {
+		return err
+	}
+
+	plan := roleset.Diff(current, desired)
+	apply := !c.flags.DryRun && !plan.IsEmpty()
+
+	if apply {
+		if err := plan.Apply(ctx, roleWriter{client: c.permissions}); err != nil {
+			return err
+		}
+	}
+
+	return c.out.plan(plan, apply)
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"permission-service/internal/config"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// restoreCollectionSuffix marks the collections documents are restored into before replacing the originals
+	restoreCollectionSuffix = ".restoring"
+	restoreBatchSize        = 1000
+
+	namespaceNotFoundCode = 26
+)
+
+type mongoCollectionStore struct {
+	database *mongo.Database
+}
+
+func NewMongoCollectionStore(ctx context.Context, logger *zap.SugaredLogger, wg *sync.WaitGroup, cfg config.MongoDBConfig) (CollectionStore, error) {
+	database, err := connectMongo(ctx, logger, wg, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mongoCollectionStore{database: database}, nil
+}
+
+func (m *mongoCollectionStore) CollectionNames(ctx context.Context) ([]string, error) {
+	names, err := m.database.ListCollectionNames(ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+
+	// System collections and leftovers of an interrupted restore aren't part of the dataset
+	filtered := make([]string, 0, len(names))
+	for _, name := range names {
+		if !strings.HasPrefix(name, "system.") && !strings.HasSuffix(name, restoreCollectionSuffix) {
+			filtered = append(filtered, name)
+		}
+	}
+	sort.Strings(filtered)
+
+	return filtered, nil
+}
+
+func (m *mongoCollectionStore) CountDocuments(ctx context.Context, collection string) (int64, error) {
+	return m.database.Collection(collection).CountDocuments(ctx, bson.D{})
+}
+
+func (m *mongoCollectionStore) ExportCollection(ctx context.Context, collection string, fn func(doc bson.Raw) error) error {
+	cursor, err := m.database.Collection(collection).Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		if err := fn(cursor.Current); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+func (m *mongoCollectionStore) ReplaceCollection(ctx context.Context, collection string, docs func(yield func(doc bson.Raw) error) error) error {
+	tempName := collection + restoreCollectionSuffix
+	temp := m.database.Collection(tempName)
+
+	// Left over if a previous restore was interrupted
+	if err := temp.Drop(ctx); err != nil {
+		return fmt.Errorf("failed to drop %s: %w", tempName, err)
+	}
+	if err := m.database.CreateCollection(ctx, tempName); err != nil {
+		return fmt.Errorf("failed to create %s: %w", tempName, err)
+	}
+
+	batch := make([]any, 0, restoreBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if _, err := temp.InsertMany(ctx, batch); err != nil {
+			return fmt.Errorf("failed to insert into %s: %w", tempName, err)
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	err := docs(func(doc bson.Raw) error {
+		batch = append(batch, doc)
+		if len(batch) < restoreBatchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err != nil {
+		return err
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	if err := m.copyIndexes(ctx, collection, temp); err != nil {
+		return err
+	}
+
+	// Renaming over the original replaces it in one step, so readers never see a partially restored collection
+	err = m.database.Client().Database("admin").RunCommand(ctx, bson.D{
+		{Key: "renameCollection", Value: m.database.Name() + "." + tempName},
+		{Key: "to", Value: m.database.Name() + "." + collection},
+		{Key: "dropTarget", Value: true},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to replace %s: %w", collection, err)
+	}
+
+	return nil
+}
+
+// copyIndexes creates the indexes of collection on target, as they aren't part of the backup.
+// A collection that doesn't exist yet has none; the repository creates its indexes when it starts.
+func (m *mongoCollectionStore) copyIndexes(ctx context.Context, collection string, target *mongo.Collection) error {
+	cursor, err := m.database.Collection(collection).Indexes().List(ctx)
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == namespaceNotFoundCode {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list indexes of %s: %w", collection, err)
+	}
+
+	var indexes []struct {
+		Name   string `bson:"name"`
+		Key    bson.D `bson:"key"`
+		Unique bool   `bson:"unique"`
+		Sparse bool   `bson:"sparse"`
+	}
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return fmt.Errorf("failed to list indexes of %s: %w", collection, err)
+	}
+
+	models := make([]mongo.IndexModel, 0, len(indexes))
+	for _, index := range indexes {
+		if index.Name == "_id_" {
+			continue
+		}
+		models = append(models, mongo.IndexModel{
+			Keys:    index.Key,
+			Options: options.Index().SetName(index.Name).SetUnique(index.Unique).SetSparse(index.Sparse),
+		})
+	}
+
+	if len(models) == 0 {
+		return nil
+	}
+	if _, err := target.Indexes().CreateMany(ctx, models); err != nil {
+		return fmt.Errorf("failed to copy indexes of %s: %w", collection, err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoDb "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"testing"
+)
+
+func TestMongoCollectionStore_ReplaceCollection(t *testing.T) {
+	ctx := context.Background()
+	store := &mongoCollectionStore{database: database}
+
+	collection := database.Collection("backupTest")
+	t.Cleanup(func() { _ = collection.Drop(ctx) })
+
+	_, err := collection.Indexes().CreateOne(ctx, mongoDb.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}, {Key: "created", Value: -1}},
+		Options: options.Index().SetName("name_created").SetUnique(true),
+	})
+	require.NoError(t, err)
+	_, err = collection.InsertMany(ctx, []any{bson.M{"_id": 1, "name": "old"}, bson.M{"_id": 2, "name": "removed"}})
+	require.NoError(t, err)
+
+	restored := []bson.Raw{}
+	for _, doc := range []bson.D{{{Key: "_id", Value: 1}, {Key: "name", Value: "new"}}, {{Key: "_id", Value: 3}, {Key: "name", Value: "added"}}} {
+		raw, err := bson.Marshal(doc)
+		require.NoError(t, err)
+		restored = append(restored, raw)
+	}
+
+	err = store.ReplaceCollection(ctx, "backupTest", func(yield func(doc bson.Raw) error) error {
+		for _, doc := range restored {
+			if err := yield(doc); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	exported := []bson.Raw{}
+	require.NoError(t, store.ExportCollection(ctx, "backupTest", func(doc bson.Raw) error {
+		exported = append(exported, doc)
+		return nil
+	}))
+	assert.Equal(t, restored, exported)
+
+	// The index was copied over, and the temporary collection renamed away
+	names, err := database.ListCollectionNames(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.Contains(t, names, "backupTest")
+	assert.NotContains(t, names, "backupTest"+restoreCollectionSuffix)
+
+	specs, err := collection.Indexes().ListSpecifications(ctx)
+	require.NoError(t, err)
+	require.Len(t, specs, 2)
+	assert.Equal(t, "name_created", specs[1].Name)
+	assert.True(t, *specs[1].Unique)
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+)
+
+// RoleDiff describes what changed between two versions of a role.
+type RoleDiff struct {
+	Priority    *PriorityChange    `json:"priority,omitempty"`
+	DisplayName *DisplayNameChange `json:"displayName,omitempty"`
+
+	AddedPermissions   []PermissionNodeDiff `json:"addedPermissions,omitempty"`
+	RemovedPermissions []PermissionNodeDiff `json:"removedPermissions,omitempty"`
+	ChangedPermissions []PermissionNodeDiff `json:"changedPermissions,omitempty"`
+}
+
+type PriorityChange struct {
+	Old uint32 `json:"old"`
+	New uint32 `json:"new"`
+}
+
+type DisplayNameChange struct {
+	Old *string `json:"old"`
+	New *string `json:"new"`
+}
+
+// PermissionNodeDiff is a single node whose state was added, removed or changed.
+// OldState is unset for added nodes and NewState is unset for removed nodes.
+type PermissionNodeDiff struct {
+	Node     string                                     `json:"node"`
+	OldState *protoModel.PermissionNode_PermissionState `json:"oldState,omitempty"`
+	NewState *protoModel.PermissionNode_PermissionState `json:"newState,omitempty"`
+}
+
+// DiffRoles computes the changes from previous to next. Either may be nil,
+// in which case the role is treated as being created or deleted.
+func DiffRoles(previous *Role, next *Role) RoleDiff {
+	if previous == nil {
+		previous = &Role{}
+	}
+	if next == nil {
+		next = &Role{}
+	}
+
+	diff := RoleDiff{}
+
+	if previous.Priority != next.Priority {
+		diff.Priority = &PriorityChange{Old: previous.Priority, New: next.Priority}
+	}
+
+	if !stringPtrEqual(previous.DisplayName, next.DisplayName) {
+		diff.DisplayName = &DisplayNameChange{Old: previous.DisplayName, New: next.DisplayName}
+	}
+
+	oldStates := make(map[string]protoModel.PermissionNode_PermissionState, len(previous.Permissions))
+	for _, p := range previous.Permissions {
+		oldStates[p.Node] = p.State
+	}
+
+	newStates := make(map[string]protoModel.PermissionNode_PermissionState, len(next.Permissions))
+	for _, p := range next.Permissions {
+		newState := p.State
+		newStates[p.Node] = newState
+
+		oldState, existed := oldStates[p.Node]
+		if !existed {
+			diff.AddedPermissions = append(diff.AddedPermissions, PermissionNodeDiff{Node: p.Node, NewState: &newState})
+		} else if oldState != newState {
+			diff.ChangedPermissions = append(diff.ChangedPermissions, PermissionNodeDiff{Node: p.Node, OldState: &oldState, NewState: &newState})
+		}
+	}
+
+	for _, p := range previous.Permissions {
+		if _, exists := newStates[p.Node]; !exists {
+			oldState := p.State
+			diff.RemovedPermissions = append(diff.RemovedPermissions, PermissionNodeDiff{Node: p.Node, OldState: &oldState})
+		}
+	}
+
+	return diff
+}
+
+// ChangedFields returns the names of the top-level fields that changed.
+func (d RoleDiff) ChangedFields() []string {
+	fields := make([]string, 0, 3)
+	if d.Priority != nil {
+		fields = append(fields, "priority")
+	}
+	if d.DisplayName != nil {
+		fields = append(fields, "displayName")
+	}
+	if len(d.AddedPermissions) > 0 || len(d.RemovedPermissions) > 0 || len(d.ChangedPermissions) > 0 {
+		fields = append(fields, "permissions")
+	}
+
+	return fields
+}
+
+// IsEmpty returns true if nothing changed.
+func (d RoleDiff) IsEmpty() bool {
+	return len(d.ChangedFields()) == 0
+}
+
+func stringPtrEqual(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"github.com/stretchr/testify/assert"
+	"permission-service/internal/utils"
+	"testing"
+)
+
+func TestDiffRoles(t *testing.T) {
+	allow := protoModel.PermissionNode_ALLOW
+	deny := protoModel.PermissionNode_DENY
+
+	tests := []struct {
+		name string
+
+		previous *Role
+		next     *Role
+
+		want          RoleDiff
+		wantFields    []string
+		wantEmptyDiff bool
+	}{
+		{
+			name: "no changes",
+			previous: &Role{Id: "test", Priority: 1, DisplayName: utils.PointerOf("name"),
+				Permissions: []PermissionNode{{Node: "a", State: allow}}},
+			next: &Role{Id: "test", Priority: 1, DisplayName: utils.PointerOf("name"),
+				Permissions: []PermissionNode{{Node: "a", State: allow}}},
+
+			want:          RoleDiff{},
+			wantFields:    []string{},
+			wantEmptyDiff: true,
+		},
+		{
+			name:     "priority and display name",
+			previous: &Role{Id: "test", Priority: 1, DisplayName: nil},
+			next:     &Role{Id: "test", Priority: 2, DisplayName: utils.PointerOf("name")},
+
+			want: RoleDiff{
+				Priority:    &PriorityChange{Old: 1, New: 2},
+				DisplayName: &DisplayNameChange{Old: nil, New: utils.PointerOf("name")},
+			},
+			wantFields: []string{"priority", "displayName"},
+		},
+		{
+			name: "permissions added, removed and changed",
+			previous: &Role{Id: "test", Permissions: []PermissionNode{
+				{Node: "kept", State: allow},
+				{Node: "removed", State: deny},
+				{Node: "changed", State: allow},
+			}},
+			next: &Role{Id: "test", Permissions: []PermissionNode{
+				{Node: "kept", State: allow},
+				{Node: "changed", State: deny},
+				{Node: "added", State: allow},
+			}},
+
+			want: RoleDiff{
+				AddedPermissions:   []PermissionNodeDiff{{Node: "added", NewState: &allow}},
+				RemovedPermissions: []PermissionNodeDiff{{Node: "removed", OldState: &deny}},
+				ChangedPermissions: []PermissionNodeDiff{{Node: "changed", OldState: &allow, NewState: &deny}},
+			},
+			wantFields: []string{"permissions"},
+		},
+		{
+			name:     "created",
+			previous: nil,
+			next:     &Role{Id: "test", Priority: 5, Permissions: []PermissionNode{{Node: "a", State: allow}}},
+
+			want: RoleDiff{
+				Priority:         &PriorityChange{Old: 0, New: 5},
+				AddedPermissions: []PermissionNodeDiff{{Node: "a", NewState: &allow}},
+			},
+			wantFields: []string{"priority", "permissions"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffRoles(tt.previous, tt.next)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantFields, got.ChangedFields())
+			assert.Equal(t, tt.wantEmptyDiff, got.IsEmpty())
+		})
+	}
+}
+
+func TestRole_Clone(t *testing.T) {
+	role := &Role{Id: "test", DisplayName: utils.PointerOf("name"),
+		Permissions: []PermissionNode{{Node: "a", State: protoModel.PermissionNode_ALLOW}}}
+
+	clone := role.Clone()
+	assert.Equal(t, role, clone)
+
+	clone.Permissions[0].State = protoModel.PermissionNode_DENY
+	*clone.DisplayName = "changed"
+	assert.Equal(t, protoModel.PermissionNode_ALLOW, role.Permissions[0].State)
+	assert.Equal(t, "name", *role.DisplayName)
+}
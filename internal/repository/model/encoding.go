@@ -0,0 +1,52 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"gopkg.in/yaml.v3"
+	"strings"
+)
+
+// permissionNodeDocument is how a PermissionNode is written to JSON and YAML, with the state by name ("ALLOW", "DENY")
+type permissionNodeDocument struct {
+	Node  string `json:"node" yaml:"node"`
+	State string `json:"permissionState" yaml:"permissionState"`
+}
+
+func (p PermissionNode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(permissionNodeDocument{Node: p.Node, State: p.State.String()})
+}
+
+func (p *PermissionNode) UnmarshalJSON(data []byte) error {
+	var doc permissionNodeDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	return p.fromDocument(doc)
+}
+
+func (p PermissionNode) MarshalYAML() (any, error) {
+	return permissionNodeDocument{Node: p.Node, State: p.State.String()}, nil
+}
+
+func (p *PermissionNode) UnmarshalYAML(value *yaml.Node) error {
+	var doc permissionNodeDocument
+	if err := value.Decode(&doc); err != nil {
+		return err
+	}
+
+	return p.fromDocument(doc)
+}
+
+func (p *PermissionNode) fromDocument(doc permissionNodeDocument) error {
+	state, ok := protoModel.PermissionNode_PermissionState_value[strings.ToUpper(doc.State)]
+	if !ok {
+		return fmt.Errorf("node %s has invalid state %q, must be ALLOW or DENY", doc.Node, doc.State)
+	}
+
+	p.Node = doc.Node
+	p.State = protoModel.PermissionNode_PermissionState(state)
+	return nil
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"encoding/json"
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	"testing"
+)
+
+func TestRole_JSON(t *testing.T) {
+	name := "<red>{{.Username}}"
+	role := &Role{Id: "admin", Priority: 100, DisplayName: &name, Permissions: []PermissionNode{
+		{Node: "command.ban", State: protoModel.PermissionNode_DENY},
+	}}
+
+	b, err := json.Marshal(role)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id": "admin", "priority": 100, "displayName": "<red>{{.Username}}",
+		"permissions": [{"node": "command.ban", "permissionState": "DENY"}]}`, string(b))
+
+	var decoded Role
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, role, &decoded)
+}
+
+func TestPermissionNode_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    PermissionNode
+		wantErr bool
+	}{
+		{name: "allow", input: "node: a.b\npermissionState: ALLOW", want: PermissionNode{Node: "a.b", State: protoModel.PermissionNode_ALLOW}},
+		{name: "lower case", input: "node: a.b\npermissionState: deny", want: PermissionNode{Node: "a.b", State: protoModel.PermissionNode_DENY}},
+		{name: "invalid state", input: "node: a.b\npermissionState: 1", wantErr: true},
+		{name: "missing state", input: "node: a.b", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var node PermissionNode
+			err := yaml.Unmarshal([]byte(tt.input), &node)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, node)
+		})
+	}
+}
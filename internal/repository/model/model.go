@@ -5,13 +5,17 @@ import (
 	"github.com/google/uuid"
 )
 
+// DefaultRoleId is the id of the role every player holds, unless bootstrap.default-role.id configures another.
+// Code acting on the configured default role is given its id rather than using this.
 const DefaultRoleId = "default"
 
 type Role struct {
-	Id            string           `bson:"_id" ,json:"id"`
-	Priority      uint32           `bson:"priority" ,json:"priority"`
-	DisplayName   *string          `bson:"displayName" ,json:"displayName"`
-	Permissions   []PermissionNode `bson:"permissions" ,json:"permissions"`
+	Id            string           `bson:"_id" json:"id" yaml:"id"`
+	Priority      uint32           `bson:"priority" json:"priority" yaml:"priority"`
+	DisplayName   *string          `bson:"displayName" json:"displayName,omitempty" yaml:"displayName,omitempty"`
+	Permissions   []PermissionNode `bson:"permissions" json:"permissions" yaml:"permissions"`
+	// Managed roles are reconciled from the role sync files, so can't be changed through the API
+	Managed       bool             `bson:"managed,omitempty" json:"-" yaml:"-"`
 }
 
 func (r *Role) ToProto() *protoModel.Role {
@@ -28,9 +32,38 @@ func (r *Role) ToProto() *protoModel.Role {
 	}
 }
 
+func RoleFromProto(role *protoModel.Role) *Role {
+	permissions := make([]PermissionNode, 0, len(role.Permissions))
+	for _, p := range role.Permissions {
+		permissions = append(permissions, PermissionNode{Node: p.Node, State: p.State})
+	}
+
+	return &Role{
+		Id:          role.Id,
+		Priority:    role.Priority,
+		DisplayName: role.DisplayName,
+		Permissions: permissions,
+	}
+}
+
+// Clone returns a deep copy of the role.
+func (r *Role) Clone() *Role {
+	clone := *r
+	if r.DisplayName != nil {
+		displayName := *r.DisplayName
+		clone.DisplayName = &displayName
+	}
+	if r.Permissions != nil {
+		clone.Permissions = make([]PermissionNode, len(r.Permissions))
+		copy(clone.Permissions, r.Permissions)
+	}
+
+	return &clone
+}
+
 type PermissionNode struct {
-	Node  string                                    `bson:"node" ,json:"node"`
-	State protoModel.PermissionNode_PermissionState `bson:"permissionState" ,json:"permissionState"`
+	Node  string                                    `bson:"node" json:"node" yaml:"node"`
+	State protoModel.PermissionNode_PermissionState `bson:"permissionState" json:"permissionState" yaml:"permissionState"`
 }
 
 func (p *PermissionNode) ToProto() *protoModel.PermissionNode {
@@ -43,6 +76,6 @@ func (p *PermissionNode) ToProto() *protoModel.PermissionNode {
 // Player does not have an equivalent proto
 // as roles are retrieved separately from the player
 type Player struct {
-	Id    uuid.UUID `bson:"_id" ,json:"id"`
-	Roles []string  `bson:"roles" ,json:"roles"`
+	Id    uuid.UUID `bson:"_id" json:"id"`
+	Roles []string  `bson:"roles" json:"roles"`
 }
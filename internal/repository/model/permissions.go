@@ -0,0 +1,66 @@
+package model
+
+import (
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"sort"
+	"strings"
+)
+
+// ResolvedPermissions are the effective permissions of a player or service account.
+type ResolvedPermissions struct {
+	HighestPriority uint32
+	// Nodes are the resolved states of every node granted by the subject's roles and direct nodes
+	Nodes map[string]protoModel.PermissionNode_PermissionState
+}
+
+// ResolvePermissions combines the nodes of every role a subject holds, higher priority roles overriding lower ones.
+// Nodes held directly override every role.
+func ResolvePermissions(roles []*Role, direct []PermissionNode) *ResolvedPermissions {
+	sorted := make([]*Role, len(roles))
+	copy(sorted, roles)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Priority < sorted[j].Priority
+	})
+
+	resolved := &ResolvedPermissions{Nodes: make(map[string]protoModel.PermissionNode_PermissionState)}
+	for _, role := range sorted {
+		resolved.HighestPriority = role.Priority
+		for _, perm := range role.Permissions {
+			resolved.Nodes[perm.Node] = perm.State
+		}
+	}
+
+	for _, perm := range direct {
+		resolved.Nodes[perm.Node] = perm.State
+	}
+
+	return resolved
+}
+
+// Check returns the state of node and the node it was resolved from, trying the node itself, then each parent
+// wildcard ("permission.role.*", "permission.*", "*"). ok is false if none of them are set.
+func (p *ResolvedPermissions) Check(node string) (state protoModel.PermissionNode_PermissionState, matched string, ok bool) {
+	candidate := node
+	for {
+		if state, ok := p.Nodes[candidate]; ok {
+			return state, candidate, true
+		}
+
+		if candidate == "*" {
+			return protoModel.PermissionNode_DENY, "", false
+		}
+
+		candidate = strings.TrimSuffix(candidate, ".*")
+		if i := strings.LastIndex(candidate, "."); i >= 0 {
+			candidate = candidate[:i] + ".*"
+		} else {
+			candidate = "*"
+		}
+	}
+}
+
+// HasPermission returns whether node resolves to ALLOW. Unset nodes are denied.
+func (p *ResolvedPermissions) HasPermission(node string) bool {
+	state, _, ok := p.Check(node)
+	return ok && state == protoModel.PermissionNode_ALLOW
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestResolvedPermissions_Check(t *testing.T) {
+	allow, deny := protoModel.PermissionNode_ALLOW, protoModel.PermissionNode_DENY
+
+	roles := []*Role{
+		// Deliberately out of priority order
+		{Id: "admin", Priority: 100, Permissions: []PermissionNode{{Node: "*", State: allow}, {Node: "command.ban", State: deny}}},
+		{Id: "default", Priority: 0, Permissions: []PermissionNode{{Node: "command.*", State: allow}, {Node: "command.ban", State: allow}}},
+	}
+	resolved := ResolvePermissions(roles, []PermissionNode{{Node: "command.kick", State: deny}})
+
+	tests := []struct {
+		node string
+
+		wantState   protoModel.PermissionNode_PermissionState
+		wantMatched string
+		wantOk      bool
+	}{
+		{node: "command.ban", wantState: deny, wantMatched: "command.ban", wantOk: true},
+		{node: "command.kick", wantState: deny, wantMatched: "command.kick", wantOk: true},
+		{node: "command.gamemode.creative", wantState: allow, wantMatched: "command.*", wantOk: true},
+		{node: "chat.colour", wantState: allow, wantMatched: "*", wantOk: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.node, func(t *testing.T) {
+			state, matched, ok := resolved.Check(tt.node)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.wantState, state)
+			assert.Equal(t, tt.wantMatched, matched)
+			assert.Equal(t, tt.wantState == allow, resolved.HasPermission(tt.node))
+		})
+	}
+
+	assert.Equal(t, uint32(100), resolved.HighestPriority)
+
+	_, _, ok := ResolvePermissions(nil, nil).Check("command.ban")
+	assert.False(t, ok)
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"fmt"
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"permission-service/api/permissionapi"
+	"strings"
+	"time"
+)
+
+type SubjectType string
+
+const (
+	SubjectTypePlayer         SubjectType = "player"
+	SubjectTypeServiceAccount SubjectType = "service_account"
+
+	// serviceAccountSubjectPrefix marks a service account in a subject string, e.g. "service:discord-bot"
+	serviceAccountSubjectPrefix = "service:"
+)
+
+// Subject is anything that can hold roles: a player or a named service account.
+type Subject struct {
+	Type SubjectType
+	// Id is the player's UUID or the service account's name
+	Id string
+}
+
+func PlayerSubject(playerId uuid.UUID) Subject {
+	return Subject{Type: SubjectTypePlayer, Id: playerId.String()}
+}
+
+func ServiceAccountSubject(name string) Subject {
+	return Subject{Type: SubjectTypeServiceAccount, Id: name}
+}
+
+// ParseSubject parses a player UUID or a "service:<name>" service account.
+func ParseSubject(s string) (Subject, error) {
+	if name, ok := strings.CutPrefix(s, serviceAccountSubjectPrefix); ok {
+		if name == "" {
+			return Subject{}, fmt.Errorf("empty service account name in subject %s", s)
+		}
+		return ServiceAccountSubject(name), nil
+	}
+
+	playerId, err := uuid.Parse(s)
+	if err != nil {
+		return Subject{}, fmt.Errorf("subject %s is neither a player id nor a service account: %w", s, err)
+	}
+
+	return PlayerSubject(playerId), nil
+}
+
+func (s Subject) String() string {
+	if s.Type == SubjectTypeServiceAccount {
+		return serviceAccountSubjectPrefix + s.Id
+	}
+	return s.Id
+}
+
+// SubjectGrants are the roles and direct permission nodes held by a subject.
+// Direct permissions override those granted by roles.
+type SubjectGrants struct {
+	RoleIds     []string
+	Permissions []PermissionNode
+}
+
+// ServiceAccount is a non-player subject, authenticated by an API token.
+type ServiceAccount struct {
+	Id          string `bson:"_id"`
+	Description string `bson:"description"`
+
+	// TokenHash is the hex SHA-256 of the account's API token. The token itself is never stored.
+	TokenHash string `bson:"tokenHash"`
+
+	Roles       []string         `bson:"roles"`
+	Permissions []PermissionNode `bson:"permissions"`
+
+	CreatedAt time.Time `bson:"createdAt"`
+}
+
+func (a *ServiceAccount) ToProto() *permissionapi.ServiceAccount {
+	protoPermissions := make([]*protoModel.PermissionNode, 0, len(a.Permissions))
+	for _, p := range a.Permissions {
+		protoPermissions = append(protoPermissions, p.ToProto())
+	}
+
+	return &permissionapi.ServiceAccount{
+		Id:          a.Id,
+		Description: a.Description,
+		RoleIds:     a.Roles,
+		Permissions: protoPermissions,
+		CreatedAt:   timestamppb.New(a.CreatedAt),
+	}
+}
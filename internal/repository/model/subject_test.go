@@ -0,0 +1,39 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseSubject(t *testing.T) {
+	playerId := uuid.New()
+
+	tests := []struct {
+		name string
+
+		subject string
+
+		want    Subject
+		wantErr bool
+	}{
+		{name: "player", subject: playerId.String(), want: PlayerSubject(playerId)},
+		{name: "service account", subject: "service:discord-bot", want: ServiceAccountSubject("discord-bot")},
+		{name: "empty service account name", subject: "service:", wantErr: true},
+		{name: "neither", subject: "discord-bot", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSubject(tt.subject)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.subject, got.String())
+		})
+	}
+}
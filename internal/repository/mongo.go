@@ -3,16 +3,20 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"go.uber.org/zap"
 	"permission-service/internal/config"
 	"permission-service/internal/repository/model"
 	"permission-service/internal/repository/registrytypes"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -20,6 +24,8 @@ const (
 	databaseName         = "permission-service"
 	roleCollectionName   = "roles"
 	playerCollectionName = "players"
+
+	serviceAccountCollectionName = "serviceAccounts"
 )
 
 type mongoRepository struct {
@@ -28,6 +34,15 @@ type mongoRepository struct {
 
 	roleCollection   *mongo.Collection
 	playerCollection *mongo.Collection
+
+	serviceAccountCollection *mongo.Collection
+
+	// defaultRoleId is given to players when they're created
+	defaultRoleId string
+
+	// timeout and bulkTimeout are time.Durations, changed when the config is reloaded
+	timeout     atomic.Int64
+	bulkTimeout atomic.Int64
 }
 
 var (
@@ -35,20 +50,36 @@ var (
 	DoesNotHaveRoleError = errors.New("player does not have testRole")
 )
 
-func NewMongoRepository(ctx context.Context, logger *zap.SugaredLogger, wg *sync.WaitGroup, cfg config.MongoDBConfig) (Repository, error) {
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.URI).SetRegistry(createCodecRegistry()))
+func NewMongoRepository(ctx context.Context, logger *zap.SugaredLogger, wg *sync.WaitGroup, cfg config.MongoDBConfig,
+	defaultRoleId string) (Repository, error) {
+
+	database, err := connectMongo(ctx, logger, wg, cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	database := client.Database(databaseName)
 	repo := &mongoRepository{
 		database:         database,
 		roleCollection:   database.Collection(roleCollectionName),
 		playerCollection: database.Collection(playerCollectionName),
+
+		serviceAccountCollection: database.Collection(serviceAccountCollectionName),
+
+		defaultRoleId: defaultRoleId,
+	}
+	repo.SetTimeouts(cfg.Timeout, cfg.BulkTimeout)
+
+	err = repo.createIndexes(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	err = repo.createDefaultRole(ctx)
+	return repo, nil
+}
+
+// connectMongo connects to the service's database, disconnecting when ctx is done.
+func connectMongo(ctx context.Context, logger *zap.SugaredLogger, wg *sync.WaitGroup, cfg config.MongoDBConfig) (*mongo.Database, error) {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.URI).SetRegistry(createCodecRegistry()))
 	if err != nil {
 		return nil, err
 	}
@@ -62,25 +93,40 @@ func NewMongoRepository(ctx context.Context, logger *zap.SugaredLogger, wg *sync
 		}
 	}()
 
-	return repo, nil
+	return client.Database(databaseName), nil
 }
 
-func (m *mongoRepository) createDefaultRole(ctx context.Context) error {
-	displayName := "{{.Username}}"
-	role := &model.Role{
-		Id:          model.DefaultRoleId,
-		Priority:    0,
-		DisplayName: &displayName,
-		Permissions: make([]model.PermissionNode, 0),
-	}
+func (m *mongoRepository) SetTimeouts(timeout time.Duration, bulkTimeout time.Duration) {
+	m.timeout.Store(int64(timeout))
+	m.bulkTimeout.Store(int64(bulkTimeout))
+}
 
-	// Create default role if it doesn't already exist
-	_, err := m.roleCollection.UpdateOne(ctx, &bson.M{"_id": model.DefaultRoleId}, &bson.M{"$setOnInsert": &role}, options.Update().SetUpsert(true))
+func (m *mongoRepository) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, time.Duration(m.timeout.Load()))
+}
+
+// withBulkTimeout is used for operations on many documents
+func (m *mongoRepository) withBulkTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, time.Duration(m.bulkTimeout.Load()))
+}
+
+func (m *mongoRepository) createIndexes(ctx context.Context) error {
+	_, err := m.serviceAccountCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.M{"tokenHash": 1},
+		Options: options.Index().SetUnique(true),
+	})
 	return err
 }
 
+func (m *mongoRepository) Ping(ctx context.Context) error {
+	ctx, cancel := m.withTimeout(ctx)
+	defer cancel()
+
+	return m.database.Client().Ping(ctx, readpref.Primary())
+}
+
 func (m *mongoRepository) GetAllRoles(ctx context.Context) ([]*model.Role, error) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := m.withTimeout(ctx)
 	defer cancel()
 
 	cursor, err := m.roleCollection.Find(ctx, bson.D{})
@@ -100,7 +146,7 @@ func (m *mongoRepository) GetAllRoles(ctx context.Context) ([]*model.Role, error
 }
 
 func (m *mongoRepository) GetRole(ctx context.Context, roleId string) (*model.Role, error) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := m.withTimeout(ctx)
 	defer cancel()
 
 	filter := bson.M{"_id": roleId}
@@ -112,7 +158,7 @@ func (m *mongoRepository) GetRole(ctx context.Context, roleId string) (*model.Ro
 }
 
 func (m *mongoRepository) DoesRoleExist(ctx context.Context, roleId string) (bool, error) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := m.withTimeout(ctx)
 	defer cancel()
 
 	filter := bson.M{"_id": roleId}
@@ -126,7 +172,7 @@ func (m *mongoRepository) DoesRoleExist(ctx context.Context, roleId string) (boo
 }
 
 func (m *mongoRepository) CreateRole(ctx context.Context, role *model.Role) error {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := m.withTimeout(ctx)
 	defer cancel()
 
 	_, err := m.roleCollection.InsertOne(ctx, role)
@@ -134,15 +180,93 @@ func (m *mongoRepository) CreateRole(ctx context.Context, role *model.Role) erro
 }
 
 func (m *mongoRepository) UpdateRole(ctx context.Context, role *model.Role) error {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := m.withTimeout(ctx)
 	defer cancel()
 
 	result := m.roleCollection.FindOneAndReplace(ctx, bson.M{"_id": role.Id}, role)
 	return result.Err()
 }
 
+func (m *mongoRepository) DeleteRole(ctx context.Context, roleId string) error {
+	ctx, cancel := m.withBulkTimeout(ctx)
+	defer cancel()
+
+	result, err := m.roleCollection.DeleteOne(ctx, bson.M{"_id": roleId})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	// The role is deleted first, so if this fails holders are left with an unknown role id rather than a role they lost
+	if _, err := m.playerCollection.UpdateMany(ctx, bson.M{"roles": roleId}, bson.M{"$pull": bson.M{"roles": roleId}}); err != nil {
+		return fmt.Errorf("failed to remove role from players: %w", err)
+	}
+	if _, err := m.serviceAccountCollection.UpdateMany(ctx, bson.M{"roles": roleId}, bson.M{"$pull": bson.M{"roles": roleId}}); err != nil {
+		return fmt.Errorf("failed to remove role from service accounts: %w", err)
+	}
+
+	return nil
+}
+
+func (m *mongoRepository) CreateRolesIfMissing(ctx context.Context, roles []*model.Role) ([]string, error) {
+	if len(roles) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := m.withTimeout(ctx)
+	defer cancel()
+
+	writes := make([]mongo.WriteModel, len(roles))
+	for i, role := range roles {
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": role.Id}).
+			SetUpdate(bson.M{"$setOnInsert": role}).
+			SetUpsert(true)
+	}
+
+	result, err := m.roleCollection.BulkWrite(ctx, writes)
+	if err != nil {
+		return nil, err
+	}
+
+	// UpsertedIDs is keyed by the index of the write that inserted
+	created := make([]string, 0, len(result.UpsertedIDs))
+	for i, role := range roles {
+		if _, ok := result.UpsertedIDs[int64(i)]; ok {
+			created = append(created, role.Id)
+		}
+	}
+	return created, nil
+}
+
+func (m *mongoRepository) SetManagedRoles(ctx context.Context, roleIds []string) error {
+	ctx, cancel := m.withTimeout(ctx)
+	defer cancel()
+
+	if roleIds == nil {
+		roleIds = make([]string, 0)
+	}
+
+	if _, err := m.roleCollection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": roleIds}}, bson.M{"$set": bson.M{"managed": true}}); err != nil {
+		return err
+	}
+
+	_, err := m.roleCollection.UpdateMany(ctx, bson.M{"_id": bson.M{"$nin": roleIds}, "managed": true}, bson.M{"$unset": bson.M{"managed": ""}})
+	return err
+}
+
+func (m *mongoRepository) CountPlayers(ctx context.Context) (int64, error) {
+	ctx, cancel := m.withTimeout(ctx)
+	defer cancel()
+
+	// Estimated from collection metadata, counting documents would scan the whole collection
+	return m.playerCollection.EstimatedDocumentCount(ctx)
+}
+
 func (m *mongoRepository) GetPlayerRoleIds(ctx context.Context, playerId uuid.UUID) ([]string, error) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := m.withTimeout(ctx)
 	defer cancel()
 
 	filter := bson.M{"_id": playerId}
@@ -153,13 +277,13 @@ func (m *mongoRepository) GetPlayerRoleIds(ctx context.Context, playerId uuid.UU
 	if err != nil {
 		// insert into db if not exists
 		if err == mongo.ErrNoDocuments {
-			_, err := m.playerCollection.InsertOne(ctx, model.Player{Id: playerId, Roles: []string{model.DefaultRoleId}})
+			_, err := m.playerCollection.InsertOne(ctx, model.Player{Id: playerId, Roles: []string{m.defaultRoleId}})
 
 			if err != nil {
 				return nil, err
 			}
 
-			return []string{model.DefaultRoleId}, nil
+			return []string{m.defaultRoleId}, nil
 		} else {
 			return nil, err
 		}
@@ -169,7 +293,7 @@ func (m *mongoRepository) GetPlayerRoleIds(ctx context.Context, playerId uuid.UU
 }
 
 func (m *mongoRepository) AddRoleToPlayer(ctx context.Context, playerId uuid.UUID, roleId string) error {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := m.withTimeout(ctx)
 	defer cancel()
 
 	result, err := m.playerCollection.UpdateOne(ctx, bson.M{"_id": playerId}, bson.M{"$addToSet": bson.M{"roles": roleId}})
@@ -181,7 +305,7 @@ func (m *mongoRepository) AddRoleToPlayer(ctx context.Context, playerId uuid.UUI
 	if result.ModifiedCount == 0 {
 		if result.MatchedCount == 0 {
 			// insert into db if not exists
-			_, err = m.playerCollection.InsertOne(ctx, model.Player{Id: playerId, Roles: []string{model.DefaultRoleId, roleId}})
+			_, err = m.playerCollection.InsertOne(ctx, model.Player{Id: playerId, Roles: []string{m.defaultRoleId, roleId}})
 			if err != nil {
 				return err
 			}
@@ -194,7 +318,7 @@ func (m *mongoRepository) AddRoleToPlayer(ctx context.Context, playerId uuid.UUI
 }
 
 func (m *mongoRepository) RemoveRoleFromPlayer(ctx context.Context, playerId uuid.UUID, roleId string) error {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := m.withTimeout(ctx)
 	defer cancel()
 
 	result, err := m.playerCollection.UpdateOne(ctx, bson.M{"_id": playerId}, bson.M{"$pull": bson.M{"roles": roleId}})
@@ -209,6 +333,273 @@ func (m *mongoRepository) RemoveRoleFromPlayer(ctx context.Context, playerId uui
 	return err
 }
 
+func (m *mongoRepository) AddRoleToPlayers(ctx context.Context, playerIds []uuid.UUID, roleId string) ([]uuid.UUID, []uuid.UUID, error) {
+	ctx, cancel := m.withBulkTimeout(ctx)
+	defer cancel()
+
+	// Each write only matches a player without the role and inserts the player otherwise, so it fails with a
+	// duplicate key error if the player already has the role and changes the player if it succeeds
+	writes := make([]mongo.WriteModel, len(playerIds))
+	for i, playerId := range playerIds {
+		roles := bson.M{"$concatArrays": bson.A{
+			bson.M{"$ifNull": bson.A{"$roles", bson.M{"$literal": bson.A{m.defaultRoleId}}}},
+			bson.M{"$literal": bson.A{roleId}},
+		}}
+
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": playerId, "roles": bson.M{"$ne": roleId}}).
+			SetUpdate(bson.A{bson.M{"$set": bson.M{"roles": roles}}}).
+			SetUpsert(true)
+	}
+
+	_, err := m.playerCollection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	failed := failedWrites(err)
+	if failed == nil {
+		return nil, playerIds, err
+	}
+
+	changed := make([]uuid.UUID, 0, len(playerIds))
+	var failedIds []uuid.UUID
+	var writeErr error
+	for i, playerId := range playerIds {
+		failure, ok := failed[i]
+		switch {
+		case !ok:
+			changed = append(changed, playerId)
+		case !mongo.IsDuplicateKeyError(failure.WriteError):
+			failedIds = append(failedIds, playerId)
+			writeErr = err
+		}
+	}
+
+	return changed, failedIds, writeErr
+}
+
+func (m *mongoRepository) RemoveRoleFromPlayers(ctx context.Context, playerIds []uuid.UUID, roleId string) ([]uuid.UUID, []uuid.UUID, error) {
+	ctx, cancel := m.withBulkTimeout(ctx)
+	defer cancel()
+
+	existing, err := m.findPlayers(ctx, playerIds)
+	if err != nil {
+		return nil, playerIds, err
+	}
+
+	holders := make([]uuid.UUID, 0, len(existing))
+	writes := make([]mongo.WriteModel, 0, len(existing))
+	for _, playerId := range playerIds {
+		if player, ok := existing[playerId]; ok && slices.Contains(player.Roles, roleId) {
+			holders = append(holders, playerId)
+			writes = append(writes, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": playerId, "roles": roleId}).
+				SetUpdate(bson.M{"$pull": bson.M{"roles": roleId}}))
+		}
+	}
+
+	if len(writes) == 0 {
+		return holders, nil, nil
+	}
+
+	// A player whose role is removed concurrently is still reported as changed, so it may be notified twice
+	_, err = m.playerCollection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	failed := failedWrites(err)
+	if failed == nil {
+		return nil, holders, err
+	}
+
+	changed := make([]uuid.UUID, 0, len(holders))
+	var failedIds []uuid.UUID
+	for i, playerId := range holders {
+		if _, ok := failed[i]; ok {
+			failedIds = append(failedIds, playerId)
+		} else {
+			changed = append(changed, playerId)
+		}
+	}
+
+	return changed, failedIds, err
+}
+
+// failedWrites returns the writes of an unordered bulk write that failed, by index. It's nil if the bulk write
+// failed as a whole, as it's then unknown which writes were applied.
+func failedWrites(err error) map[int]mongo.BulkWriteError {
+	if err == nil {
+		return map[int]mongo.BulkWriteError{}
+	}
+
+	var ex mongo.BulkWriteException
+	if !errors.As(err, &ex) || ex.WriteConcernError != nil {
+		return nil
+	}
+
+	failed := make(map[int]mongo.BulkWriteError, len(ex.WriteErrors))
+	for _, writeErr := range ex.WriteErrors {
+		failed[writeErr.Index] = writeErr
+	}
+	return failed
+}
+
+func (m *mongoRepository) findPlayers(ctx context.Context, playerIds []uuid.UUID) (map[uuid.UUID]*model.Player, error) {
+	cursor, err := m.playerCollection.Find(ctx, bson.M{"_id": bson.M{"$in": playerIds}})
+	if err != nil {
+		return nil, err
+	}
+
+	var players []*model.Player
+	if err := cursor.All(ctx, &players); err != nil {
+		return nil, err
+	}
+
+	byId := make(map[uuid.UUID]*model.Player, len(players))
+	for _, player := range players {
+		byId[player.Id] = player
+	}
+
+	return byId, nil
+}
+
+func (m *mongoRepository) GetSubjectGrants(ctx context.Context, subject model.Subject) (*model.SubjectGrants, error) {
+	switch subject.Type {
+	case model.SubjectTypePlayer:
+		playerId, err := uuid.Parse(subject.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx, cancel := m.withTimeout(ctx)
+		defer cancel()
+
+		// Read only, unlike GetPlayerRoleIds, so checking an unknown actor doesn't create them
+		var player *model.Player
+		err = m.playerCollection.FindOne(ctx, bson.M{"_id": playerId}).Decode(&player)
+		if err == mongo.ErrNoDocuments {
+			return &model.SubjectGrants{RoleIds: []string{m.defaultRoleId}}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return &model.SubjectGrants{RoleIds: player.Roles}, nil
+	case model.SubjectTypeServiceAccount:
+		account, err := m.GetServiceAccount(ctx, subject.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		return &model.SubjectGrants{RoleIds: account.Roles, Permissions: account.Permissions}, nil
+	default:
+		return nil, fmt.Errorf("unknown subject type %s", subject.Type)
+	}
+}
+
+func (m *mongoRepository) GetAllServiceAccounts(ctx context.Context) ([]*model.ServiceAccount, error) {
+	ctx, cancel := m.withTimeout(ctx)
+	defer cancel()
+
+	cursor, err := m.serviceAccountCollection.Find(ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+
+	var mongoResult []model.ServiceAccount
+	err = cursor.All(ctx, &mongoResult)
+
+	slice := make([]*model.ServiceAccount, len(mongoResult))
+	for i := range mongoResult {
+		slice[i] = &mongoResult[i]
+	}
+
+	return slice, err
+}
+
+func (m *mongoRepository) GetServiceAccount(ctx context.Context, id string) (*model.ServiceAccount, error) {
+	ctx, cancel := m.withTimeout(ctx)
+	defer cancel()
+
+	var result *model.ServiceAccount
+	err := m.serviceAccountCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&result)
+
+	return result, err
+}
+
+func (m *mongoRepository) GetServiceAccountByTokenHash(ctx context.Context, tokenHash string) (*model.ServiceAccount, error) {
+	ctx, cancel := m.withTimeout(ctx)
+	defer cancel()
+
+	var result *model.ServiceAccount
+	err := m.serviceAccountCollection.FindOne(ctx, bson.M{"tokenHash": tokenHash}).Decode(&result)
+
+	return result, err
+}
+
+func (m *mongoRepository) CreateServiceAccount(ctx context.Context, account *model.ServiceAccount) error {
+	ctx, cancel := m.withTimeout(ctx)
+	defer cancel()
+
+	_, err := m.serviceAccountCollection.InsertOne(ctx, account)
+	return err
+}
+
+func (m *mongoRepository) UpdateServiceAccount(ctx context.Context, account *model.ServiceAccount) error {
+	ctx, cancel := m.withTimeout(ctx)
+	defer cancel()
+
+	result := m.serviceAccountCollection.FindOneAndReplace(ctx, bson.M{"_id": account.Id}, account)
+	return result.Err()
+}
+
+func (m *mongoRepository) DeleteServiceAccount(ctx context.Context, id string) error {
+	ctx, cancel := m.withTimeout(ctx)
+	defer cancel()
+
+	result, err := m.serviceAccountCollection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+func (m *mongoRepository) AddRoleToServiceAccount(ctx context.Context, id string, roleId string) error {
+	ctx, cancel := m.withTimeout(ctx)
+	defer cancel()
+
+	result, err := m.serviceAccountCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$addToSet": bson.M{"roles": roleId}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	if result.ModifiedCount == 0 {
+		return AlreadyHasRoleError
+	}
+
+	return nil
+}
+
+func (m *mongoRepository) RemoveRoleFromServiceAccount(ctx context.Context, id string, roleId string) error {
+	ctx, cancel := m.withTimeout(ctx)
+	defer cancel()
+
+	result, err := m.serviceAccountCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$pull": bson.M{"roles": roleId}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	if result.ModifiedCount == 0 {
+		return DoesNotHaveRoleError
+	}
+
+	return nil
+}
+
 func createCodecRegistry() *bsoncodec.Registry {
 	r := bson.NewRegistry()
 
@@ -10,6 +10,7 @@ import (
 	"github.com/ory/dockertest/v3"
 	"github.com/ory/dockertest/v3/docker"
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
 	mongoDb "go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
@@ -20,6 +21,7 @@ import (
 	"permission-service/internal/utils"
 	"sync"
 	"testing"
+	"time"
 )
 
 const (
@@ -77,7 +79,7 @@ func TestMain(m *testing.M) {
 		logger := unsugared.Sugar()
 
 		// Ping was successful, let's create the mongo repo
-		repo, err = NewMongoRepository(ctx, logger, &sync.WaitGroup{}, config.MongoDBConfig{URI: uri})
+		repo, err = NewMongoRepository(ctx, logger, &sync.WaitGroup{}, config.MongoDBConfig{URI: uri, Timeout: 5 * time.Second, BulkTimeout: 30 * time.Second}, model.DefaultRoleId)
 		database = dbClient.Database(databaseName)
 		return
 	})
@@ -245,6 +247,96 @@ func TestMongoRepository_UpdateRole(t *testing.T) {
 	assert.Equal(t, mongoDb.ErrNoDocuments, err)
 }
 
+func TestMongoRepository_DeleteRole(t *testing.T) {
+	// Setup
+	_, err := database.Collection(roleCollectionName).InsertOne(context.Background(), testRole)
+	assert.NoError(t, err)
+	_, err = database.Collection(playerCollectionName).InsertOne(context.Background(), model.Player{
+		Id:    testUserIds[0],
+		Roles: []string{model.DefaultRoleId, testRole.Id},
+	})
+	assert.NoError(t, err)
+	_, err = database.Collection(serviceAccountCollectionName).InsertOne(context.Background(), model.ServiceAccount{
+		Id:    "bot",
+		Roles: []string{testRole.Id},
+	})
+	assert.NoError(t, err)
+
+	// Test
+	err = repo.DeleteRole(context.Background(), testRole.Id)
+	assert.NoError(t, err)
+
+	// Verify
+	exists, err := repo.DoesRoleExist(context.Background(), testRole.Id)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	roleIds, err := repo.GetPlayerRoleIds(context.Background(), testUserIds[0])
+	assert.NoError(t, err)
+	assert.Equal(t, []string{model.DefaultRoleId}, roleIds)
+
+	account, err := repo.GetServiceAccount(context.Background(), "bot")
+	assert.NoError(t, err)
+	assert.Empty(t, account.Roles)
+
+	cleanup()
+
+	err = repo.DeleteRole(context.Background(), testRole.Id)
+	assert.Equal(t, mongoDb.ErrNoDocuments, err)
+}
+
+func TestMongoRepository_SetManagedRoles(t *testing.T) {
+	// Setup
+	_, err := database.Collection(roleCollectionName).InsertOne(context.Background(), testRole)
+	assert.NoError(t, err)
+	_, err = database.Collection(roleCollectionName).InsertOne(context.Background(), model.Role{Id: "legacy", Managed: true})
+	assert.NoError(t, err)
+
+	// Test
+	err = repo.SetManagedRoles(context.Background(), []string{testRole.Id})
+	assert.NoError(t, err)
+
+	// Verify
+	role, err := repo.GetRole(context.Background(), testRole.Id)
+	assert.NoError(t, err)
+	assert.True(t, role.Managed)
+
+	role, err = repo.GetRole(context.Background(), "legacy")
+	assert.NoError(t, err)
+	assert.False(t, role.Managed)
+
+	cleanup()
+}
+
+func TestMongoRepository_CreateRolesIfMissing(t *testing.T) {
+	// Setup
+	_, err := database.Collection(roleCollectionName).InsertOne(context.Background(), testRole)
+	assert.NoError(t, err)
+
+	// Test
+	changed := testRole
+	changed.Priority = 50
+	created, err := repo.CreateRolesIfMissing(context.Background(), []*model.Role{&changed, &testMinimumRole})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{testMinimumRole.Id}, created)
+
+	// Verify the existing role was left as it was
+	role, err := repo.GetRole(context.Background(), testRole.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, testRole, *role)
+
+	role, err = repo.GetRole(context.Background(), testMinimumRole.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, testMinimumRole, *role)
+
+	// Nothing is created the second time
+	created, err = repo.CreateRolesIfMissing(context.Background(), []*model.Role{&changed, &testMinimumRole})
+	assert.NoError(t, err)
+	assert.Empty(t, created)
+
+	cleanup()
+}
+
 func TestMongoRepository_GetPlayerRoleIds(t *testing.T) {
 	// Test default behaviour when user is not present
 	roleIds, err := repo.GetPlayerRoleIds(context.Background(), testUserIds[0])
@@ -329,6 +421,52 @@ func TestMongoRepository_AddRoleToPlayer2(t *testing.T) {
 	cleanup()
 }
 
+func TestMongoRepository_AddRoleToPlayers(t *testing.T) {
+	// Setup: one player already has the role, one doesn't and one doesn't exist yet
+	_, err := database.Collection(playerCollectionName).InsertMany(context.Background(), []any{
+		model.Player{Id: testUserIds[0], Roles: []string{model.DefaultRoleId, testRole.Id}},
+		model.Player{Id: testUserIds[1], Roles: []string{model.DefaultRoleId}},
+	})
+	assert.NoError(t, err)
+
+	// Test
+	changed, failed, err := repo.AddRoleToPlayers(context.Background(), testUserIds, testRole.Id)
+	assert.NoError(t, err)
+	assert.Empty(t, failed)
+	assert.Equal(t, []uuid.UUID{testUserIds[1], testUserIds[2]}, changed)
+
+	// Verify
+	for _, id := range testUserIds {
+		roleIds, err := repo.GetPlayerRoleIds(context.Background(), id)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{model.DefaultRoleId, testRole.Id}, roleIds)
+	}
+
+	cleanup()
+}
+
+func TestMongoRepository_RemoveRoleFromPlayers(t *testing.T) {
+	// Setup
+	_, err := database.Collection(playerCollectionName).InsertMany(context.Background(), []any{
+		model.Player{Id: testUserIds[0], Roles: []string{model.DefaultRoleId, testRole.Id}},
+		model.Player{Id: testUserIds[1], Roles: []string{model.DefaultRoleId}},
+	})
+	assert.NoError(t, err)
+
+	// Test
+	changed, failed, err := repo.RemoveRoleFromPlayers(context.Background(), testUserIds, testRole.Id)
+	assert.NoError(t, err)
+	assert.Empty(t, failed)
+	assert.Equal(t, []uuid.UUID{testUserIds[0]}, changed)
+
+	// Verify
+	roleIds, err := repo.GetPlayerRoleIds(context.Background(), testUserIds[0])
+	assert.NoError(t, err)
+	assert.Equal(t, []string{model.DefaultRoleId}, roleIds)
+
+	cleanup()
+}
+
 func TestMongoRepository_RemoveRoleFromPlayer(t *testing.T) {
 	// Test when the user does not exist. DoesNotHaveRoleError should be returned.
 	err := repo.RemoveRoleFromPlayer(context.Background(), testUserIds[0], testRole.Id)
@@ -365,8 +503,69 @@ func TestMongoRepository_RemoveRoleFromPlayer(t *testing.T) {
 	cleanup()
 }
 
+func TestMongoRepository_ServiceAccount(t *testing.T) {
+	account := &model.ServiceAccount{
+		Id:          "discord-bot",
+		TokenHash:   "hash",
+		Roles:       []string{},
+		Permissions: []model.PermissionNode{{Node: "test", State: permission.PermissionNode_ALLOW}},
+	}
+	assert.NoError(t, repo.CreateServiceAccount(context.Background(), account))
+
+	got, err := repo.GetServiceAccountByTokenHash(context.Background(), "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, account.Id, got.Id)
+
+	_, err = repo.GetServiceAccountByTokenHash(context.Background(), "unknown")
+	assert.Equal(t, mongoDb.ErrNoDocuments, err)
+
+	// Roles behave the same as they do for players
+	assert.NoError(t, repo.AddRoleToServiceAccount(context.Background(), account.Id, testRole.Id))
+	assert.Equal(t, AlreadyHasRoleError, repo.AddRoleToServiceAccount(context.Background(), account.Id, testRole.Id))
+	assert.Equal(t, mongoDb.ErrNoDocuments, repo.AddRoleToServiceAccount(context.Background(), "unknown", testRole.Id))
+
+	grants, err := repo.GetSubjectGrants(context.Background(), model.ServiceAccountSubject(account.Id))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{testRole.Id}, grants.RoleIds)
+	assert.Equal(t, account.Permissions, grants.Permissions)
+
+	assert.NoError(t, repo.RemoveRoleFromServiceAccount(context.Background(), account.Id, testRole.Id))
+	assert.Equal(t, DoesNotHaveRoleError, repo.RemoveRoleFromServiceAccount(context.Background(), account.Id, testRole.Id))
+
+	assert.NoError(t, repo.DeleteServiceAccount(context.Background(), account.Id))
+	assert.Equal(t, mongoDb.ErrNoDocuments, repo.DeleteServiceAccount(context.Background(), account.Id))
+
+	cleanup()
+}
+
 func cleanup() {
 	if err := database.Drop(context.Background()); err != nil {
 		log.Panicf("could not drop database: %s", err)
 	}
 }
+
+func TestMongoRepository_CountPlayers(t *testing.T) {
+	for _, id := range testUserIds {
+		_, err := repo.GetPlayerRoleIds(context.Background(), id)
+		assert.NoError(t, err)
+	}
+
+	count, err := repo.CountPlayers(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(testUserIds)), count)
+
+	cleanup()
+}
+
+func TestMongoRepository_GetSubjectGrants_UnknownPlayer(t *testing.T) {
+	grants, err := repo.GetSubjectGrants(context.Background(), model.PlayerSubject(testUserIds[0]))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{model.DefaultRoleId}, grants.RoleIds)
+
+	// Looking up grants doesn't store the player
+	count, err := database.Collection(playerCollectionName).CountDocuments(context.Background(), bson.M{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	cleanup()
+}
@@ -3,17 +3,66 @@ package repository
 import (
 	"context"
 	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
 	"permission-service/internal/repository/model"
+	"time"
 )
 
 type Repository interface {
+	// Ping checks that the database can be reached
+	Ping(ctx context.Context) error
+	// SetTimeouts changes the timeouts of operations on a single document and on many documents
+	SetTimeouts(timeout time.Duration, bulkTimeout time.Duration)
+
 	GetAllRoles(ctx context.Context) ([]*model.Role, error)
 	GetRole(ctx context.Context, roleId string) (*model.Role, error)
 	DoesRoleExist(ctx context.Context, roleId string) (bool, error)
 	CreateRole(ctx context.Context, role *model.Role) error
 	UpdateRole(ctx context.Context, newRole *model.Role) error
+	// DeleteRole deletes the role, then removes it from every player and service account holding it
+	DeleteRole(ctx context.Context, roleId string) error
+	// CreateRolesIfMissing creates the roles that don't exist yet, leaving existing roles as they are.
+	// It returns the ids of the roles created.
+	CreateRolesIfMissing(ctx context.Context, roles []*model.Role) (created []string, err error)
+	// SetManagedRoles marks the given roles as managed and every other role as unmanaged
+	SetManagedRoles(ctx context.Context, roleIds []string) error
 
+	// CountPlayers returns the number of players stored, including those only holding the default role
+	CountPlayers(ctx context.Context) (int64, error)
 	GetPlayerRoleIds(ctx context.Context, playerId uuid.UUID) ([]string, error)
 	AddRoleToPlayer(ctx context.Context, playerId uuid.UUID, roleId string) error
 	RemoveRoleFromPlayer(ctx context.Context, playerId uuid.UUID, roleId string) error
+	// AddRoleToPlayers adds the role to every player in a single unordered bulk write, creating players that don't
+	// exist. It isn't atomic: each player is written independently, so it returns the players it changed and the
+	// players that failed, also alongside an error. Players in neither already had the role.
+	// If the whole write failed, every player is returned as failed, as it's unknown which were changed.
+	AddRoleToPlayers(ctx context.Context, playerIds []uuid.UUID, roleId string) (changed []uuid.UUID, failed []uuid.UUID, err error)
+	// RemoveRoleFromPlayers removes the role from every player in a single unordered bulk write. It isn't atomic,
+	// and returns the changed and failed players in the same way as AddRoleToPlayers.
+	RemoveRoleFromPlayers(ctx context.Context, playerIds []uuid.UUID, roleId string) (changed []uuid.UUID, failed []uuid.UUID, err error)
+
+	// GetSubjectGrants returns the roles and direct permissions held by a player or service account.
+	// Players not stored yet hold only the default role, and aren't inserted.
+	GetSubjectGrants(ctx context.Context, subject model.Subject) (*model.SubjectGrants, error)
+
+	GetAllServiceAccounts(ctx context.Context) ([]*model.ServiceAccount, error)
+	GetServiceAccount(ctx context.Context, id string) (*model.ServiceAccount, error)
+	GetServiceAccountByTokenHash(ctx context.Context, tokenHash string) (*model.ServiceAccount, error)
+	CreateServiceAccount(ctx context.Context, account *model.ServiceAccount) error
+	UpdateServiceAccount(ctx context.Context, account *model.ServiceAccount) error
+	DeleteServiceAccount(ctx context.Context, id string) error
+	AddRoleToServiceAccount(ctx context.Context, id string, roleId string) error
+	RemoveRoleFromServiceAccount(ctx context.Context, id string, roleId string) error
+}
+
+// CollectionStore reads and replaces whole collections as raw documents, for backups.
+type CollectionStore interface {
+	// CollectionNames returns the collections of the service's database, sorted by name
+	CollectionNames(ctx context.Context) ([]string, error)
+	CountDocuments(ctx context.Context, collection string) (int64, error)
+	// ExportCollection calls fn with every document of the collection, ordered by id
+	ExportCollection(ctx context.Context, collection string, fn func(doc bson.Raw) error) error
+	// ReplaceCollection replaces every document of the collection with the documents docs yields, keeping its indexes.
+	// The documents are written to a temporary collection first, so the original is left as it was if this fails.
+	ReplaceCollection(ctx context.Context, collection string, docs func(yield func(doc bson.Raw) error) error) error
 }
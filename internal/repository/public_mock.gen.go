@@ -8,9 +8,11 @@ import (
 	context "context"
 	model "permission-service/internal/repository/model"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
+	bson "go.mongodb.org/mongo-driver/bson"
 )
 
 // MockRepository is a mock of Repository interface.
@@ -50,6 +52,51 @@ func (mr *MockRepositoryMockRecorder) AddRoleToPlayer(ctx, playerId, roleId inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRoleToPlayer", reflect.TypeOf((*MockRepository)(nil).AddRoleToPlayer), ctx, playerId, roleId)
 }
 
+// AddRoleToPlayers mocks base method.
+func (m *MockRepository) AddRoleToPlayers(ctx context.Context, playerIds []uuid.UUID, roleId string) ([]uuid.UUID, []uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRoleToPlayers", ctx, playerIds, roleId)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].([]uuid.UUID)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AddRoleToPlayers indicates an expected call of AddRoleToPlayers.
+func (mr *MockRepositoryMockRecorder) AddRoleToPlayers(ctx, playerIds, roleId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRoleToPlayers", reflect.TypeOf((*MockRepository)(nil).AddRoleToPlayers), ctx, playerIds, roleId)
+}
+
+// AddRoleToServiceAccount mocks base method.
+func (m *MockRepository) AddRoleToServiceAccount(ctx context.Context, id, roleId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRoleToServiceAccount", ctx, id, roleId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRoleToServiceAccount indicates an expected call of AddRoleToServiceAccount.
+func (mr *MockRepositoryMockRecorder) AddRoleToServiceAccount(ctx, id, roleId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRoleToServiceAccount", reflect.TypeOf((*MockRepository)(nil).AddRoleToServiceAccount), ctx, id, roleId)
+}
+
+// CountPlayers mocks base method.
+func (m *MockRepository) CountPlayers(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountPlayers", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountPlayers indicates an expected call of CountPlayers.
+func (mr *MockRepositoryMockRecorder) CountPlayers(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPlayers", reflect.TypeOf((*MockRepository)(nil).CountPlayers), ctx)
+}
+
 // CreateRole mocks base method.
 func (m *MockRepository) CreateRole(ctx context.Context, role *model.Role) error {
 	m.ctrl.T.Helper()
@@ -64,6 +111,63 @@ func (mr *MockRepositoryMockRecorder) CreateRole(ctx, role interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRole", reflect.TypeOf((*MockRepository)(nil).CreateRole), ctx, role)
 }
 
+// CreateRolesIfMissing mocks base method.
+func (m *MockRepository) CreateRolesIfMissing(ctx context.Context, roles []*model.Role) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRolesIfMissing", ctx, roles)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRolesIfMissing indicates an expected call of CreateRolesIfMissing.
+func (mr *MockRepositoryMockRecorder) CreateRolesIfMissing(ctx, roles interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRolesIfMissing", reflect.TypeOf((*MockRepository)(nil).CreateRolesIfMissing), ctx, roles)
+}
+
+// CreateServiceAccount mocks base method.
+func (m *MockRepository) CreateServiceAccount(ctx context.Context, account *model.ServiceAccount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateServiceAccount", ctx, account)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateServiceAccount indicates an expected call of CreateServiceAccount.
+func (mr *MockRepositoryMockRecorder) CreateServiceAccount(ctx, account interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateServiceAccount", reflect.TypeOf((*MockRepository)(nil).CreateServiceAccount), ctx, account)
+}
+
+// DeleteRole mocks base method.
+func (m *MockRepository) DeleteRole(ctx context.Context, roleId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRole", ctx, roleId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRole indicates an expected call of DeleteRole.
+func (mr *MockRepositoryMockRecorder) DeleteRole(ctx, roleId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRole", reflect.TypeOf((*MockRepository)(nil).DeleteRole), ctx, roleId)
+}
+
+// DeleteServiceAccount mocks base method.
+func (m *MockRepository) DeleteServiceAccount(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteServiceAccount", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteServiceAccount indicates an expected call of DeleteServiceAccount.
+func (mr *MockRepositoryMockRecorder) DeleteServiceAccount(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServiceAccount", reflect.TypeOf((*MockRepository)(nil).DeleteServiceAccount), ctx, id)
+}
+
 // DoesRoleExist mocks base method.
 func (m *MockRepository) DoesRoleExist(ctx context.Context, roleId string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +198,21 @@ func (mr *MockRepositoryMockRecorder) GetAllRoles(ctx interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllRoles", reflect.TypeOf((*MockRepository)(nil).GetAllRoles), ctx)
 }
 
+// GetAllServiceAccounts mocks base method.
+func (m *MockRepository) GetAllServiceAccounts(ctx context.Context) ([]*model.ServiceAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllServiceAccounts", ctx)
+	ret0, _ := ret[0].([]*model.ServiceAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllServiceAccounts indicates an expected call of GetAllServiceAccounts.
+func (mr *MockRepositoryMockRecorder) GetAllServiceAccounts(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllServiceAccounts", reflect.TypeOf((*MockRepository)(nil).GetAllServiceAccounts), ctx)
+}
+
 // GetPlayerRoleIds mocks base method.
 func (m *MockRepository) GetPlayerRoleIds(ctx context.Context, playerId uuid.UUID) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -124,6 +243,65 @@ func (mr *MockRepositoryMockRecorder) GetRole(ctx, roleId interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRole", reflect.TypeOf((*MockRepository)(nil).GetRole), ctx, roleId)
 }
 
+// GetServiceAccount mocks base method.
+func (m *MockRepository) GetServiceAccount(ctx context.Context, id string) (*model.ServiceAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceAccount", ctx, id)
+	ret0, _ := ret[0].(*model.ServiceAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceAccount indicates an expected call of GetServiceAccount.
+func (mr *MockRepositoryMockRecorder) GetServiceAccount(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceAccount", reflect.TypeOf((*MockRepository)(nil).GetServiceAccount), ctx, id)
+}
+
+// GetServiceAccountByTokenHash mocks base method.
+func (m *MockRepository) GetServiceAccountByTokenHash(ctx context.Context, tokenHash string) (*model.ServiceAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceAccountByTokenHash", ctx, tokenHash)
+	ret0, _ := ret[0].(*model.ServiceAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceAccountByTokenHash indicates an expected call of GetServiceAccountByTokenHash.
+func (mr *MockRepositoryMockRecorder) GetServiceAccountByTokenHash(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceAccountByTokenHash", reflect.TypeOf((*MockRepository)(nil).GetServiceAccountByTokenHash), ctx, tokenHash)
+}
+
+// GetSubjectGrants mocks base method.
+func (m *MockRepository) GetSubjectGrants(ctx context.Context, subject model.Subject) (*model.SubjectGrants, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubjectGrants", ctx, subject)
+	ret0, _ := ret[0].(*model.SubjectGrants)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubjectGrants indicates an expected call of GetSubjectGrants.
+func (mr *MockRepositoryMockRecorder) GetSubjectGrants(ctx, subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubjectGrants", reflect.TypeOf((*MockRepository)(nil).GetSubjectGrants), ctx, subject)
+}
+
+// Ping mocks base method.
+func (m *MockRepository) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockRepositoryMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockRepository)(nil).Ping), ctx)
+}
+
 // RemoveRoleFromPlayer mocks base method.
 func (m *MockRepository) RemoveRoleFromPlayer(ctx context.Context, playerId uuid.UUID, roleId string) error {
 	m.ctrl.T.Helper()
@@ -138,6 +316,62 @@ func (mr *MockRepositoryMockRecorder) RemoveRoleFromPlayer(ctx, playerId, roleId
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRoleFromPlayer", reflect.TypeOf((*MockRepository)(nil).RemoveRoleFromPlayer), ctx, playerId, roleId)
 }
 
+// RemoveRoleFromPlayers mocks base method.
+func (m *MockRepository) RemoveRoleFromPlayers(ctx context.Context, playerIds []uuid.UUID, roleId string) ([]uuid.UUID, []uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveRoleFromPlayers", ctx, playerIds, roleId)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].([]uuid.UUID)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RemoveRoleFromPlayers indicates an expected call of RemoveRoleFromPlayers.
+func (mr *MockRepositoryMockRecorder) RemoveRoleFromPlayers(ctx, playerIds, roleId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRoleFromPlayers", reflect.TypeOf((*MockRepository)(nil).RemoveRoleFromPlayers), ctx, playerIds, roleId)
+}
+
+// RemoveRoleFromServiceAccount mocks base method.
+func (m *MockRepository) RemoveRoleFromServiceAccount(ctx context.Context, id, roleId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveRoleFromServiceAccount", ctx, id, roleId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveRoleFromServiceAccount indicates an expected call of RemoveRoleFromServiceAccount.
+func (mr *MockRepositoryMockRecorder) RemoveRoleFromServiceAccount(ctx, id, roleId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRoleFromServiceAccount", reflect.TypeOf((*MockRepository)(nil).RemoveRoleFromServiceAccount), ctx, id, roleId)
+}
+
+// SetManagedRoles mocks base method.
+func (m *MockRepository) SetManagedRoles(ctx context.Context, roleIds []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetManagedRoles", ctx, roleIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetManagedRoles indicates an expected call of SetManagedRoles.
+func (mr *MockRepositoryMockRecorder) SetManagedRoles(ctx, roleIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetManagedRoles", reflect.TypeOf((*MockRepository)(nil).SetManagedRoles), ctx, roleIds)
+}
+
+// SetTimeouts mocks base method.
+func (m *MockRepository) SetTimeouts(timeout, bulkTimeout time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTimeouts", timeout, bulkTimeout)
+}
+
+// SetTimeouts indicates an expected call of SetTimeouts.
+func (mr *MockRepositoryMockRecorder) SetTimeouts(timeout, bulkTimeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTimeouts", reflect.TypeOf((*MockRepository)(nil).SetTimeouts), timeout, bulkTimeout)
+}
+
 // UpdateRole mocks base method.
 func (m *MockRepository) UpdateRole(ctx context.Context, newRole *model.Role) error {
 	m.ctrl.T.Helper()
@@ -151,3 +385,98 @@ func (mr *MockRepositoryMockRecorder) UpdateRole(ctx, newRole interface{}) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockRepository)(nil).UpdateRole), ctx, newRole)
 }
+
+// UpdateServiceAccount mocks base method.
+func (m *MockRepository) UpdateServiceAccount(ctx context.Context, account *model.ServiceAccount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateServiceAccount", ctx, account)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateServiceAccount indicates an expected call of UpdateServiceAccount.
+func (mr *MockRepositoryMockRecorder) UpdateServiceAccount(ctx, account interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateServiceAccount", reflect.TypeOf((*MockRepository)(nil).UpdateServiceAccount), ctx, account)
+}
+
+// MockCollectionStore is a mock of CollectionStore interface.
+type MockCollectionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockCollectionStoreMockRecorder
+}
+
+// MockCollectionStoreMockRecorder is the mock recorder for MockCollectionStore.
+type MockCollectionStoreMockRecorder struct {
+	mock *MockCollectionStore
+}
+
+// NewMockCollectionStore creates a new mock instance.
+func NewMockCollectionStore(ctrl *gomock.Controller) *MockCollectionStore {
+	mock := &MockCollectionStore{ctrl: ctrl}
+	mock.recorder = &MockCollectionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCollectionStore) EXPECT() *MockCollectionStoreMockRecorder {
+	return m.recorder
+}
+
+// CollectionNames mocks base method.
+func (m *MockCollectionStore) CollectionNames(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectionNames", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CollectionNames indicates an expected call of CollectionNames.
+func (mr *MockCollectionStoreMockRecorder) CollectionNames(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectionNames", reflect.TypeOf((*MockCollectionStore)(nil).CollectionNames), ctx)
+}
+
+// CountDocuments mocks base method.
+func (m *MockCollectionStore) CountDocuments(ctx context.Context, collection string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountDocuments", ctx, collection)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountDocuments indicates an expected call of CountDocuments.
+func (mr *MockCollectionStoreMockRecorder) CountDocuments(ctx, collection interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDocuments", reflect.TypeOf((*MockCollectionStore)(nil).CountDocuments), ctx, collection)
+}
+
+// ExportCollection mocks base method.
+func (m *MockCollectionStore) ExportCollection(ctx context.Context, collection string, fn func(bson.Raw) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportCollection", ctx, collection, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportCollection indicates an expected call of ExportCollection.
+func (mr *MockCollectionStoreMockRecorder) ExportCollection(ctx, collection, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportCollection", reflect.TypeOf((*MockCollectionStore)(nil).ExportCollection), ctx, collection, fn)
+}
+
+// ReplaceCollection mocks base method.
+func (m *MockCollectionStore) ReplaceCollection(ctx context.Context, collection string, docs func(func(bson.Raw) error) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceCollection", ctx, collection, docs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceCollection indicates an expected call of ReplaceCollection.
+func (mr *MockCollectionStoreMockRecorder) ReplaceCollection(ctx, collection, docs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceCollection", reflect.TypeOf((*MockCollectionStore)(nil).ReplaceCollection), ctx, collection, docs)
+}
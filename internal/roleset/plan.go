@@ -0,0 +1,202 @@
+package roleset
+
+import (
+	"context"
+	"fmt"
+	"github.com/emortalmc/proto-specs/gen/go/grpc/permission"
+	"io"
+	"permission-service/internal/repository/model"
+)
+
+type ChangeType string
+
+const (
+	ChangeCreate ChangeType = "create"
+	ChangeUpdate ChangeType = "update"
+)
+
+// Change brings one role in line with its definition.
+type Change struct {
+	Type ChangeType
+	// Current is nil for creates
+	Current *model.Role
+	Desired *model.Role
+	Diff    model.RoleDiff
+}
+
+// Plan is what importing a role set would change. Roles are never deleted by an import.
+type Plan struct {
+	Changes   []Change
+	Unchanged []string
+	// Undeclared roles exist but aren't in the role set, and are left as they are
+	Undeclared []string
+}
+
+// RoleWriter creates and updates roles. Both the PermissionService implementation and its gRPC client satisfy it
+// (the latter through an adapter), so imports are validated, authorized and notified like any other change.
+type RoleWriter interface {
+	CreateRole(ctx context.Context, req *permission.RoleCreateRequest) (*permission.CreateRoleResponse, error)
+	UpdateRole(ctx context.Context, req *permission.RoleUpdateRequest) (*permission.UpdateRoleResponse, error)
+}
+
+// Diff computes the changes needed to make current match desired.
+// A desired role without a display name keeps its current one, as display names can't be removed.
+func Diff(current []*model.Role, desired []*model.Role) *Plan {
+	currentById := make(map[string]*model.Role, len(current))
+	for _, role := range current {
+		currentById[role.Id] = role
+	}
+
+	plan := &Plan{}
+	declared := make(map[string]bool, len(desired))
+
+	for _, role := range desired {
+		declared[role.Id] = true
+
+		existing, ok := currentById[role.Id]
+		if !ok {
+			plan.Changes = append(plan.Changes, Change{Type: ChangeCreate, Desired: role, Diff: model.DiffRoles(nil, role)})
+			continue
+		}
+
+		if role.DisplayName == nil {
+			role = role.Clone()
+			role.DisplayName = existing.DisplayName
+		}
+
+		diff := model.DiffRoles(existing, role)
+		if diff.IsEmpty() {
+			plan.Unchanged = append(plan.Unchanged, role.Id)
+			continue
+		}
+
+		plan.Changes = append(plan.Changes, Change{Type: ChangeUpdate, Current: existing, Desired: role, Diff: diff})
+	}
+
+	for _, role := range current {
+		if !declared[role.Id] {
+			plan.Undeclared = append(plan.Undeclared, role.Id)
+		}
+	}
+
+	return plan
+}
+
+func (p *Plan) IsEmpty() bool {
+	return len(p.Changes) == 0
+}
+
+// Apply performs every change of the plan in order, stopping at the first that fails.
+func (p *Plan) Apply(ctx context.Context, w RoleWriter) error {
+	for _, change := range p.Changes {
+		if err := applyChange(ctx, w, change); err != nil {
+			return fmt.Errorf("failed to %s role %s: %w", change.Type, change.Desired.Id, err)
+		}
+	}
+
+	return nil
+}
+
+func applyChange(ctx context.Context, w RoleWriter, change Change) error {
+	role := change.Desired
+
+	if change.Type == ChangeCreate {
+		_, err := w.CreateRole(ctx, &permission.RoleCreateRequest{Id: role.Id, Priority: role.Priority, DisplayName: role.DisplayName})
+		if err != nil {
+			return err
+		}
+
+		// Roles are always created without nodes, so they're set by an update
+		if len(role.Permissions) == 0 {
+			return nil
+		}
+
+		req := &permission.RoleUpdateRequest{Id: role.Id}
+		for _, perm := range role.Permissions {
+			req.SetPermissions = append(req.SetPermissions, perm.ToProto())
+		}
+
+		_, err = w.UpdateRole(ctx, req)
+		return err
+	}
+
+	_, err := w.UpdateRole(ctx, UpdateRequest(change.Diff, role.Id))
+	return err
+}
+
+// UpdateRequest returns the UpdateRole request applying diff to the role.
+func UpdateRequest(diff model.RoleDiff, roleId string) *permission.RoleUpdateRequest {
+	req := &permission.RoleUpdateRequest{Id: roleId}
+
+	if diff.Priority != nil {
+		req.Priority = &diff.Priority.New
+	}
+	if diff.DisplayName != nil && diff.DisplayName.New != nil {
+		req.DisplayName = diff.DisplayName.New
+	}
+
+	for _, nodes := range [][]model.PermissionNodeDiff{diff.AddedPermissions, diff.ChangedPermissions} {
+		for _, node := range nodes {
+			perm := model.PermissionNode{Node: node.Node, State: *node.NewState}
+			req.SetPermissions = append(req.SetPermissions, perm.ToProto())
+		}
+	}
+	for _, node := range diff.RemovedPermissions {
+		req.UnsetPermissions = append(req.UnsetPermissions, node.Node)
+	}
+
+	return req
+}
+
+// Print writes the plan as a human-readable diff.
+func (p *Plan) Print(w io.Writer) {
+	for _, change := range p.Changes {
+		role := change.Desired
+
+		switch change.Type {
+		case ChangeCreate:
+			fmt.Fprintf(w, "+ role %s\n", role.Id)
+			fmt.Fprintf(w, "    priority: %d\n", role.Priority)
+			if role.DisplayName != nil {
+				fmt.Fprintf(w, "    display name: %q\n", *role.DisplayName)
+			}
+		case ChangeUpdate:
+			fmt.Fprintf(w, "~ role %s\n", role.Id)
+			if change.Diff.Priority != nil {
+				fmt.Fprintf(w, "    priority: %d -> %d\n", change.Diff.Priority.Old, change.Diff.Priority.New)
+			}
+			if d := change.Diff.DisplayName; d != nil {
+				fmt.Fprintf(w, "    display name: %s -> %s\n", quoteOrNone(d.Old), quoteOrNone(d.New))
+			}
+		}
+
+		for _, node := range change.Diff.AddedPermissions {
+			fmt.Fprintf(w, "    + %s %s\n", node.Node, node.NewState)
+		}
+		for _, node := range change.Diff.ChangedPermissions {
+			fmt.Fprintf(w, "    ~ %s %s -> %s\n", node.Node, node.OldState, node.NewState)
+		}
+		for _, node := range change.Diff.RemovedPermissions {
+			fmt.Fprintf(w, "    - %s %s\n", node.Node, node.OldState)
+		}
+	}
+
+	for _, id := range p.Undeclared {
+		fmt.Fprintf(w, "  role %s is not in the role set and will be left as is\n", id)
+	}
+
+	creates := 0
+	for _, change := range p.Changes {
+		if change.Type == ChangeCreate {
+			creates++
+		}
+	}
+	fmt.Fprintf(w, "%d to create, %d to update, %d unchanged\n", creates, len(p.Changes)-creates, len(p.Unchanged))
+}
+
+func quoteOrNone(s *string) string {
+	if s == nil {
+		return "none"
+	}
+	return fmt.Sprintf("%q", *s)
+}
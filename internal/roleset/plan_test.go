@@ -0,0 +1,115 @@
+package roleset
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/emortalmc/proto-specs/gen/go/grpc/permission"
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"permission-service/internal/repository/model"
+	"permission-service/internal/utils"
+	"testing"
+)
+
+type recordingWriter struct {
+	creates []*permission.RoleCreateRequest
+	updates []*permission.RoleUpdateRequest
+
+	failOn string
+}
+
+func (w *recordingWriter) CreateRole(_ context.Context, req *permission.RoleCreateRequest) (*permission.CreateRoleResponse, error) {
+	if req.Id == w.failOn {
+		return nil, errors.New("boom")
+	}
+	w.creates = append(w.creates, req)
+	return &permission.CreateRoleResponse{}, nil
+}
+
+func (w *recordingWriter) UpdateRole(_ context.Context, req *permission.RoleUpdateRequest) (*permission.UpdateRoleResponse, error) {
+	if req.Id == w.failOn {
+		return nil, errors.New("boom")
+	}
+	w.updates = append(w.updates, req)
+	return &permission.UpdateRoleResponse{}, nil
+}
+
+func TestDiff(t *testing.T) {
+	allow, deny := protoModel.PermissionNode_ALLOW, protoModel.PermissionNode_DENY
+
+	current := []*model.Role{
+		{Id: "default", Priority: 0, DisplayName: utils.PointerOf("{{.Username}}")},
+		{Id: "admin", Priority: 90, DisplayName: utils.PointerOf("admin"), Permissions: []model.PermissionNode{
+			{Node: "command.ban", State: allow},
+			{Node: "command.kick", State: allow},
+			{Node: "command.gamemode", State: allow},
+		}},
+		{Id: "legacy", Priority: 10},
+	}
+	desired := []*model.Role{
+		// No display name keeps the current one
+		{Id: "default", Priority: 0},
+		{Id: "admin", Priority: 100, DisplayName: utils.PointerOf("admin"), Permissions: []model.PermissionNode{
+			{Node: "command.ban", State: allow},
+			{Node: "command.gamemode", State: deny},
+			{Node: "command.mute", State: allow},
+		}},
+		{Id: "helper", Priority: 20, Permissions: []model.PermissionNode{{Node: "command.mute", State: allow}}},
+		{Id: "builder", Priority: 15},
+	}
+
+	plan := Diff(current, desired)
+
+	assert.Equal(t, []string{"default"}, plan.Unchanged)
+	assert.Equal(t, []string{"legacy"}, plan.Undeclared)
+	require.Len(t, plan.Changes, 3)
+	assert.Equal(t, ChangeUpdate, plan.Changes[0].Type)
+	assert.Equal(t, ChangeCreate, plan.Changes[1].Type)
+	assert.Equal(t, ChangeCreate, plan.Changes[2].Type)
+
+	out := &bytes.Buffer{}
+	plan.Print(out)
+	assert.Equal(t, `~ role admin
+    priority: 90 -> 100
+    + command.mute ALLOW
+    ~ command.gamemode ALLOW -> DENY
+    - command.kick ALLOW
++ role helper
+    priority: 20
+    + command.mute ALLOW
++ role builder
+    priority: 15
+  role legacy is not in the role set and will be left as is
+2 to create, 1 to update, 1 unchanged
+`, out.String())
+
+	w := &recordingWriter{}
+	require.NoError(t, plan.Apply(context.Background(), w))
+
+	assert.Equal(t, []*permission.RoleCreateRequest{{Id: "helper", Priority: 20}, {Id: "builder", Priority: 15}}, w.creates)
+	assert.Equal(t, []*permission.RoleUpdateRequest{
+		{
+			Id:       "admin",
+			Priority: utils.PointerOf(uint32(100)),
+			SetPermissions: []*protoModel.PermissionNode{
+				{Node: "command.mute", State: allow},
+				{Node: "command.gamemode", State: deny},
+			},
+			UnsetPermissions: []string{"command.kick"},
+		},
+		// Nodes of created roles are set by an update
+		{Id: "helper", SetPermissions: []*protoModel.PermissionNode{{Node: "command.mute", State: allow}}},
+	}, w.updates)
+}
+
+func TestPlan_Apply_StopsOnError(t *testing.T) {
+	plan := Diff(nil, []*model.Role{{Id: "a"}, {Id: "b"}, {Id: "c"}})
+
+	w := &recordingWriter{failOn: "b"}
+	err := plan.Apply(context.Background(), w)
+
+	assert.EqualError(t, err, "failed to create role b: boom")
+	assert.Len(t, w.creates, 1)
+}
@@ -0,0 +1,192 @@
+package roleset
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"path/filepath"
+	"permission-service/internal/repository/model"
+	"sort"
+	"strings"
+)
+
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+// File is a declarative set of roles, as exported and imported.
+type File struct {
+	Roles []*model.Role `json:"roles" yaml:"roles"`
+}
+
+// FormatFromPath returns the format of a file by its extension, defaulting to YAML.
+func FormatFromPath(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return FormatJSON
+	}
+	return FormatYAML
+}
+
+// Marshal writes roles highest priority first, with each role's nodes sorted, so exports diff cleanly in git.
+func Marshal(roles []*model.Role, format string) ([]byte, error) {
+	sorted := make([]*model.Role, len(roles))
+	for i, role := range roles {
+		sorted[i] = role.Clone()
+		if sorted[i].Permissions == nil {
+			sorted[i].Permissions = make([]model.PermissionNode, 0)
+		}
+		sort.Slice(sorted[i].Permissions, func(a, b int) bool {
+			return sorted[i].Permissions[a].Node < sorted[i].Permissions[b].Node
+		})
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority > sorted[j].Priority
+		}
+		return sorted[i].Id < sorted[j].Id
+	})
+
+	file := File{Roles: sorted}
+
+	switch format {
+	case FormatJSON:
+		b, err := json.MarshalIndent(file, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	case FormatYAML:
+		buf := &bytes.Buffer{}
+		enc := yaml.NewEncoder(buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(file); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), enc.Close()
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// Unmarshal reads a role set, rejecting unknown fields and duplicate roles or nodes.
+func Unmarshal(data []byte, format string) ([]*model.Role, error) {
+	var file File
+
+	switch format {
+	case FormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&file); err != nil {
+			return nil, err
+		}
+	case FormatYAML:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		// An empty file is an empty role set
+		if err := dec.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+
+	if err := validate(file.Roles); err != nil {
+		return nil, err
+	}
+
+	return file.Roles, nil
+}
+
+// Load reads the role set at path. If path is a directory, the roles of every YAML and JSON file in it are combined.
+func Load(path string) ([]*model.Role, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return loadFile(path)
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]*model.Role, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || !IsRoleSetFile(entry.Name()) {
+			continue
+		}
+
+		fileRoles, err := loadFile(filepath.Join(path, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, fileRoles...)
+	}
+
+	// Roles are only checked for duplicates within a file when it's parsed
+	if err := validate(roles); err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// IsRoleSetFile reports whether the file name has a YAML or JSON extension. Hidden files are ignored,
+// as editors and Kubernetes ConfigMap mounts keep their own files next to the real ones.
+func IsRoleSetFile(name string) bool {
+	if strings.HasPrefix(name, ".") {
+		return false
+	}
+
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
+
+func loadFile(path string) ([]*model.Role, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := Unmarshal(data, FormatFromPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("invalid role set %s: %w", path, err)
+	}
+
+	return roles, nil
+}
+
+func validate(roles []*model.Role) error {
+	ids := make(map[string]bool, len(roles))
+	for i, role := range roles {
+		if role == nil || role.Id == "" {
+			return fmt.Errorf("role %d has no id", i)
+		}
+		if ids[role.Id] {
+			return fmt.Errorf("role %s is defined more than once", role.Id)
+		}
+		ids[role.Id] = true
+
+		nodes := make(map[string]bool, len(role.Permissions))
+		for _, perm := range role.Permissions {
+			if nodes[perm.Node] {
+				return fmt.Errorf("role %s sets node %s more than once", role.Id, perm.Node)
+			}
+			nodes[perm.Node] = true
+		}
+	}
+
+	return nil
+}
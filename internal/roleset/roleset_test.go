@@ -0,0 +1,114 @@
+package roleset
+
+import (
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"permission-service/internal/repository/model"
+	"permission-service/internal/utils"
+	"testing"
+)
+
+var testRoles = []*model.Role{
+	{Id: "default", Priority: 0, DisplayName: utils.PointerOf("{{.Username}}")},
+	{Id: "admin", Priority: 100, DisplayName: utils.PointerOf("<red>{{.Username}}"), Permissions: []model.PermissionNode{
+		{Node: "command.gamemode", State: protoModel.PermissionNode_DENY},
+		{Node: "command.ban", State: protoModel.PermissionNode_ALLOW},
+	}},
+}
+
+func TestMarshal_YAML(t *testing.T) {
+	data, err := Marshal(testRoles, FormatYAML)
+	require.NoError(t, err)
+
+	// Highest priority first and nodes sorted
+	assert.Equal(t, `roles:
+  - id: admin
+    priority: 100
+    displayName: <red>{{.Username}}
+    permissions:
+      - node: command.ban
+        permissionState: ALLOW
+      - node: command.gamemode
+        permissionState: DENY
+  - id: default
+    priority: 0
+    displayName: '{{.Username}}'
+    permissions: []
+`, string(data))
+
+	roles, err := Unmarshal(data, FormatYAML)
+	require.NoError(t, err)
+	assert.Equal(t, "admin", roles[0].Id)
+	assert.Empty(t, model.DiffRoles(testRoles[1], roles[0]).ChangedFields())
+	assert.Empty(t, model.DiffRoles(testRoles[0], roles[1]).ChangedFields())
+}
+
+func TestMarshal_JSON(t *testing.T) {
+	data, err := Marshal(testRoles[:1], FormatJSON)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"roles": [{"id": "default", "priority": 0, "displayName": "{{.Username}}", "permissions": []}]}`, string(data))
+
+	roles, err := Unmarshal(data, FormatJSON)
+	require.NoError(t, err)
+	assert.Equal(t, []*model.Role{{Id: "default", DisplayName: utils.PointerOf("{{.Username}}"), Permissions: []model.PermissionNode{}}}, roles)
+}
+
+func TestUnmarshal_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   string
+	}{
+		{name: "unknown field", format: FormatYAML, data: "roles:\n  - id: admin\n    weight: 100\n"},
+		{name: "unknown json field", format: FormatJSON, data: `{"roles": [{"id": "admin", "weight": 100}]}`},
+		{name: "invalid state", format: FormatYAML, data: "roles:\n  - id: admin\n    permissions:\n      - node: a\n        permissionState: MAYBE\n"},
+		{name: "missing state", format: FormatJSON, data: `{"roles": [{"id": "admin", "permissions": [{"node": "a"}]}]}`},
+		{name: "missing id", format: FormatYAML, data: "roles:\n  - priority: 10\n"},
+		{name: "duplicate role", format: FormatYAML, data: "roles:\n  - id: admin\n  - id: admin\n"},
+		{name: "duplicate node", format: FormatJSON,
+			data: `{"roles": [{"id": "admin", "permissions": [{"node": "a", "permissionState": "ALLOW"}, {"node": "a", "permissionState": "DENY"}]}]}`},
+		{name: "unknown format", format: "toml", data: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Unmarshal([]byte(tt.data), tt.format)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestUnmarshal_Empty(t *testing.T) {
+	roles, err := Unmarshal(nil, FormatYAML)
+	assert.NoError(t, err)
+	assert.Empty(t, roles)
+}
+
+func TestFormatFromPath(t *testing.T) {
+	assert.Equal(t, FormatJSON, FormatFromPath("roles.JSON"))
+	assert.Equal(t, FormatYAML, FormatFromPath("roles.yml"))
+	assert.Equal(t, FormatYAML, FormatFromPath("roles"))
+}
+
+func TestLoad_Directory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "staff.yaml"), []byte("roles:\n  - id: admin\n    priority: 100\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ranks.json"), []byte(`{"roles": [{"id": "vip", "priority": 10}]}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".staff.yaml.swp"), []byte("not yaml"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# roles"), 0o644))
+
+	roles, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, roles, 2)
+	assert.Equal(t, "vip", roles[0].Id)
+	assert.Equal(t, "admin", roles[1].Id)
+
+	// Roles must be unique across files
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "more.yml"), []byte("roles:\n  - id: admin\n"), 0o644))
+	_, err = Load(dir)
+	assert.ErrorContains(t, err, "admin is defined more than once")
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/emortalmc/proto-specs/gen/go/grpc/permission"
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	mongoDb "go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"permission-service/api/permissionapi"
+	"permission-service/internal/auth"
+	"permission-service/internal/repository"
+	"permission-service/internal/repository/model"
+)
+
+// Permission nodes a subject needs to make each mutation
+const (
+	RoleCreateNode       = "permission.role.create"
+	RoleUpdateNode       = "permission.role.update"
+	RoleDeleteNode       = "permission.role.delete"
+	PlayerRoleAddNode    = "permission.player.role.add"
+	PlayerRoleRemoveNode = "permission.player.role.remove"
+
+	ServiceAccountManageNode     = "permission.serviceaccount.manage"
+	ServiceAccountRoleAddNode    = "permission.serviceaccount.role.add"
+	ServiceAccountRoleRemoveNode = "permission.serviceaccount.role.remove"
+)
+
+// SubjectAuthorizer checks mutations against the roles and nodes of the subjects making them:
+// the authenticated service account, if any, and the acting player or service account named in
+// the x-actor-id metadata if checkActors is set.
+// Calls with neither are denied if checkActors is set, otherwise they're made by a configured identity on its own
+// authority and are left to the auth interceptor.
+type SubjectAuthorizer struct {
+	repo repository.Repository
+
+	checkActors bool
+}
+
+func NewSubjectAuthorizer(repo repository.Repository, checkActors bool) *SubjectAuthorizer {
+	return &SubjectAuthorizer{repo: repo, checkActors: checkActors}
+}
+
+func (a *SubjectAuthorizer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := a.Authorize(ctx, req); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// Authorize returns a PermissionDenied error if any subject making the request may not make it.
+func (a *SubjectAuthorizer) Authorize(ctx context.Context, req any) error {
+	var node string
+	var targetRoleId string
+	var targetAccountId string
+	var newPriority *uint32
+	// allowedNodes are granted by the request, so the subject must already hold them
+	var allowedNodes []string
+
+	switch req := req.(type) {
+	case *permission.RoleCreateRequest:
+		node = RoleCreateNode
+		newPriority = &req.Priority
+	case *permission.RoleUpdateRequest:
+		node = RoleUpdateNode
+		targetRoleId = req.Id
+		newPriority = req.Priority
+		allowedNodes = allowedNodesOf(req.SetPermissions)
+	case *permissionapi.DeleteRoleRequest:
+		node = RoleDeleteNode
+		targetRoleId = req.Id
+	case *permission.AddRoleToPlayerRequest:
+		node = PlayerRoleAddNode
+		targetRoleId = req.RoleId
+	case *permission.RemoveRoleFromPlayerRequest:
+		node = PlayerRoleRemoveNode
+		targetRoleId = req.RoleId
+	case *permissionapi.BulkUpdatePlayerRolesRequest:
+		node = PlayerRoleAddNode
+		if req.ChangeType == permissionapi.BulkUpdatePlayerRolesRequest_REMOVE {
+			node = PlayerRoleRemoveNode
+		}
+		targetRoleId = req.RoleId
+	case *permissionapi.CreateServiceAccountRequest:
+		node = ServiceAccountManageNode
+	case *permissionapi.DeleteServiceAccountRequest:
+		node = ServiceAccountManageNode
+		targetAccountId = req.Id
+	case *permissionapi.RotateServiceAccountTokenRequest:
+		node = ServiceAccountManageNode
+		targetAccountId = req.Id
+	case *permissionapi.UpdateServiceAccountPermissionsRequest:
+		node = ServiceAccountManageNode
+		targetAccountId = req.Id
+		allowedNodes = allowedNodesOf(req.SetPermissions)
+	case *permissionapi.AddRoleToServiceAccountRequest:
+		node = ServiceAccountRoleAddNode
+		targetRoleId = req.RoleId
+	case *permissionapi.RemoveRoleFromServiceAccountRequest:
+		node = ServiceAccountRoleRemoveNode
+		targetRoleId = req.RoleId
+	default:
+		return nil
+	}
+
+	subjects, err := a.subjectsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if len(subjects) == 0 {
+		if a.checkActors {
+			return status.Error(codes.PermissionDenied, fmt.Sprintf("%s needs an actor to check permission %s against", ActorMetadataKey, node))
+		}
+		return nil
+	}
+
+	var target *model.Role
+	if targetRoleId != "" {
+		target, err = a.repo.GetRole(ctx, targetRoleId)
+		if err != nil && err != mongoDb.ErrNoDocuments {
+			return fmt.Errorf("error getting role: %w", err)
+		}
+		// A missing role is left to the handler to return its own not found error
+	}
+
+	var targetAccount *model.ResolvedPermissions
+	if targetAccountId != "" {
+		grants, err := a.repo.GetSubjectGrants(ctx, model.ServiceAccountSubject(targetAccountId))
+		if err != nil && err != mongoDb.ErrNoDocuments {
+			return fmt.Errorf("error getting service account: %w", err)
+		}
+		// As with roles, a missing service account is left to the handler
+		if grants != nil {
+			if targetAccount, err = a.resolveGrants(ctx, grants); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, subject := range subjects {
+		resolved, err := a.resolveSubject(ctx, subject)
+		if err != nil {
+			return err
+		}
+
+		if !resolved.HasPermission(node) {
+			return status.Error(codes.PermissionDenied, fmt.Sprintf("%s is missing permission %s", subject, node))
+		}
+
+		if newPriority != nil && *newPriority >= resolved.HighestPriority {
+			return status.Error(codes.PermissionDenied, "cannot set a role priority equal to or higher than your own")
+		}
+
+		if target != nil && target.Priority >= resolved.HighestPriority {
+			return status.Error(codes.PermissionDenied, "cannot change a role with a priority equal to or higher than your own")
+		}
+
+		if targetAccount != nil && targetAccount.HighestPriority >= resolved.HighestPriority {
+			return status.Error(codes.PermissionDenied, "cannot change a service account with a role equal to or higher than your own")
+		}
+
+		for _, allowed := range allowedNodes {
+			if !resolved.HasPermission(allowed) {
+				return status.Error(codes.PermissionDenied, fmt.Sprintf("%s cannot allow permission %s it doesn't have", subject, allowed))
+			}
+		}
+	}
+
+	return nil
+}
+
+// allowedNodesOf returns the nodes a request sets to ALLOW.
+func allowedNodesOf(perms []*protoModel.PermissionNode) []string {
+	var nodes []string
+	for _, perm := range perms {
+		if perm.State == protoModel.PermissionNode_ALLOW {
+			nodes = append(nodes, perm.Node)
+		}
+	}
+	return nodes
+}
+
+// subjectsFromContext returns every subject whose permissions a request must be checked against.
+func (a *SubjectAuthorizer) subjectsFromContext(ctx context.Context) ([]model.Subject, error) {
+	subjects := make([]model.Subject, 0, 2)
+
+	if identity, ok := auth.IdentityFromContext(ctx); ok && identity.Subject != nil {
+		subjects = append(subjects, *identity.Subject)
+	}
+
+	if a.checkActors {
+		actor, ok, err := actorFromContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			subjects = append(subjects, actor)
+		}
+	}
+
+	return subjects, nil
+}
+
+func (a *SubjectAuthorizer) resolveSubject(ctx context.Context, subject model.Subject) (*model.ResolvedPermissions, error) {
+	grants, err := a.repo.GetSubjectGrants(ctx, subject)
+	if err != nil {
+		if err == mongoDb.ErrNoDocuments {
+			return nil, status.Error(codes.PermissionDenied, fmt.Sprintf("%s does not exist", subject))
+		}
+		return nil, fmt.Errorf("error getting roles of %s: %w", subject, err)
+	}
+
+	return a.resolveGrants(ctx, grants)
+}
+
+func (a *SubjectAuthorizer) resolveGrants(ctx context.Context, grants *model.SubjectGrants) (*model.ResolvedPermissions, error) {
+	allRoles, err := a.repo.GetAllRoles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting all roles: %w", err)
+	}
+
+	roles := make([]*model.Role, 0, len(grants.RoleIds))
+	for _, role := range allRoles {
+		for _, roleId := range grants.RoleIds {
+			if role.Id == roleId {
+				roles = append(roles, role)
+			}
+		}
+	}
+
+	return model.ResolvePermissions(roles, grants.Permissions), nil
+}
+
+// actorFromContext returns the acting player or service account from the incoming metadata, if there is one.
+func actorFromContext(ctx context.Context) (model.Subject, bool, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return model.Subject{}, false, nil
+	}
+
+	values := md.Get(ActorMetadataKey)
+	if len(values) == 0 || values[0] == "" {
+		return model.Subject{}, false, nil
+	}
+
+	actor, err := model.ParseSubject(values[0])
+	if err != nil {
+		return model.Subject{}, false, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid actor id %s", values[0]))
+	}
+
+	return actor, true, nil
+}
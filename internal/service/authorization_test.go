@@ -0,0 +1,409 @@
+package service
+
+import (
+	"context"
+	permService "github.com/emortalmc/proto-specs/gen/go/grpc/permission"
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"permission-service/api/permissionapi"
+	"permission-service/internal/auth"
+	"permission-service/internal/repository"
+	"permission-service/internal/repository/model"
+	"permission-service/internal/utils"
+	"testing"
+)
+
+var authorizationTestRoles = []*model.Role{
+	{Id: "default", Priority: 0},
+	{Id: "helper", Priority: 20},
+	{Id: "moderator", Priority: 50, Permissions: []model.PermissionNode{
+		{Node: "permission.player.role.*", State: protoModel.PermissionNode_ALLOW},
+		{Node: "permission.role.update", State: protoModel.PermissionNode_ALLOW},
+	}},
+	{Id: "senior-moderator", Priority: 60, Permissions: []model.PermissionNode{
+		// Overrides the moderator's grant
+		{Node: "permission.role.update", State: protoModel.PermissionNode_DENY},
+	}},
+	{Id: "account-manager", Priority: 70, Permissions: []model.PermissionNode{
+		{Node: "permission.serviceaccount.manage", State: protoModel.PermissionNode_ALLOW},
+		{Node: "permission.player.role.*", State: protoModel.PermissionNode_ALLOW},
+	}},
+	{Id: "admin", Priority: 100, Permissions: []model.PermissionNode{
+		{Node: "*", State: protoModel.PermissionNode_ALLOW},
+	}},
+}
+
+func findAuthorizationTestRole(id string) *model.Role {
+	for _, role := range authorizationTestRoles {
+		if role.Id == id {
+			return role
+		}
+	}
+	return nil
+}
+
+func TestSubjectAuthorizer_Authorize(t *testing.T) {
+	actorId := uuid.New()
+
+	tests := []struct {
+		name string
+
+		actor      string
+		actorRoles []string
+		req        any
+
+		wantCode codes.Code
+	}{
+		{
+			name:     "no actor is denied",
+			req:      &permService.AddRoleToPlayerRequest{PlayerId: uuid.NewString(), RoleId: "admin"},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "invalid actor id",
+			actor:    "store-service",
+			req:      &permService.AddRoleToPlayerRequest{PlayerId: uuid.NewString(), RoleId: "helper"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "reads are not checked",
+			actor:    actorId.String(),
+			req:      &permService.GetAllRolesRequest{},
+			wantCode: codes.OK,
+		},
+		{
+			name:       "moderator grants lower role by wildcard",
+			actor:      actorId.String(),
+			actorRoles: []string{"default", "moderator"},
+			req:        &permService.AddRoleToPlayerRequest{PlayerId: uuid.NewString(), RoleId: "helper"},
+			wantCode:   codes.OK,
+		},
+		{
+			name:       "moderator removes lower role",
+			actor:      actorId.String(),
+			actorRoles: []string{"default", "moderator"},
+			req:        &permService.RemoveRoleFromPlayerRequest{PlayerId: uuid.NewString(), RoleId: "helper"},
+			wantCode:   codes.OK,
+		},
+		{
+			name:       "moderator cannot grant own role",
+			actor:      actorId.String(),
+			actorRoles: []string{"default", "moderator"},
+			req:        &permService.AddRoleToPlayerRequest{PlayerId: uuid.NewString(), RoleId: "moderator"},
+			wantCode:   codes.PermissionDenied,
+		},
+		{
+			name:       "moderator cannot grant higher role",
+			actor:      actorId.String(),
+			actorRoles: []string{"default", "moderator"},
+			req:        &permService.AddRoleToPlayerRequest{PlayerId: uuid.NewString(), RoleId: "admin"},
+			wantCode:   codes.PermissionDenied,
+		},
+		{
+			name:       "helper missing node",
+			actor:      actorId.String(),
+			actorRoles: []string{"default", "helper"},
+			req:        &permService.AddRoleToPlayerRequest{PlayerId: uuid.NewString(), RoleId: "default"},
+			wantCode:   codes.PermissionDenied,
+		},
+		{
+			name:       "higher priority role denies node",
+			actor:      actorId.String(),
+			actorRoles: []string{"moderator", "senior-moderator"},
+			req:        &permService.RoleUpdateRequest{Id: "helper"},
+			wantCode:   codes.PermissionDenied,
+		},
+		{
+			name:       "moderator updates lower role",
+			actor:      actorId.String(),
+			actorRoles: []string{"moderator"},
+			req:        &permService.RoleUpdateRequest{Id: "helper", Priority: utils.PointerOf(uint32(30))},
+			wantCode:   codes.OK,
+		},
+		{
+			name:       "moderator cannot raise role above themselves",
+			actor:      actorId.String(),
+			actorRoles: []string{"moderator"},
+			req:        &permService.RoleUpdateRequest{Id: "helper", Priority: utils.PointerOf(uint32(50))},
+			wantCode:   codes.PermissionDenied,
+		},
+		{
+			name:       "moderator allows held node on lower role",
+			actor:      actorId.String(),
+			actorRoles: []string{"moderator"},
+			req: &permService.RoleUpdateRequest{Id: "default", SetPermissions: []*protoModel.PermissionNode{
+				{Node: "permission.player.role.add", State: protoModel.PermissionNode_ALLOW},
+			}},
+			wantCode: codes.OK,
+		},
+		{
+			name:       "moderator cannot allow wildcard on own default role",
+			actor:      actorId.String(),
+			actorRoles: []string{"default", "moderator"},
+			req: &permService.RoleUpdateRequest{Id: "default", SetPermissions: []*protoModel.PermissionNode{
+				{Node: "*", State: protoModel.PermissionNode_ALLOW},
+			}},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:       "moderator denies node not held on lower role",
+			actor:      actorId.String(),
+			actorRoles: []string{"moderator"},
+			req: &permService.RoleUpdateRequest{Id: "default", SetPermissions: []*protoModel.PermissionNode{
+				{Node: "permission.role.delete", State: protoModel.PermissionNode_DENY},
+			}},
+			wantCode: codes.OK,
+		},
+		{
+			name:       "admin creates lower role",
+			actor:      actorId.String(),
+			actorRoles: []string{"admin"},
+			req:        &permService.RoleCreateRequest{Id: "new", Priority: 99},
+			wantCode:   codes.OK,
+		},
+		{
+			name:       "admin cannot create equal role",
+			actor:      actorId.String(),
+			actorRoles: []string{"admin"},
+			req:        &permService.RoleCreateRequest{Id: "new", Priority: 100},
+			wantCode:   codes.PermissionDenied,
+		},
+		{
+			name:       "admin deletes lower role",
+			actor:      actorId.String(),
+			actorRoles: []string{"admin"},
+			req:        &permissionapi.DeleteRoleRequest{Id: "moderator"},
+			wantCode:   codes.OK,
+		},
+		{
+			name:       "moderator cannot delete role without node",
+			actor:      actorId.String(),
+			actorRoles: []string{"moderator"},
+			req:        &permissionapi.DeleteRoleRequest{Id: "helper"},
+			wantCode:   codes.PermissionDenied,
+		},
+		{
+			name:       "moderator bulk revokes lower role",
+			actor:      actorId.String(),
+			actorRoles: []string{"default", "moderator"},
+			req: &permissionapi.BulkUpdatePlayerRolesRequest{
+				RoleId: "helper", ChangeType: permissionapi.BulkUpdatePlayerRolesRequest_REMOVE, PlayerIds: []string{uuid.NewString()},
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name:       "moderator cannot bulk grant own role",
+			actor:      actorId.String(),
+			actorRoles: []string{"default", "moderator"},
+			req:        &permissionapi.BulkUpdatePlayerRolesRequest{RoleId: "moderator", PlayerIds: []string{uuid.NewString()}},
+			wantCode:   codes.PermissionDenied,
+		},
+		{
+			name:       "helper cannot bulk grant without node",
+			actor:      actorId.String(),
+			actorRoles: []string{"default", "helper"},
+			req:        &permissionapi.BulkUpdatePlayerRolesRequest{RoleId: "default", PlayerIds: []string{uuid.NewString()}},
+			wantCode:   codes.PermissionDenied,
+		},
+		{
+			name:       "unknown target role is left to the handler",
+			actor:      actorId.String(),
+			actorRoles: []string{"moderator"},
+			req:        &permService.AddRoleToPlayerRequest{PlayerId: uuid.NewString(), RoleId: "unknown"},
+			wantCode:   codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCntrl := gomock.NewController(t)
+			mockRepo := repository.NewMockRepository(mockCntrl)
+
+			mockRepo.EXPECT().GetSubjectGrants(gomock.Any(), model.PlayerSubject(actorId)).Return(&model.SubjectGrants{RoleIds: tt.actorRoles}, nil).AnyTimes()
+			mockRepo.EXPECT().GetAllRoles(gomock.Any()).Return(authorizationTestRoles, nil).AnyTimes()
+			mockRepo.EXPECT().GetRole(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, id string) (*model.Role, error) {
+				if role := findAuthorizationTestRole(id); role != nil {
+					return role, nil
+				}
+				return nil, mongo.ErrNoDocuments
+			}).AnyTimes()
+
+			ctx := context.Background()
+			if tt.actor != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(ActorMetadataKey, tt.actor))
+			}
+
+			err := NewSubjectAuthorizer(mockRepo, true).Authorize(ctx, tt.req)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestSubjectAuthorizer_Authorize_ServiceAccount(t *testing.T) {
+	botSubject := model.ServiceAccountSubject("discord-bot")
+	botGrants := &model.SubjectGrants{
+		RoleIds: []string{"moderator"},
+		Permissions: []model.PermissionNode{
+			// Direct nodes override the moderator role
+			{Node: "permission.player.role.remove", State: protoModel.PermissionNode_DENY},
+		},
+	}
+	actorId := uuid.New()
+
+	tests := []struct {
+		name string
+
+		checkActors bool
+		actor       string
+		req         any
+
+		wantCode codes.Code
+	}{
+		{
+			name:     "service account grants lower role",
+			req:      &permService.AddRoleToPlayerRequest{PlayerId: uuid.NewString(), RoleId: "helper"},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "direct node denies removal",
+			req:      &permService.RemoveRoleFromPlayerRequest{PlayerId: uuid.NewString(), RoleId: "helper"},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "service account cannot manage service accounts",
+			req:      &permissionapi.CreateServiceAccountRequest{Id: "other-bot"},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "actor ignored unless checked",
+			actor:    actorId.String(),
+			req:      &permService.AddRoleToPlayerRequest{PlayerId: uuid.NewString(), RoleId: "helper"},
+			wantCode: codes.OK,
+		},
+		{
+			name:        "acting player must also be allowed",
+			checkActors: true,
+			actor:       actorId.String(),
+			req:         &permService.AddRoleToPlayerRequest{PlayerId: uuid.NewString(), RoleId: "helper"},
+			wantCode:    codes.PermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCntrl := gomock.NewController(t)
+			mockRepo := repository.NewMockRepository(mockCntrl)
+
+			mockRepo.EXPECT().GetSubjectGrants(gomock.Any(), botSubject).Return(botGrants, nil).AnyTimes()
+			mockRepo.EXPECT().GetSubjectGrants(gomock.Any(), model.PlayerSubject(actorId)).Return(&model.SubjectGrants{RoleIds: []string{"default"}}, nil).AnyTimes()
+			mockRepo.EXPECT().GetAllRoles(gomock.Any()).Return(authorizationTestRoles, nil).AnyTimes()
+			mockRepo.EXPECT().GetRole(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, id string) (*model.Role, error) {
+				return findAuthorizationTestRole(id), nil
+			}).AnyTimes()
+
+			ctx := auth.ContextWithIdentity(context.Background(), &auth.Identity{Name: botSubject.String(), Subject: &botSubject})
+			if tt.actor != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(ActorMetadataKey, tt.actor))
+			}
+
+			err := NewSubjectAuthorizer(mockRepo, tt.checkActors).Authorize(ctx, tt.req)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestSubjectAuthorizer_Authorize_NoSubject(t *testing.T) {
+	req := &permService.AddRoleToPlayerRequest{PlayerId: uuid.NewString(), RoleId: "admin"}
+	ctx := auth.ContextWithIdentity(context.Background(), &auth.Identity{Name: "store"})
+
+	// A configured identity acts on its own authority unless player permissions are checked
+	mockRepo := repository.NewMockRepository(gomock.NewController(t))
+	assert.NoError(t, NewSubjectAuthorizer(mockRepo, false).Authorize(ctx, req))
+	assert.Equal(t, codes.PermissionDenied, status.Code(NewSubjectAuthorizer(mockRepo, true).Authorize(ctx, req)))
+}
+
+func TestSubjectAuthorizer_Authorize_ServiceAccountTarget(t *testing.T) {
+	actorId := uuid.New()
+
+	tests := []struct {
+		name string
+
+		req any
+
+		wantCode codes.Code
+	}{
+		{
+			name:     "rotates lower service account token",
+			req:      &permissionapi.RotateServiceAccountTokenRequest{Id: "discord-bot"},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "cannot delete higher service account",
+			req:      &permissionapi.DeleteServiceAccountRequest{Id: "ci"},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "cannot rotate higher service account token",
+			req:      &permissionapi.RotateServiceAccountTokenRequest{Id: "ci"},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "unknown service account is left to the handler",
+			req:      &permissionapi.DeleteServiceAccountRequest{Id: "unknown"},
+			wantCode: codes.OK,
+		},
+		{
+			name: "allows held node",
+			req: &permissionapi.UpdateServiceAccountPermissionsRequest{Id: "discord-bot", SetPermissions: []*protoModel.PermissionNode{
+				{Node: "permission.player.role.add", State: protoModel.PermissionNode_ALLOW},
+			}},
+			wantCode: codes.OK,
+		},
+		{
+			name: "denies node not held without holding it",
+			req: &permissionapi.UpdateServiceAccountPermissionsRequest{Id: "discord-bot", SetPermissions: []*protoModel.PermissionNode{
+				{Node: "permission.role.create", State: protoModel.PermissionNode_DENY},
+			}},
+			wantCode: codes.OK,
+		},
+		{
+			name: "cannot allow node not held",
+			req: &permissionapi.UpdateServiceAccountPermissionsRequest{Id: "discord-bot", SetPermissions: []*protoModel.PermissionNode{
+				{Node: "permission.player.role.add", State: protoModel.PermissionNode_ALLOW},
+				{Node: "permission.role.create", State: protoModel.PermissionNode_ALLOW},
+			}},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "cannot allow wildcard broader than held",
+			req: &permissionapi.UpdateServiceAccountPermissionsRequest{Id: "discord-bot", SetPermissions: []*protoModel.PermissionNode{
+				{Node: "permission.*", State: protoModel.PermissionNode_ALLOW},
+			}},
+			wantCode: codes.PermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCntrl := gomock.NewController(t)
+			mockRepo := repository.NewMockRepository(mockCntrl)
+
+			mockRepo.EXPECT().GetSubjectGrants(gomock.Any(), model.PlayerSubject(actorId)).Return(&model.SubjectGrants{RoleIds: []string{"account-manager"}}, nil).AnyTimes()
+			mockRepo.EXPECT().GetSubjectGrants(gomock.Any(), model.ServiceAccountSubject("discord-bot")).Return(&model.SubjectGrants{RoleIds: []string{"moderator"}}, nil).AnyTimes()
+			mockRepo.EXPECT().GetSubjectGrants(gomock.Any(), model.ServiceAccountSubject("ci")).Return(&model.SubjectGrants{RoleIds: []string{"admin"}}, nil).AnyTimes()
+			mockRepo.EXPECT().GetSubjectGrants(gomock.Any(), model.ServiceAccountSubject("unknown")).Return(nil, mongo.ErrNoDocuments).AnyTimes()
+			mockRepo.EXPECT().GetAllRoles(gomock.Any()).Return(authorizationTestRoles, nil).AnyTimes()
+
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(ActorMetadataKey, actorId.String()))
+
+			err := NewSubjectAuthorizer(mockRepo, true).Authorize(ctx, tt.req)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"go.uber.org/zap"
+	"permission-service/internal/config"
+	"permission-service/internal/repository"
+	"permission-service/internal/repository/model"
+)
+
+// Bootstrap creates the default role and the other roles declared in cfg that don't exist yet.
+// Roles that already exist are left as they are, even if they differ from cfg. cfg is validated when it's loaded.
+func Bootstrap(ctx context.Context, logger *zap.SugaredLogger, cfg config.BootstrapConfig, repo repository.Repository) error {
+	created, err := repo.CreateRolesIfMissing(ctx, bootstrapRoles(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to create bootstrap roles: %w", err)
+	}
+
+	logger.Infow("bootstrapped roles", "defaultRole", cfg.DefaultRole.Id, "created", created)
+	return nil
+}
+
+// bootstrapRoles converts cfg to roles, the default role first.
+func bootstrapRoles(cfg config.BootstrapConfig) []*model.Role {
+	// The default role is the lowest priority role, so any other role's display name is preferred
+	defaultRole := cfg.DefaultRole
+	defaultRole.Priority = 0
+
+	roles := []*model.Role{bootstrapRole(defaultRole)}
+	for _, roleCfg := range cfg.Roles {
+		roles = append(roles, bootstrapRole(roleCfg))
+	}
+	return roles
+}
+
+func bootstrapRole(cfg config.BootstrapRoleConfig) *model.Role {
+	role := &model.Role{Id: cfg.Id, Priority: cfg.Priority, Permissions: make([]model.PermissionNode, 0, len(cfg.Allow)+len(cfg.Deny))}
+	if cfg.DisplayName != "" {
+		displayName := cfg.DisplayName
+		role.DisplayName = &displayName
+	}
+
+	for _, node := range cfg.Allow {
+		role.Permissions = append(role.Permissions, model.PermissionNode{Node: node, State: protoModel.PermissionNode_ALLOW})
+	}
+	for _, node := range cfg.Deny {
+		role.Permissions = append(role.Permissions, model.PermissionNode{Node: node, State: protoModel.PermissionNode_DENY})
+	}
+
+	return role
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"errors"
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"permission-service/internal/config"
+	"permission-service/internal/repository"
+	"permission-service/internal/repository/model"
+	"testing"
+)
+
+func TestBootstrap(t *testing.T) {
+	cfg := config.BootstrapConfig{
+		DefaultRole: config.BootstrapRoleConfig{Id: "member", Priority: 5, DisplayName: "{{.Username}}", Allow: []string{"chat.send"}},
+		Roles: []config.BootstrapRoleConfig{
+			{Id: "admin", Priority: 100, Allow: []string{"*"}, Deny: []string{"command.stop"}},
+		},
+	}
+
+	displayName := "{{.Username}}"
+	want := []*model.Role{
+		{Id: "member", Priority: 0, DisplayName: &displayName, Permissions: []model.PermissionNode{
+			{Node: "chat.send", State: protoModel.PermissionNode_ALLOW},
+		}},
+		{Id: "admin", Priority: 100, Permissions: []model.PermissionNode{
+			{Node: "*", State: protoModel.PermissionNode_ALLOW},
+			{Node: "command.stop", State: protoModel.PermissionNode_DENY},
+		}},
+	}
+
+	mockRepo := repository.NewMockRepository(gomock.NewController(t))
+	mockRepo.EXPECT().CreateRolesIfMissing(gomock.Any(), want).Return([]string{"admin"}, nil)
+
+	assert.NoError(t, Bootstrap(context.Background(), zap.NewNop().Sugar(), cfg, mockRepo))
+}
+
+func TestBootstrap_RepositoryError(t *testing.T) {
+	mockRepo := repository.NewMockRepository(gomock.NewController(t))
+	mockRepo.EXPECT().CreateRolesIfMissing(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused"))
+
+	cfg := config.BootstrapConfig{DefaultRole: config.BootstrapRoleConfig{Id: "default"}}
+	err := Bootstrap(context.Background(), zap.NewNop().Sugar(), cfg, mockRepo)
+	assert.EqualError(t, err, "failed to create bootstrap roles: connection refused")
+}
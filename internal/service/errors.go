@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	mongoDb "go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorUnaryServerInterceptor converts errors that aren't gRPC statuses to the closest status code,
+// so repository errors don't reach callers as Unknown.
+func ErrorUnaryServerInterceptor(logger *zap.SugaredLogger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		res, err := handler(ctx, req)
+		if err != nil {
+			return nil, toStatusError(logger, info.FullMethod, err)
+		}
+
+		return res, nil
+	}
+}
+
+func ErrorStreamServerInterceptor(logger *zap.SugaredLogger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return toStatusError(logger, info.FullMethod, err)
+		}
+
+		return nil
+	}
+}
+
+func toStatusError(logger *zap.SugaredLogger, method string, err error) error {
+	if st, ok := status.FromError(err); ok && st.Code() != codes.Unknown {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "request cancelled")
+	case errors.Is(err, context.DeadlineExceeded), mongoDb.IsTimeout(err):
+		return status.Error(codes.DeadlineExceeded, "request timed out")
+	case errors.Is(err, mongoDb.ErrNoDocuments):
+		return status.Error(codes.NotFound, "not found")
+	case mongoDb.IsDuplicateKeyError(err):
+		return status.Error(codes.AlreadyExists, "already exists")
+	case mongoDb.IsNetworkError(err), errors.Is(err, mongoDb.ErrClientDisconnected):
+		logger.Errorw("database unavailable", "method", method, "error", err)
+		return status.Error(codes.Unavailable, "database unavailable")
+	}
+
+	// The cause is logged rather than returned as it may contain internal details
+	logger.Errorw("internal error", "method", method, "error", err)
+	return status.Error(codes.Internal, "internal error")
+}
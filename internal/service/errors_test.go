@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestToStatusError(t *testing.T) {
+	tests := []struct {
+		name string
+
+		err error
+
+		wantCode codes.Code
+		wantMsg  string
+	}{
+		{name: "status kept", err: status.Error(codes.NotFound, "role not found"), wantCode: codes.NotFound, wantMsg: "role not found"},
+		{name: "deadline", err: fmt.Errorf("error getting role: %w", context.DeadlineExceeded), wantCode: codes.DeadlineExceeded},
+		{name: "cancelled", err: context.Canceled, wantCode: codes.Canceled},
+		{name: "no documents", err: fmt.Errorf("error getting role: %w", mongo.ErrNoDocuments), wantCode: codes.NotFound},
+		{name: "duplicate key", err: mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000}}}, wantCode: codes.AlreadyExists},
+		{name: "disconnected", err: mongo.ErrClientDisconnected, wantCode: codes.Unavailable},
+		{name: "unknown is hidden", err: errors.New("secret connection string"), wantCode: codes.Internal, wantMsg: "internal error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := status.Convert(toStatusError(zap.NewNop().Sugar(), "/test", tt.err))
+			assert.Equal(t, tt.wantCode, st.Code())
+			if tt.wantMsg != "" {
+				assert.Equal(t, tt.wantMsg, st.Message())
+			}
+		})
+	}
+}
@@ -0,0 +1,429 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"github.com/emortalmc/proto-specs/gen/go/grpc/permission"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"net"
+	"net/http"
+	"permission-service/api/permissionapi"
+	"permission-service/internal/config"
+	"permission-service/internal/tracing"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	permissionServicePrefix = "/emortal.grpc.permission.PermissionService/"
+
+	// maxGatewayBodySize is far above any valid request, validation limits node and display name lengths
+	maxGatewayBodySize = 1 << 20
+)
+
+// gatewayHeaders are the HTTP headers passed to the interceptors and service as gRPC metadata
+var gatewayHeaders = []string{"authorization", ActorMetadataKey, ReasonMetadataKey}
+
+// gateway exposes the PermissionService, RoleService and ServiceAccountService as JSON over HTTP. Every request
+// goes through the same interceptors as gRPC calls, so authentication, authorization and error mapping are identical.
+type gateway struct {
+	svc         permission.PermissionServiceServer
+	roles       permissionapi.RoleServiceServer
+	accounts    permissionapi.ServiceAccountServiceServer
+	interceptor grpc.UnaryServerInterceptor
+
+	mux       *runtime.ServeMux
+	marshaler runtime.Marshaler
+}
+
+func newGatewayHandler(svc permission.PermissionServiceServer, roles permissionapi.RoleServiceServer,
+	accounts permissionapi.ServiceAccountServiceServer, interceptors []grpc.UnaryServerInterceptor,
+	corsOrigins []string) (http.Handler, error) {
+
+	marshaler := &runtime.JSONPb{
+		MarshalOptions: protojson.MarshalOptions{EmitUnpopulated: true},
+		// Unknown fields are rejected rather than silently ignored, so typos in a request are noticed
+		UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: false},
+	}
+
+	g := &gateway{
+		svc:         svc,
+		roles:       roles,
+		accounts:    accounts,
+		interceptor: chainUnaryInterceptors(interceptors),
+		mux: runtime.NewServeMux(
+			runtime.WithMarshalerOption(runtime.MIMEWildcard, marshaler),
+			runtime.WithRoutingErrorHandler(routingErrorHandler),
+		),
+		marshaler: marshaler,
+	}
+
+	routes := []struct {
+		method  string
+		pattern string
+		handler runtime.HandlerFunc
+	}{
+		{http.MethodGet, "/v1/roles", g.getAllRoles},
+		{http.MethodPost, "/v1/roles", g.createRole},
+		{http.MethodGet, "/v1/roles/{id}", g.getRole},
+		{http.MethodPatch, "/v1/roles/{id}", g.updateRole},
+		{http.MethodDelete, "/v1/roles/{id}", g.deleteRole},
+		{http.MethodPost, "/v1/roles/{role_id}/players", g.bulkUpdatePlayerRoles},
+		{http.MethodGet, "/v1/players/{player_id}/roles", g.getPlayerRoles},
+		{http.MethodPut, "/v1/players/{player_id}/roles/{role_id}", g.addRoleToPlayer},
+		{http.MethodDelete, "/v1/players/{player_id}/roles/{role_id}", g.removeRoleFromPlayer},
+		{http.MethodGet, "/v1/service-accounts", g.getServiceAccounts},
+		{http.MethodPost, "/v1/service-accounts", g.createServiceAccount},
+		{http.MethodDelete, "/v1/service-accounts/{id}", g.deleteServiceAccount},
+		{http.MethodPost, "/v1/service-accounts/{id}/token", g.rotateServiceAccountToken},
+		{http.MethodPatch, "/v1/service-accounts/{id}/permissions", g.updateServiceAccountPermissions},
+		{http.MethodPut, "/v1/service-accounts/{id}/roles/{role_id}", g.addRoleToServiceAccount},
+		{http.MethodDelete, "/v1/service-accounts/{id}/roles/{role_id}", g.removeRoleFromServiceAccount},
+	}
+
+	for _, route := range routes {
+		if err := g.mux.HandlePath(route.method, route.pattern, route.handler); err != nil {
+			return nil, fmt.Errorf("failed to register %s %s: %w", route.method, route.pattern, err)
+		}
+	}
+
+	if len(corsOrigins) > 0 {
+		return cors(g.mux, corsOrigins), nil
+	}
+	return g.mux, nil
+}
+
+// runGateway serves the gateway until ctx is done. TLS is used if tlsConfig is not nil.
+func runGateway(ctx context.Context, wg *sync.WaitGroup, logger *zap.SugaredLogger, cfg config.GatewayConfig,
+	tlsConfig *tls.Config, handler http.Handler) {
+
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Port),
+		Handler:           handler,
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		logger.Infow("listening for HTTP requests", "port", cfg.Port)
+
+		var err error
+		if tlsConfig != nil {
+			// The certificates are already loaded into tlsConfig
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatalw("failed to serve HTTP", "error", err)
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logger.Errorw("failed to shut down HTTP gateway", "error", err)
+		}
+	}()
+}
+
+func (g *gateway) getAllRoles(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	g.invoke(w, r, g.svc, permissionServicePrefix+"GetAllRoles", &permission.GetAllRolesRequest{}, http.StatusOK, func(ctx context.Context, req any) (any, error) {
+		return g.svc.GetAllRoles(ctx, req.(*permission.GetAllRolesRequest))
+	})
+}
+
+// getRole has no RPC of its own, so it's authorized as GetAllRoles and filters the result.
+func (g *gateway) getRole(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	g.invoke(w, r, g.svc, permissionServicePrefix+"GetAllRoles", &permission.GetAllRolesRequest{}, http.StatusOK, func(ctx context.Context, req any) (any, error) {
+		res, err := g.svc.GetAllRoles(ctx, req.(*permission.GetAllRolesRequest))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, role := range res.Roles {
+			if role.Id == params["id"] {
+				return role, nil
+			}
+		}
+		return nil, status.Error(codes.NotFound, "role not found")
+	})
+}
+
+func (g *gateway) createRole(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	req := &permission.RoleCreateRequest{}
+	if !g.decodeBody(w, r, req) {
+		return
+	}
+
+	g.invoke(w, r, g.svc, permissionServicePrefix+"CreateRole", req, http.StatusCreated, func(ctx context.Context, req any) (any, error) {
+		return g.svc.CreateRole(ctx, req.(*permission.RoleCreateRequest))
+	})
+}
+
+func (g *gateway) updateRole(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	req := &permission.RoleUpdateRequest{}
+	if !g.decodeBody(w, r, req) {
+		return
+	}
+	req.Id = params["id"]
+
+	g.invoke(w, r, g.svc, permissionServicePrefix+"UpdateRole", req, http.StatusOK, func(ctx context.Context, req any) (any, error) {
+		return g.svc.UpdateRole(ctx, req.(*permission.RoleUpdateRequest))
+	})
+}
+
+func (g *gateway) deleteRole(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	req := &permissionapi.DeleteRoleRequest{Id: params["id"]}
+
+	g.invoke(w, r, g.roles, permissionapi.RoleService_DeleteRole_FullMethodName, req, http.StatusOK, func(ctx context.Context, req any) (any, error) {
+		return g.roles.DeleteRole(ctx, req.(*permissionapi.DeleteRoleRequest))
+	})
+}
+
+func (g *gateway) bulkUpdatePlayerRoles(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	req := &permissionapi.BulkUpdatePlayerRolesRequest{}
+	if !g.decodeBody(w, r, req) {
+		return
+	}
+	req.RoleId = params["role_id"]
+
+	g.invoke(w, r, g.roles, permissionapi.RoleService_BulkUpdatePlayerRoles_FullMethodName, req, http.StatusOK, func(ctx context.Context, req any) (any, error) {
+		return g.roles.BulkUpdatePlayerRoles(ctx, req.(*permissionapi.BulkUpdatePlayerRolesRequest))
+	})
+}
+
+func (g *gateway) getPlayerRoles(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	req := &permission.GetPlayerRolesRequest{PlayerId: params["player_id"]}
+
+	g.invoke(w, r, g.svc, permissionServicePrefix+"GetPlayerRoles", req, http.StatusOK, func(ctx context.Context, req any) (any, error) {
+		return g.svc.GetPlayerRoles(ctx, req.(*permission.GetPlayerRolesRequest))
+	})
+}
+
+func (g *gateway) addRoleToPlayer(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	req := &permission.AddRoleToPlayerRequest{PlayerId: params["player_id"], RoleId: params["role_id"]}
+
+	g.invoke(w, r, g.svc, permissionServicePrefix+"AddRoleToPlayer", req, http.StatusOK, func(ctx context.Context, req any) (any, error) {
+		return g.svc.AddRoleToPlayer(ctx, req.(*permission.AddRoleToPlayerRequest))
+	})
+}
+
+func (g *gateway) removeRoleFromPlayer(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	req := &permission.RemoveRoleFromPlayerRequest{PlayerId: params["player_id"], RoleId: params["role_id"]}
+
+	g.invoke(w, r, g.svc, permissionServicePrefix+"RemoveRoleFromPlayer", req, http.StatusOK, func(ctx context.Context, req any) (any, error) {
+		return g.svc.RemoveRoleFromPlayer(ctx, req.(*permission.RemoveRoleFromPlayerRequest))
+	})
+}
+
+func (g *gateway) getServiceAccounts(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	req := &permissionapi.GetServiceAccountsRequest{}
+
+	g.invoke(w, r, g.accounts, permissionapi.ServiceAccountService_GetServiceAccounts_FullMethodName, req, http.StatusOK, func(ctx context.Context, req any) (any, error) {
+		return g.accounts.GetServiceAccounts(ctx, req.(*permissionapi.GetServiceAccountsRequest))
+	})
+}
+
+func (g *gateway) createServiceAccount(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	req := &permissionapi.CreateServiceAccountRequest{}
+	if !g.decodeBody(w, r, req) {
+		return
+	}
+
+	g.invoke(w, r, g.accounts, permissionapi.ServiceAccountService_CreateServiceAccount_FullMethodName, req, http.StatusCreated, func(ctx context.Context, req any) (any, error) {
+		return g.accounts.CreateServiceAccount(ctx, req.(*permissionapi.CreateServiceAccountRequest))
+	})
+}
+
+func (g *gateway) deleteServiceAccount(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	req := &permissionapi.DeleteServiceAccountRequest{Id: params["id"]}
+
+	g.invoke(w, r, g.accounts, permissionapi.ServiceAccountService_DeleteServiceAccount_FullMethodName, req, http.StatusOK, func(ctx context.Context, req any) (any, error) {
+		return g.accounts.DeleteServiceAccount(ctx, req.(*permissionapi.DeleteServiceAccountRequest))
+	})
+}
+
+func (g *gateway) rotateServiceAccountToken(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	req := &permissionapi.RotateServiceAccountTokenRequest{Id: params["id"]}
+
+	g.invoke(w, r, g.accounts, permissionapi.ServiceAccountService_RotateServiceAccountToken_FullMethodName, req, http.StatusOK, func(ctx context.Context, req any) (any, error) {
+		return g.accounts.RotateServiceAccountToken(ctx, req.(*permissionapi.RotateServiceAccountTokenRequest))
+	})
+}
+
+func (g *gateway) updateServiceAccountPermissions(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	req := &permissionapi.UpdateServiceAccountPermissionsRequest{}
+	if !g.decodeBody(w, r, req) {
+		return
+	}
+	req.Id = params["id"]
+
+	g.invoke(w, r, g.accounts, permissionapi.ServiceAccountService_UpdateServiceAccountPermissions_FullMethodName, req, http.StatusOK, func(ctx context.Context, req any) (any, error) {
+		return g.accounts.UpdateServiceAccountPermissions(ctx, req.(*permissionapi.UpdateServiceAccountPermissionsRequest))
+	})
+}
+
+func (g *gateway) addRoleToServiceAccount(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	req := &permissionapi.AddRoleToServiceAccountRequest{Id: params["id"], RoleId: params["role_id"]}
+
+	g.invoke(w, r, g.accounts, permissionapi.ServiceAccountService_AddRoleToServiceAccount_FullMethodName, req, http.StatusOK, func(ctx context.Context, req any) (any, error) {
+		return g.accounts.AddRoleToServiceAccount(ctx, req.(*permissionapi.AddRoleToServiceAccountRequest))
+	})
+}
+
+func (g *gateway) removeRoleFromServiceAccount(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	req := &permissionapi.RemoveRoleFromServiceAccountRequest{Id: params["id"], RoleId: params["role_id"]}
+
+	g.invoke(w, r, g.accounts, permissionapi.ServiceAccountService_RemoveRoleFromServiceAccount_FullMethodName, req, http.StatusOK, func(ctx context.Context, req any) (any, error) {
+		return g.accounts.RemoveRoleFromServiceAccount(ctx, req.(*permissionapi.RemoveRoleFromServiceAccountRequest))
+	})
+}
+
+// invoke calls handler through the interceptors as if the request was made to fullMethod of server over gRPC,
+// and writes the response or error as JSON.
+func (g *gateway) invoke(w http.ResponseWriter, r *http.Request, server any, fullMethod string, req proto.Message,
+	successStatus int, handler grpc.UnaryHandler) {
+
+	ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	ctx, span := tracing.Tracer().Start(ctx, fullMethod, trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	ctx = gatewayContext(ctx, r)
+
+	res, err := g.interceptor(ctx, req, &grpc.UnaryServerInfo{Server: server, FullMethod: fullMethod}, handler)
+	if err != nil {
+		runtime.HTTPError(ctx, g.mux, g.marshaler, w, r, err)
+		return
+	}
+
+	body, err := g.marshaler.Marshal(res)
+	if err != nil {
+		runtime.HTTPError(ctx, g.mux, g.marshaler, w, r, status.Error(codes.Internal, "failed to marshal response"))
+		return
+	}
+
+	w.Header().Set("Content-Type", g.marshaler.ContentType(res))
+	w.WriteHeader(successStatus)
+	_, _ = w.Write(body)
+}
+
+// routingErrorHandler replies 405 rather than 501 when a route exists but not for the request's method.
+func routingErrorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler,
+	w http.ResponseWriter, r *http.Request, httpStatus int) {
+
+	if httpStatus == http.StatusMethodNotAllowed {
+		err := &runtime.HTTPStatusError{HTTPStatus: httpStatus, Err: status.Error(codes.Unimplemented, "method not allowed")}
+		runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, err)
+		return
+	}
+
+	runtime.DefaultRoutingErrorHandler(ctx, mux, marshaler, w, r, httpStatus)
+}
+
+// decodeBody unmarshals the JSON body of r into req, writing an InvalidArgument error if it's malformed.
+func (g *gateway) decodeBody(w http.ResponseWriter, r *http.Request, req proto.Message) bool {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxGatewayBodySize))
+	if err == nil && len(body) > 0 {
+		err = g.marshaler.Unmarshal(body, req)
+	}
+	if err != nil {
+		runtime.HTTPError(r.Context(), g.mux, g.marshaler, w, r, status.Errorf(codes.InvalidArgument, "invalid request body: %v", err))
+		return false
+	}
+
+	return true
+}
+
+// gatewayContext makes the HTTP request look like a gRPC call to the interceptors: the auth and change
+// headers become incoming metadata, and a verified client certificate becomes the peer's TLS info.
+func gatewayContext(ctx context.Context, r *http.Request) context.Context {
+	md := metadata.MD{}
+	for _, key := range gatewayHeaders {
+		if values := r.Header.Values(key); len(values) > 0 {
+			md.Set(key, values...)
+		}
+	}
+	ctx = metadata.NewIncomingContext(ctx, md)
+
+	p := &peer.Peer{Addr: gatewayAddr(r.RemoteAddr)}
+	if r.TLS != nil {
+		p.AuthInfo = credentials.TLSInfo{State: *r.TLS}
+	}
+
+	return peer.NewContext(ctx, p)
+}
+
+func gatewayAddr(remoteAddr string) net.Addr {
+	addr, err := net.ResolveTCPAddr("tcp", remoteAddr)
+	if err != nil {
+		return &net.TCPAddr{}
+	}
+
+	return addr
+}
+
+// chainUnaryInterceptors combines interceptors the same way grpc.ChainUnaryInterceptor does, first is outermost.
+func chainUnaryInterceptors(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(ctx context.Context, req any) (any, error) {
+				return interceptor(ctx, req, info, inner)
+			}
+		}
+
+		return next(ctx, req)
+	}
+}
+
+// cors allows browser apps served from origins to call the gateway. Credentials are only allowed for listed
+// origins, "*" lets any origin call the gateway without them.
+func cors(next http.Handler, origins []string) http.Handler {
+	allowAll := slices.Contains(origins, "*")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin != "" && (allowAll || slices.Contains(origins, origin)) {
+			if allowAll {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+				w.Header().Add("Vary", "Origin")
+			}
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE")
+				w.Header().Set("Access-Control-Allow-Headers", strings.Join(append([]string{"Content-Type"}, gatewayHeaders...), ", "))
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
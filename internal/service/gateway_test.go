@@ -0,0 +1,375 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"net/http"
+	"net/http/httptest"
+	"permission-service/internal/auth"
+	"permission-service/internal/config"
+	"permission-service/internal/messaging/notifier"
+	"permission-service/internal/repository"
+	"permission-service/internal/repository/model"
+	"strings"
+	"testing"
+)
+
+func TestGateway(t *testing.T) {
+	playerId := uuid.New()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		header http.Header
+
+		setup func(repo *repository.MockRepository, notif *notifier.MockNotifier)
+
+		wantStatus int
+		// wantBody is a subset of the JSON response fields
+		wantBody map[string]any
+	}{
+		{
+			name:   "get all roles",
+			method: http.MethodGet,
+			path:   "/v1/roles",
+			setup: func(repo *repository.MockRepository, _ *notifier.MockNotifier) {
+				repo.EXPECT().GetAllRoles(gomock.Any()).Return([]*model.Role{{Id: "default"}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "get role",
+			method: http.MethodGet,
+			path:   "/v1/roles/admin",
+			setup: func(repo *repository.MockRepository, _ *notifier.MockNotifier) {
+				repo.EXPECT().GetAllRoles(gomock.Any()).Return([]*model.Role{{Id: "default"}, {Id: "admin", Priority: 100}}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   map[string]any{"id": "admin", "priority": float64(100)},
+		},
+		{
+			name:   "get missing role",
+			method: http.MethodGet,
+			path:   "/v1/roles/admin",
+			setup: func(repo *repository.MockRepository, _ *notifier.MockNotifier) {
+				repo.EXPECT().GetAllRoles(gomock.Any()).Return([]*model.Role{{Id: "default"}}, nil)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   map[string]any{"message": "role not found"},
+		},
+		{
+			name:   "create role",
+			method: http.MethodPost,
+			path:   "/v1/roles",
+			body:   `{"id": "helper", "priority": 20}`,
+			header: http.Header{"X-Actor-Id": {"admin-panel"}, "X-Change-Reason": {"new staff"}},
+			setup: func(repo *repository.MockRepository, notif *notifier.MockNotifier) {
+				repo.EXPECT().CreateRole(gomock.Any(), gomock.Any()).Return(nil)
+				notif.EXPECT().RoleUpdate(gomock.Any(), gomock.Nil(), gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ *model.Role, _ *model.Role, _ any, meta notifier.ChangeMeta) error {
+						assert.Equal(t, "admin-panel", meta.OnBehalfOf)
+						assert.Equal(t, "new staff", meta.Reason)
+						return nil
+					})
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   map[string]any{"role": map[string]any{"id": "helper", "priority": float64(20), "permissions": []any{}}},
+		},
+		{
+			name:       "create role with malformed body",
+			method:     http.MethodPost,
+			path:       "/v1/roles",
+			body:       `{"id": `,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "create role with unknown field",
+			method:     http.MethodPost,
+			path:       "/v1/roles",
+			body:       `{"id": "helper", "colour": "red"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "create invalid role",
+			method:     http.MethodPost,
+			path:       "/v1/roles",
+			body:       `{"id": ""}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "update role uses path id",
+			method: http.MethodPatch,
+			path:   "/v1/roles/helper",
+			body:   `{"id": "admin", "priority": 30}`,
+			setup: func(repo *repository.MockRepository, notif *notifier.MockNotifier) {
+				repo.EXPECT().GetRole(gomock.Any(), "helper").Return(&model.Role{Id: "helper", Priority: 20}, nil)
+				repo.EXPECT().UpdateRole(gomock.Any(), gomock.Any()).Return(nil)
+				notif.EXPECT().RoleUpdate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   map[string]any{"role": map[string]any{"id": "helper", "priority": float64(30), "permissions": []any{}}},
+		},
+		{
+			name:   "get player roles",
+			method: http.MethodGet,
+			path:   "/v1/players/" + playerId.String() + "/roles",
+			setup: func(repo *repository.MockRepository, _ *notifier.MockNotifier) {
+				repo.EXPECT().GetPlayerRoleIds(gomock.Any(), playerId).Return([]string{"default"}, nil)
+				repo.EXPECT().GetAllRoles(gomock.Any()).Return([]*model.Role{{Id: "default"}}, nil).AnyTimes()
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   map[string]any{"roleIds": []any{"default"}},
+		},
+		{
+			name:       "get roles of invalid player",
+			method:     http.MethodGet,
+			path:       "/v1/players/notauuid/roles",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "add role to player",
+			method: http.MethodPut,
+			path:   "/v1/players/" + playerId.String() + "/roles/helper",
+			setup: func(repo *repository.MockRepository, notif *notifier.MockNotifier) {
+				repo.EXPECT().DoesRoleExist(gomock.Any(), "helper").Return(true, nil)
+				repo.EXPECT().AddRoleToPlayer(gomock.Any(), playerId, "helper").Return(nil)
+				notif.EXPECT().PlayerRolesUpdate(gomock.Any(), playerId.String(), "helper", gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "add missing role to player",
+			method: http.MethodPut,
+			path:   "/v1/players/" + playerId.String() + "/roles/helper",
+			setup: func(repo *repository.MockRepository, _ *notifier.MockNotifier) {
+				repo.EXPECT().DoesRoleExist(gomock.Any(), "helper").Return(false, nil)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "add role player already has",
+			method: http.MethodPut,
+			path:   "/v1/players/" + playerId.String() + "/roles/helper",
+			setup: func(repo *repository.MockRepository, _ *notifier.MockNotifier) {
+				repo.EXPECT().DoesRoleExist(gomock.Any(), "helper").Return(true, nil)
+				repo.EXPECT().AddRoleToPlayer(gomock.Any(), playerId, "helper").Return(repository.AlreadyHasRoleError)
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:   "remove role from player",
+			method: http.MethodDelete,
+			path:   "/v1/players/" + playerId.String() + "/roles/helper",
+			setup: func(repo *repository.MockRepository, notif *notifier.MockNotifier) {
+				repo.EXPECT().RemoveRoleFromPlayer(gomock.Any(), playerId, "helper").Return(nil)
+				notif.EXPECT().PlayerRolesUpdate(gomock.Any(), playerId.String(), "helper", gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "delete role",
+			method: http.MethodDelete,
+			path:   "/v1/roles/helper",
+			setup: func(repo *repository.MockRepository, notif *notifier.MockNotifier) {
+				repo.EXPECT().GetRole(gomock.Any(), "helper").Return(&model.Role{Id: "helper"}, nil)
+				repo.EXPECT().DeleteRole(gomock.Any(), "helper").Return(nil)
+				notif.EXPECT().RoleUpdate(gomock.Any(), gomock.Nil(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "bulk add role to players",
+			method: http.MethodPost,
+			path:   "/v1/roles/helper/players",
+			body:   `{"changeType": "ADD", "playerIds": ["` + playerId.String() + `"]}`,
+			setup: func(repo *repository.MockRepository, notif *notifier.MockNotifier) {
+				repo.EXPECT().DoesRoleExist(gomock.Any(), "helper").Return(true, nil)
+				repo.EXPECT().AddRoleToPlayers(gomock.Any(), []uuid.UUID{playerId}, "helper").Return([]uuid.UUID{playerId}, nil, nil)
+				notif.EXPECT().PlayerRolesUpdates(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: map[string]any{"results": []any{
+				map[string]any{"playerId": playerId.String(), "status": "UPDATED"},
+			}},
+		},
+		{
+			name:   "get service accounts",
+			method: http.MethodGet,
+			path:   "/v1/service-accounts",
+			setup: func(repo *repository.MockRepository, _ *notifier.MockNotifier) {
+				repo.EXPECT().GetAllServiceAccounts(gomock.Any()).Return([]*model.ServiceAccount{{Id: "discord-bot"}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "add role to missing service account",
+			method: http.MethodPut,
+			path:   "/v1/service-accounts/discord-bot/roles/helper",
+			setup: func(repo *repository.MockRepository, _ *notifier.MockNotifier) {
+				repo.EXPECT().DoesRoleExist(gomock.Any(), "helper").Return(true, nil)
+				repo.EXPECT().AddRoleToServiceAccount(gomock.Any(), "discord-bot", "helper").Return(mongo.ErrNoDocuments)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "unknown route",
+			method:     http.MethodPut,
+			path:       "/v1/roles/helper",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			repo := repository.NewMockRepository(mockCtrl)
+			notif := notifier.NewMockNotifier(mockCtrl)
+			if test.setup != nil {
+				test.setup(repo, notif)
+			}
+
+			svc := NewPermissionService(zap.NewNop().Sugar(), repo, notif)
+			roleSvc := NewRoleService(zap.NewNop().Sugar(), repo, notif, model.DefaultRoleId)
+			saSvc := NewServiceAccountService(zap.NewNop().Sugar(), repo)
+			handler, err := newGatewayHandler(svc, roleSvc, saSvc, []grpc.UnaryServerInterceptor{ErrorUnaryServerInterceptor(zap.NewNop().Sugar())}, nil)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+			for key, values := range test.header {
+				req.Header[key] = values
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.wantStatus, rec.Code, rec.Body.String())
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+			if test.wantBody != nil {
+				var body map[string]any
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+				for key, value := range test.wantBody {
+					assert.Equal(t, value, body[key], key)
+				}
+			}
+		})
+	}
+}
+
+func TestGateway_Auth(t *testing.T) {
+	authenticator, err := auth.NewAuthenticator(config.AuthConfig{
+		Enabled: true,
+		Identities: []config.IdentityConfig{
+			{Name: "reader", Token: "reader-token", Methods: []string{"GetAllRoles"}},
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		wantStatus int
+	}{
+		{name: "no token", method: http.MethodGet, path: "/v1/roles", wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", method: http.MethodGet, path: "/v1/roles", token: "nope", wantStatus: http.StatusUnauthorized},
+		{name: "allowed method", method: http.MethodGet, path: "/v1/roles", token: "reader-token", wantStatus: http.StatusOK},
+		{name: "denied method", method: http.MethodPut, path: "/v1/players/" + uuid.NewString() + "/roles/admin",
+			token: "reader-token", wantStatus: http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			repo := repository.NewMockRepository(mockCtrl)
+			repo.EXPECT().GetAllRoles(gomock.Any()).Return(nil, nil).AnyTimes()
+
+			svc := NewPermissionService(zap.NewNop().Sugar(), repo, notifier.NewMockNotifier(mockCtrl))
+			handler, err := newGatewayHandler(svc, nil, nil, []grpc.UnaryServerInterceptor{authenticator.UnaryServerInterceptor()}, nil)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(test.method, test.path, nil)
+			if test.token != "" {
+				req.Header.Set("Authorization", "Bearer "+test.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.wantStatus, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestGateway_CORS(t *testing.T) {
+	svc := NewPermissionService(zap.NewNop().Sugar(), nil, nil)
+	handler, err := newGatewayHandler(svc, nil, nil, nil, []string{"https://admin.example.com"})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodOptions, "/v1/roles", nil)
+	req.Header.Set("Origin", "https://admin.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "https://admin.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", rec.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Contains(t, rec.Header().Get("Access-Control-Allow-Headers"), "authorization")
+
+	// Other origins get no CORS headers, so browsers block the response
+	req = httptest.NewRequest(http.MethodOptions, "/v1/roles", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestGateway_CORS_AnyOrigin(t *testing.T) {
+	svc := NewPermissionService(zap.NewNop().Sugar(), nil, nil)
+	handler, err := newGatewayHandler(svc, nil, nil, nil, []string{"*"})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodOptions, "/v1/roles", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	// The origin isn't echoed, so browsers never send credentials to any origin
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func TestChainUnaryInterceptors(t *testing.T) {
+	var calls []string
+	interceptor := func(name string) grpc.UnaryServerInterceptor {
+		return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			calls = append(calls, name+":"+info.FullMethod)
+			return handler(ctx, req)
+		}
+	}
+
+	chained := chainUnaryInterceptors([]grpc.UnaryServerInterceptor{interceptor("first"), interceptor("second")})
+	res, err := chained(context.Background(), "req", &grpc.UnaryServerInfo{FullMethod: "/test"}, func(ctx context.Context, req any) (any, error) {
+		calls = append(calls, "handler")
+		return "res", nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "res", res)
+	assert.Equal(t, []string{"first:/test", "second:/test", "handler"}, calls)
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"sync"
+	"time"
+)
+
+const (
+	healthCheckInterval = 10 * time.Second
+	healthCheckTimeout  = 5 * time.Second
+)
+
+// HealthCheck checks a single dependency. Its status is reported under Name,
+// and the overall ("") status is NOT_SERVING while a Required check fails.
+type HealthCheck struct {
+	Name     string
+	Required bool
+	Check    func(ctx context.Context) error
+}
+
+// NewHealthServer runs checks periodically until ctx is done. The caller should call Shutdown on the
+// returned server when it starts shutting down, so probes stop routing to this replica.
+func NewHealthServer(ctx context.Context, wg *sync.WaitGroup, logger *zap.SugaredLogger, checks ...HealthCheck) *health.Server {
+	s := health.NewServer()
+	// Nothing has been checked yet
+	s.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(healthCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			runHealthChecks(ctx, logger, s, checks)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return s
+}
+
+func runHealthChecks(ctx context.Context, logger *zap.SugaredLogger, s *health.Server, checks []HealthCheck) {
+	overall := healthpb.HealthCheckResponse_SERVING
+
+	for _, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		err := check.Check(checkCtx)
+		cancel()
+
+		// The context being done means we're shutting down, not that the dependency is down
+		if ctx.Err() != nil {
+			return
+		}
+
+		status := healthpb.HealthCheckResponse_SERVING
+		if err != nil {
+			logger.Warnw("health check failed", "check", check.Name, "error", err)
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+			if check.Required {
+				overall = healthpb.HealthCheckResponse_NOT_SERVING
+			}
+		}
+
+		s.SetServingStatus(check.Name, status)
+	}
+
+	s.SetServingStatus("", overall)
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"testing"
+)
+
+func TestRunHealthChecks(t *testing.T) {
+	healthy := func(context.Context) error { return nil }
+	unhealthy := func(context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name string
+
+		checks []HealthCheck
+
+		wantOverall healthpb.HealthCheckResponse_ServingStatus
+		wantChecks  map[string]healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{
+			name:        "all healthy",
+			checks:      []HealthCheck{{Name: "mongodb", Required: true, Check: healthy}, {Name: "kafka", Check: healthy}},
+			wantOverall: healthpb.HealthCheckResponse_SERVING,
+			wantChecks: map[string]healthpb.HealthCheckResponse_ServingStatus{
+				"mongodb": healthpb.HealthCheckResponse_SERVING,
+				"kafka":   healthpb.HealthCheckResponse_SERVING,
+			},
+		},
+		{
+			name:        "required check failing",
+			checks:      []HealthCheck{{Name: "mongodb", Required: true, Check: unhealthy}, {Name: "kafka", Check: healthy}},
+			wantOverall: healthpb.HealthCheckResponse_NOT_SERVING,
+			wantChecks: map[string]healthpb.HealthCheckResponse_ServingStatus{
+				"mongodb": healthpb.HealthCheckResponse_NOT_SERVING,
+				"kafka":   healthpb.HealthCheckResponse_SERVING,
+			},
+		},
+		{
+			name:        "optional check failing",
+			checks:      []HealthCheck{{Name: "mongodb", Required: true, Check: healthy}, {Name: "kafka", Check: unhealthy}},
+			wantOverall: healthpb.HealthCheckResponse_SERVING,
+			wantChecks: map[string]healthpb.HealthCheckResponse_ServingStatus{
+				"mongodb": healthpb.HealthCheckResponse_SERVING,
+				"kafka":   healthpb.HealthCheckResponse_NOT_SERVING,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := health.NewServer()
+			runHealthChecks(context.Background(), zap.NewNop().Sugar(), s, tt.checks)
+
+			assert.Equal(t, tt.wantOverall, checkStatus(t, s, ""))
+			for name, want := range tt.wantChecks {
+				assert.Equal(t, want, checkStatus(t, s, name))
+			}
+		})
+	}
+}
+
+func TestRunHealthChecks_Shutdown(t *testing.T) {
+	s := health.NewServer()
+	runHealthChecks(context.Background(), zap.NewNop().Sugar(), s, []HealthCheck{{Name: "mongodb", Required: true, Check: func(context.Context) error { return nil }}})
+
+	s.Shutdown()
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, checkStatus(t, s, ""))
+
+	// Checks that pass after shutdown must not flip the status back
+	runHealthChecks(context.Background(), zap.NewNop().Sugar(), s, []HealthCheck{{Name: "mongodb", Required: true, Check: func(context.Context) error { return nil }}})
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, checkStatus(t, s, ""))
+}
+
+func checkStatus(t *testing.T, s *health.Server, service string) healthpb.HealthCheckResponse_ServingStatus {
+	res, err := s.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	assert.NoError(t, err)
+	return res.GetStatus()
+}
@@ -10,11 +10,22 @@ import (
 	mongoDb "go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
-	"permission-service/internal/kafka/notifier"
+	"permission-service/internal/auth"
+	"permission-service/internal/messaging/notifier"
 	"permission-service/internal/repository"
 	"permission-service/internal/repository/model"
+	"permission-service/internal/validation"
 	"sort"
+	"time"
+)
+
+const (
+	// ActorMetadataKey is the gRPC metadata key identifying who the caller is making a change for, e.g. a player
+	ActorMetadataKey = "x-actor-id"
+	// ReasonMetadataKey is the gRPC metadata key for a free-form reason for a change
+	ReasonMetadataKey = "x-change-reason"
 )
 
 type permissionService struct {
@@ -26,7 +37,7 @@ type permissionService struct {
 	notif notifier.Notifier
 }
 
-func newPermissionService(logger *zap.SugaredLogger, repo repository.Repository, notif notifier.Notifier) permission.PermissionServiceServer {
+func NewPermissionService(logger *zap.SugaredLogger, repo repository.Repository, notif notifier.Notifier) permission.PermissionServiceServer {
 	return &permissionService{
 		logger: logger,
 
@@ -56,10 +67,11 @@ func (s *permissionService) GetAllRoles(ctx context.Context, _ *permission.GetAl
 }
 
 func (s *permissionService) GetPlayerRoles(ctx context.Context, req *permission.GetPlayerRolesRequest) (*permission.PlayerRolesResponse, error) {
-	pId, err := uuid.Parse(req.PlayerId)
-	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid player id %s", req.PlayerId))
+	if err := validation.GetPlayerRolesRequest(req); err != nil {
+		return nil, err
 	}
+
+	pId := uuid.MustParse(req.PlayerId)
 	roles, err := s.repo.GetPlayerRoleIds(ctx, pId)
 	if err != nil {
 		return nil, err
@@ -82,6 +94,10 @@ func (s *permissionService) GetPlayerRoles(ctx context.Context, req *permission.
 }
 
 func (s *permissionService) CreateRole(ctx context.Context, req *permission.RoleCreateRequest) (*permission.CreateRoleResponse, error) {
+	if err := validation.RoleCreateRequest(req); err != nil {
+		return nil, err
+	}
+
 	role := &model.Role{
 		Id:          req.Id,
 		Priority:    req.Priority,
@@ -98,7 +114,7 @@ func (s *permissionService) CreateRole(ctx context.Context, req *permission.Role
 		return nil, fmt.Errorf("error creating role: %w", err)
 	}
 
-	if err := s.notif.RoleUpdate(ctx, role, permission2.RoleUpdateMessage_CREATE); err != nil {
+	if err := s.notif.RoleUpdate(ctx, nil, role, permission2.RoleUpdateMessage_CREATE, changeMetaFromContext(ctx)); err != nil {
 		s.logger.Errorw("error sending role update notification", "error", err)
 	}
 
@@ -108,6 +124,10 @@ func (s *permissionService) CreateRole(ctx context.Context, req *permission.Role
 }
 
 func (s *permissionService) UpdateRole(ctx context.Context, req *permission.RoleUpdateRequest) (*permission.UpdateRoleResponse, error) {
+	if err := validation.RoleUpdateRequest(req); err != nil {
+		return nil, err
+	}
+
 	role, err := s.repo.GetRole(ctx, req.Id)
 
 	if err != nil {
@@ -116,6 +136,10 @@ func (s *permissionService) UpdateRole(ctx context.Context, req *permission.Role
 		}
 		return nil, fmt.Errorf("error getting role: %w", err)
 	}
+	if role.Managed && !isManagedRoleWrite(ctx) {
+		return nil, errManagedRole(role.Id)
+	}
+	previous := role.Clone()
 
 	if req.Priority != nil {
 		role.Priority = *req.Priority
@@ -153,7 +177,7 @@ func (s *permissionService) UpdateRole(ctx context.Context, req *permission.Role
 		return nil, fmt.Errorf("error updating role: %w", err)
 	}
 
-	if err := s.notif.RoleUpdate(ctx, role, permission2.RoleUpdateMessage_MODIFY); err != nil {
+	if err := s.notif.RoleUpdate(ctx, previous, role, permission2.RoleUpdateMessage_MODIFY, changeMetaFromContext(ctx)); err != nil {
 		s.logger.Errorw("error sending role update notification", "error", err)
 	}
 
@@ -163,11 +187,12 @@ func (s *permissionService) UpdateRole(ctx context.Context, req *permission.Role
 }
 
 func (s *permissionService) AddRoleToPlayer(ctx context.Context, req *permission.AddRoleToPlayerRequest) (*permission.AddRoleToPlayerResponse, error) {
-	pId, err := uuid.Parse(req.PlayerId)
-	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid player id %s", req.PlayerId))
+	if err := validation.AddRoleToPlayerRequest(req); err != nil {
+		return nil, err
 	}
 
+	pId := uuid.MustParse(req.PlayerId)
+
 	ok, err := s.repo.DoesRoleExist(ctx, req.RoleId)
 	if err != nil {
 		return nil, err
@@ -191,7 +216,7 @@ func (s *permissionService) AddRoleToPlayer(ctx context.Context, req *permission
 		return nil, err
 	}
 
-	if err := s.notif.PlayerRolesUpdate(ctx, pId.String(), req.RoleId, permission2.PlayerRolesUpdateMessage_ADD); err != nil {
+	if err := s.notif.PlayerRolesUpdate(ctx, pId.String(), req.RoleId, permission2.PlayerRolesUpdateMessage_ADD, changeMetaFromContext(ctx)); err != nil {
 		s.logger.Errorw("error sending player roles update", "error", err)
 	}
 
@@ -207,12 +232,12 @@ var (
 )
 
 func (s *permissionService) RemoveRoleFromPlayer(ctx context.Context, req *permission.RemoveRoleFromPlayerRequest) (*permission.RemoveRoleFromPlayerResponse, error) {
-	pId, err := uuid.Parse(req.PlayerId)
-	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid player id %s", req.PlayerId))
+	if err := validation.RemoveRoleFromPlayerRequest(req); err != nil {
+		return nil, err
 	}
 
-	err = s.repo.RemoveRoleFromPlayer(ctx, pId, req.RoleId)
+	pId := uuid.MustParse(req.PlayerId)
+	err := s.repo.RemoveRoleFromPlayer(ctx, pId, req.RoleId)
 	if err != nil {
 		if err == mongoDb.ErrNoDocuments {
 			return nil, removeRoleFromPlayerPlayerNotFound
@@ -222,7 +247,7 @@ func (s *permissionService) RemoveRoleFromPlayer(ctx context.Context, req *permi
 		return nil, err
 	}
 
-	if err := s.notif.PlayerRolesUpdate(ctx, pId.String(), req.RoleId, permission2.PlayerRolesUpdateMessage_REMOVE); err != nil {
+	if err := s.notif.PlayerRolesUpdate(ctx, pId.String(), req.RoleId, permission2.PlayerRolesUpdateMessage_REMOVE, changeMetaFromContext(ctx)); err != nil {
 		s.logger.Errorw("error sending player roles update", "error", err)
 	}
 
@@ -259,6 +284,32 @@ func (s *permissionService) computeActiveDisplayNameRole(ctx context.Context, ro
 	return nil, nil
 }
 
+// changeMetaFromContext attributes a change to the authenticated caller. Who the caller says it's acting for
+// and why are read from the incoming gRPC metadata, which the caller controls.
+func changeMetaFromContext(ctx context.Context) notifier.ChangeMeta {
+	meta := notifier.ChangeMeta{Timestamp: time.Now()}
+
+	if identity, ok := auth.IdentityFromContext(ctx); ok {
+		meta.Actor = identity.Name
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(ActorMetadataKey); len(values) > 0 {
+			meta.OnBehalfOf = values[0]
+		}
+		if values := md.Get(ReasonMetadataKey); len(values) > 0 {
+			meta.Reason = values[0]
+		}
+	}
+
+	return meta
+}
+
+// errManagedRole rejects manual changes to a role declared in the role sync files, which would be reverted.
+func errManagedRole(roleId string) error {
+	return status.Errorf(codes.FailedPrecondition, "role %s is managed by role sync and must be changed in the role sync files", roleId)
+}
+
 func panicIfErr[T any](thing T, err error) T {
 	if err != nil {
 		panic(err)
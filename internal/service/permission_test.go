@@ -10,16 +10,16 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/mongo"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
-	"permission-service/internal/kafka/notifier"
+	"permission-service/internal/auth"
+	"permission-service/internal/messaging/notifier"
 	"permission-service/internal/repository"
 	"permission-service/internal/repository/model"
 	"permission-service/internal/utils"
 	"testing"
 )
 
-// TODO: Have the service sanity check incoming requests for missing fields
-
 func TestPermissionService_GetAllRoles(t *testing.T) {
 	mockCntrl := gomock.NewController(t)
 	mockRepo := repository.NewMockRepository(mockCntrl)
@@ -146,7 +146,7 @@ func TestPermissionService_CreateRole(t *testing.T) {
 
 	// Test successful role creation
 	mockRepo.EXPECT().CreateRole(context.Background(), role).Return(nil)
-	mockNotifier.EXPECT().RoleUpdate(context.Background(), role, permission.RoleUpdateMessage_CREATE).Return(nil)
+	mockNotifier.EXPECT().RoleUpdate(context.Background(), gomock.Nil(), role, permission.RoleUpdateMessage_CREATE, gomock.Any()).Return(nil)
 
 	svc := permissionService{
 		repo:  mockRepo,
@@ -165,6 +165,19 @@ func TestPermissionService_CreateRole(t *testing.T) {
 	})
 }
 
+// Test with an invalid role, which never reaches the repository
+func TestPermissionService_CreateRole_Invalid(t *testing.T) {
+	mockCntrl := gomock.NewController(t)
+	mockRepo := repository.NewMockRepository(mockCntrl)
+
+	svc := permissionService{
+		repo: mockRepo,
+	}
+
+	_, err := svc.CreateRole(context.Background(), &permService.RoleCreateRequest{Id: "Not Valid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
 // Test with partial role
 func TestPermissionService_CreateRole2(t *testing.T) {
 	mockCntrl := gomock.NewController(t)
@@ -175,7 +188,7 @@ func TestPermissionService_CreateRole2(t *testing.T) {
 	role.Permissions = make([]model.PermissionNode, 0)
 
 	mockRepo.EXPECT().CreateRole(context.Background(), role).Return(nil)
-	mockNotifier.EXPECT().RoleUpdate(context.Background(), role, permission.RoleUpdateMessage_CREATE).Return(nil)
+	mockNotifier.EXPECT().RoleUpdate(context.Background(), gomock.Nil(), role, permission.RoleUpdateMessage_CREATE, gomock.Any()).Return(nil)
 
 	svc := permissionService{
 		repo:  mockRepo,
@@ -255,10 +268,9 @@ var updateRoleTests = map[string]updateRoleTest{
 		updateRoleErr: nil,
 
 		mockReq: &permService.RoleUpdateRequest{
-			Id:               createGenericRole().Id,
-			Priority:         utils.PointerOf(uint32(10000)),
-			DisplayName:      utils.PointerOf("new display name"),
-			UnsetPermissions: []string{""},
+			Id:          createGenericRole().Id,
+			Priority:    utils.PointerOf(uint32(10000)),
+			DisplayName: utils.PointerOf("new display name"),
 		},
 
 		expectedUpdatedDbRole: &model.Role{
@@ -373,8 +385,7 @@ var updateRoleTests = map[string]updateRoleTest{
 		expectedUpdatedDbRole: nil,
 
 		mockReq: &permService.RoleUpdateRequest{
-			Id:               "test-role",
-			UnsetPermissions: []string{""},
+			Id: "test-role",
 		},
 
 		expectedErr: func(t *testing.T, err error) bool {
@@ -382,6 +393,19 @@ var updateRoleTests = map[string]updateRoleTest{
 		},
 		expectedRes: nil,
 	},
+	"managed_role": {
+		dbRole: &model.Role{Id: "test-role", Managed: true},
+
+		mockReq: &permService.RoleUpdateRequest{
+			Id:       "test-role",
+			Priority: utils.PointerOf(uint32(10)),
+		},
+
+		expectedErr: func(t *testing.T, err error) bool {
+			return status.Code(err) == codes.FailedPrecondition
+		},
+		expectedRes: nil,
+	},
 }
 
 // TODO: Note errors are probably because of notifier mocks right now
@@ -397,14 +421,16 @@ func TestPermissionService_UpdateRole(t *testing.T) {
 				notif: mockNotifier,
 			}
 
+			var previousRole *model.Role
 			if test.dbRole != nil {
+				previousRole = test.dbRole.Clone()
 				mockRepo.EXPECT().GetRole(context.Background(), test.dbRole.Id).Return(test.dbRole, test.getRoleErr)
 			}
 			if test.expectedUpdatedDbRole != nil {
 				mockRepo.EXPECT().UpdateRole(context.Background(), test.expectedUpdatedDbRole).Return(test.updateRoleErr)
 			}
 			if test.notifChangeType != nil {
-				mockNotifier.EXPECT().RoleUpdate(context.Background(), test.expectedUpdatedDbRole, *test.notifChangeType).Return(nil)
+				mockNotifier.EXPECT().RoleUpdate(context.Background(), previousRole, test.expectedUpdatedDbRole, *test.notifChangeType, gomock.Any()).Return(nil)
 			}
 
 			response, err := svc.UpdateRole(context.Background(), test.mockReq)
@@ -482,7 +508,7 @@ func TestPermissionService_AddRoleToPlayer(t *testing.T) {
 				mockRepo.EXPECT().AddRoleToPlayer(context.Background(), playerId, roleId).Return(test.addRoleErr)
 
 				if test.addRoleErr == nil {
-					mockNotifier.EXPECT().PlayerRolesUpdate(context.Background(), playerIdStr, roleId, permission.PlayerRolesUpdateMessage_ADD).Return(nil)
+					mockNotifier.EXPECT().PlayerRolesUpdate(context.Background(), playerIdStr, roleId, permission.PlayerRolesUpdateMessage_ADD, gomock.Any()).Return(nil)
 				}
 			}
 
@@ -556,7 +582,7 @@ func TestPermissionService_RemoveRoleFromPlayer(t *testing.T) {
 			mockRepo.EXPECT().RemoveRoleFromPlayer(context.Background(), playerId, roleId).Return(test.removeRoleErr)
 
 			if test.removeRoleErr == nil {
-				mockNotifier.EXPECT().PlayerRolesUpdate(context.Background(), playerIdStr, roleId, permission.PlayerRolesUpdateMessage_REMOVE).Return(nil)
+				mockNotifier.EXPECT().PlayerRolesUpdate(context.Background(), playerIdStr, roleId, permission.PlayerRolesUpdateMessage_REMOVE, gomock.Any()).Return(nil)
 			}
 
 			_, err := svc.RemoveRoleFromPlayer(context.Background(), &permService.RemoveRoleFromPlayerRequest{
@@ -654,3 +680,18 @@ func createPartialGenericRole() *model.Role {
 		},
 	}
 }
+
+func TestChangeMetaFromContext(t *testing.T) {
+	md := metadata.Pairs(ActorMetadataKey, "admin", ReasonMetadataKey, "promotion")
+
+	// The claimed actor never replaces the authenticated identity
+	ctx := auth.ContextWithIdentity(metadata.NewIncomingContext(context.Background(), md), &auth.Identity{Name: "store"})
+	meta := changeMetaFromContext(ctx)
+	assert.Equal(t, "store", meta.Actor)
+	assert.Equal(t, "admin", meta.OnBehalfOf)
+	assert.Equal(t, "promotion", meta.Reason)
+
+	meta = changeMetaFromContext(metadata.NewIncomingContext(context.Background(), md))
+	assert.Empty(t, meta.Actor)
+	assert.Equal(t, "admin", meta.OnBehalfOf)
+}
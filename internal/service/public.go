@@ -2,22 +2,34 @@ package service
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"github.com/emortalmc/proto-specs/gen/go/grpc/permission"
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 	"net"
+	"net/http"
+	"os"
+	"permission-service/api/permissionapi"
+	"permission-service/internal/admin"
+	"permission-service/internal/auth"
 	"permission-service/internal/config"
-	"permission-service/internal/kafka/notifier"
+	"permission-service/internal/metrics"
 	"permission-service/internal/repository"
 	"permission-service/internal/utils/grpczap"
 	"sync"
 )
 
 func RunServices(ctx context.Context, logger *zap.SugaredLogger, wg *sync.WaitGroup, cfg config.Config,
-	repo repository.Repository, notif notifier.Notifier) {
+	repo repository.Repository, svc permission.PermissionServiceServer, roleSvc permissionapi.RoleServiceServer,
+	saSvc permissionapi.ServiceAccountServiceServer,
+	watchSvc permissionapi.PermissionWatchServiceServer, healthChecks ...HealthCheck) {
 
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
 	if err != nil {
@@ -28,15 +40,64 @@ func RunServices(ctx context.Context, logger *zap.SugaredLogger, wg *sync.WaitGr
 		logging.WithLogOnEvents(logging.StartCall, logging.FinishCall),
 	}
 
-	s := grpc.NewServer(grpc.ChainUnaryInterceptor(
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		metrics.UnaryServerInterceptor(),
 		logging.UnaryServerInterceptor(grpczap.InterceptorLogger(logger.Desugar()), opts...),
-	))
+		ErrorUnaryServerInterceptor(logger),
+	}
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		metrics.StreamServerInterceptor(),
+		logging.StreamServerInterceptor(grpczap.InterceptorLogger(logger.Desugar()), opts...),
+		ErrorStreamServerInterceptor(logger),
+	}
+
+	if cfg.Auth.Enabled {
+		authenticator, err := auth.NewAuthenticator(cfg.Auth)
+		if err != nil {
+			logger.Fatalw("failed to create authenticator", "error", err)
+		}
+		authenticator.WithServiceAccounts(repo)
+
+		unaryInterceptors = append(unaryInterceptors, authenticator.UnaryServerInterceptor())
+		streamInterceptors = append(streamInterceptors, authenticator.StreamServerInterceptor())
+	} else if !cfg.Development {
+		logger.Warn("authentication is disabled, anyone who can reach the gRPC port can modify permissions")
+	}
+
+	// Service accounts are always limited by their own roles, acting players only if configured
+	if cfg.Auth.Enabled {
+		unaryInterceptors = append(unaryInterceptors, NewSubjectAuthorizer(repo, cfg.Auth.PlayerPermissions).UnaryServerInterceptor())
+	}
+
+	serverOpts := []grpc.ServerOption{
+		// Starts the server span before any interceptor runs, continuing the caller's trace
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	}
+
+	var tlsConfig *tls.Config
+	if cfg.TLS.CertFile != "" {
+		tlsConfig, err = createTLSConfig(cfg.TLS)
+		if err != nil {
+			logger.Fatalw("failed to create TLS config", "error", err)
+		}
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsConfig.Clone())))
+	}
+
+	s := grpc.NewServer(serverOpts...)
 
 	if cfg.Development {
 		reflection.Register(s)
 	}
 
-	permission.RegisterPermissionServiceServer(s, newPermissionService(logger, repo, notif))
+	permission.RegisterPermissionServiceServer(s, svc)
+	permissionapi.RegisterRoleServiceServer(s, roleSvc)
+	permissionapi.RegisterServiceAccountServiceServer(s, saSvc)
+	permissionapi.RegisterPermissionWatchServiceServer(s, watchSvc)
+
+	healthSrv := NewHealthServer(ctx, wg, logger, healthChecks...)
+	healthpb.RegisterHealthServer(s, healthSrv)
 	logger.Infow("listening for gRPC requests", "port", cfg.GRPCPort)
 
 	go func() {
@@ -49,7 +110,54 @@ func RunServices(ctx context.Context, logger *zap.SugaredLogger, wg *sync.WaitGr
 	go func() {
 		defer wg.Done()
 		<-ctx.Done()
+		// Report NOT_SERVING to probes while in-flight requests finish
+		healthSrv.Shutdown()
 		s.GracefulStop()
 	}()
 
+	if cfg.Gateway.Port != 0 {
+		handler, err := newGatewayHandler(svc, roleSvc, saSvc, unaryInterceptors, cfg.Gateway.CORSOrigins)
+		if err != nil {
+			logger.Fatalw("failed to create HTTP gateway", "error", err)
+		}
+
+		if cfg.Gateway.AdminUI {
+			mux := http.NewServeMux()
+			mux.Handle(admin.Path, admin.Handler())
+			mux.Handle("/", handler)
+			handler = mux
+		}
+
+		runGateway(ctx, wg, logger, cfg.Gateway, tlsConfig, handler)
+	}
+}
+
+func createTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certificate: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if cfg.ClientCAFile != "" {
+		caPem, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.ClientCAFile)
+		}
+
+		tlsConfig.ClientCAs = pool
+		// Clients may still authenticate with a bearer token instead of a certificate
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	return tlsConfig, nil
 }
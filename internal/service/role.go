@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	permission2 "github.com/emortalmc/proto-specs/gen/go/message/permission"
+	"github.com/google/uuid"
+	mongoDb "go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"permission-service/api/permissionapi"
+	"permission-service/internal/messaging/notifier"
+	"permission-service/internal/repository"
+	"permission-service/internal/validation"
+)
+
+type roleService struct {
+	permissionapi.UnimplementedRoleServiceServer
+
+	logger *zap.SugaredLogger
+
+	repo  repository.Repository
+	notif notifier.Notifier
+
+	defaultRoleId string
+}
+
+func NewRoleService(logger *zap.SugaredLogger, repo repository.Repository, notif notifier.Notifier, defaultRoleId string) permissionapi.RoleServiceServer {
+	return &roleService{
+		logger: logger,
+
+		repo:  repo,
+		notif: notif,
+
+		defaultRoleId: defaultRoleId,
+	}
+}
+
+func (s *roleService) DeleteRole(ctx context.Context, req *permissionapi.DeleteRoleRequest) (*permissionapi.DeleteRoleResponse, error) {
+	if err := validation.DeleteRoleRequest(req); err != nil {
+		return nil, err
+	}
+
+	// Every player is given the default role, so it must always exist
+	if req.Id == s.defaultRoleId {
+		return nil, status.Error(codes.FailedPrecondition, "the default role cannot be deleted")
+	}
+
+	role, err := s.repo.GetRole(ctx, req.Id)
+	if err != nil {
+		if err == mongoDb.ErrNoDocuments {
+			return nil, status.Error(codes.NotFound, "role not found")
+		}
+		return nil, fmt.Errorf("error getting role: %w", err)
+	}
+	// It would be recreated by the next reconcile
+	if role.Managed {
+		return nil, errManagedRole(role.Id)
+	}
+
+	if err := s.repo.DeleteRole(ctx, req.Id); err != nil {
+		if err == mongoDb.ErrNoDocuments {
+			return nil, status.Error(codes.NotFound, "role not found")
+		}
+		return nil, fmt.Errorf("error deleting role: %w", err)
+	}
+
+	if err := s.notif.RoleUpdate(ctx, nil, role, permission2.RoleUpdateMessage_DELETE, changeMetaFromContext(ctx)); err != nil {
+		s.logger.Errorw("error sending role update notification", "error", err)
+	}
+
+	return &permissionapi.DeleteRoleResponse{}, nil
+}
+
+// BulkUpdatePlayerRoles adds or removes a role for many players at once. Invalid and duplicate player ids and
+// failed writes are reported per player rather than failing the request.
+func (s *roleService) BulkUpdatePlayerRoles(ctx context.Context, req *permissionapi.BulkUpdatePlayerRolesRequest) (*permissionapi.BulkUpdatePlayerRolesResponse, error) {
+	if err := validation.BulkUpdatePlayerRolesRequest(req); err != nil {
+		return nil, err
+	}
+
+	// Every player is given the default role, so it can't be granted or revoked in bulk
+	if req.RoleId == s.defaultRoleId {
+		return nil, status.Error(codes.FailedPrecondition, "the default role cannot be bulk updated")
+	}
+
+	ok, err := s.repo.DoesRoleExist(ctx, req.RoleId)
+	if err != nil {
+		return nil, fmt.Errorf("error checking role exists: %w", err)
+	}
+	if !ok {
+		return nil, status.Error(codes.NotFound, "role not found")
+	}
+
+	results := make([]*permissionapi.BulkUpdatePlayerRolesResponse_Result, len(req.PlayerIds))
+	// rows holds the result index of each valid player id
+	rows := make(map[uuid.UUID]int, len(req.PlayerIds))
+	playerIds := make([]uuid.UUID, 0, len(req.PlayerIds))
+
+	for i, rawId := range req.PlayerIds {
+		result := &permissionapi.BulkUpdatePlayerRolesResponse_Result{PlayerId: rawId}
+		results[i] = result
+
+		playerId, err := uuid.Parse(rawId)
+		if err != nil {
+			result.Status = permissionapi.BulkUpdatePlayerRolesResponse_Result_INVALID_PLAYER_ID
+			continue
+		}
+		if _, ok := rows[playerId]; ok {
+			result.Status = permissionapi.BulkUpdatePlayerRolesResponse_Result_DUPLICATE
+			continue
+		}
+
+		rows[playerId] = i
+		playerIds = append(playerIds, playerId)
+	}
+
+	var changed, failed []uuid.UUID
+	unchangedStatus := permissionapi.BulkUpdatePlayerRolesResponse_Result_ALREADY_HAS_ROLE
+	changeType := permission2.PlayerRolesUpdateMessage_ADD
+
+	if len(playerIds) > 0 {
+		switch req.ChangeType {
+		case permissionapi.BulkUpdatePlayerRolesRequest_ADD:
+			changed, failed, err = s.repo.AddRoleToPlayers(ctx, playerIds, req.RoleId)
+		case permissionapi.BulkUpdatePlayerRolesRequest_REMOVE:
+			changed, failed, err = s.repo.RemoveRoleFromPlayers(ctx, playerIds, req.RoleId)
+			unchangedStatus = permissionapi.BulkUpdatePlayerRolesResponse_Result_DOES_NOT_HAVE_ROLE
+			changeType = permission2.PlayerRolesUpdateMessage_REMOVE
+		}
+	}
+	if err != nil {
+		// Not atomic, so the failed players are reported per row and the others are still notified
+		s.logger.Errorw("error updating player roles", "roleId", req.RoleId, "failed", len(failed), "error", err)
+	}
+
+	changes := make([]notifier.PlayerRolesChange, 0, len(changed))
+	for _, playerId := range changed {
+		changes = append(changes, notifier.PlayerRolesChange{PlayerId: playerId.String(), RoleId: req.RoleId, ChangeType: changeType})
+		delete(rows, playerId)
+	}
+	for _, playerId := range failed {
+		results[rows[playerId]].Status = permissionapi.BulkUpdatePlayerRolesResponse_Result_FAILED
+		delete(rows, playerId)
+	}
+
+	if len(changes) > 0 {
+		if err := s.notif.PlayerRolesUpdates(ctx, changes, changeMetaFromContext(ctx)); err != nil {
+			s.logger.Errorw("error sending player roles updates", "error", err)
+		}
+	}
+
+	for _, row := range rows {
+		results[row].Status = unchangedStatus
+	}
+
+	return &permissionapi.BulkUpdatePlayerRolesResponse{Results: results}, nil
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/emortalmc/proto-specs/gen/go/message/permission"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"permission-service/api/permissionapi"
+	"permission-service/internal/messaging/notifier"
+	"permission-service/internal/repository"
+	"permission-service/internal/repository/model"
+	"testing"
+)
+
+// testDefaultRoleId is configured rather than the usual "default", so the service must use the id it's given
+const testDefaultRoleId = "member"
+
+func TestRoleService_DeleteRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		managed bool
+
+		getErr    error
+		deleteErr error
+
+		wantCode codes.Code
+	}{
+		{name: "success", id: "helper", wantCode: codes.OK},
+		{name: "empty id", id: "", wantCode: codes.InvalidArgument},
+		{name: "default role", id: testDefaultRoleId, wantCode: codes.FailedPrecondition},
+		{name: "managed role", id: "helper", managed: true, wantCode: codes.FailedPrecondition},
+		{name: "role not found", id: "helper", getErr: mongo.ErrNoDocuments, wantCode: codes.NotFound},
+		{name: "deleted concurrently", id: "helper", deleteErr: mongo.ErrNoDocuments, wantCode: codes.NotFound},
+		{name: "repository error", id: "helper", deleteErr: errors.New("boom"), wantCode: codes.Unknown},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			mockCntrl := gomock.NewController(t)
+			mockRepo := repository.NewMockRepository(mockCntrl)
+			mockNotif := notifier.NewMockNotifier(mockCntrl)
+			role := &model.Role{Id: "helper", Priority: 20, Managed: tt.managed}
+
+			if tt.wantCode != codes.InvalidArgument && tt.id != testDefaultRoleId {
+				mockRepo.EXPECT().GetRole(gomock.Any(), tt.id).Return(role, tt.getErr)
+			}
+			if tt.getErr == nil && tt.wantCode != codes.InvalidArgument && tt.wantCode != codes.FailedPrecondition {
+				mockRepo.EXPECT().DeleteRole(gomock.Any(), tt.id).Return(tt.deleteErr)
+			}
+			if tt.wantCode == codes.OK {
+				mockNotif.EXPECT().RoleUpdate(gomock.Any(), gomock.Nil(), role, permission.RoleUpdateMessage_DELETE, gomock.Any()).Return(nil)
+			}
+
+			svc := NewRoleService(zap.NewNop().Sugar(), mockRepo, mockNotif, testDefaultRoleId)
+
+			_, err := svc.DeleteRole(context.Background(), &permissionapi.DeleteRoleRequest{Id: tt.id})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestRoleService_BulkUpdatePlayerRoles(t *testing.T) {
+	notch := uuid.MustParse("069a79f4-44e9-4726-a5be-fca90e38aaf5")
+	jeb := uuid.MustParse("61699b2e-d327-4a01-9f1e-0ea8c3f06bc6")
+	playerIds := []string{notch.String(), "not-a-uuid", jeb.String(), notch.String()}
+
+	tests := []struct {
+		name       string
+		changeType permissionapi.BulkUpdatePlayerRolesRequest_ChangeType
+
+		changed []uuid.UUID
+
+		wantStatuses []permissionapi.BulkUpdatePlayerRolesResponse_Result_Status
+		wantChanges  []notifier.PlayerRolesChange
+	}{
+		{
+			name:       "add",
+			changeType: permissionapi.BulkUpdatePlayerRolesRequest_ADD,
+			changed:    []uuid.UUID{notch},
+			wantStatuses: []permissionapi.BulkUpdatePlayerRolesResponse_Result_Status{
+				permissionapi.BulkUpdatePlayerRolesResponse_Result_UPDATED,
+				permissionapi.BulkUpdatePlayerRolesResponse_Result_INVALID_PLAYER_ID,
+				permissionapi.BulkUpdatePlayerRolesResponse_Result_ALREADY_HAS_ROLE,
+				permissionapi.BulkUpdatePlayerRolesResponse_Result_DUPLICATE,
+			},
+			wantChanges: []notifier.PlayerRolesChange{
+				{PlayerId: notch.String(), RoleId: "vip", ChangeType: permission.PlayerRolesUpdateMessage_ADD},
+			},
+		},
+		{
+			name:       "remove",
+			changeType: permissionapi.BulkUpdatePlayerRolesRequest_REMOVE,
+			changed:    []uuid.UUID{jeb},
+			wantStatuses: []permissionapi.BulkUpdatePlayerRolesResponse_Result_Status{
+				permissionapi.BulkUpdatePlayerRolesResponse_Result_DOES_NOT_HAVE_ROLE,
+				permissionapi.BulkUpdatePlayerRolesResponse_Result_INVALID_PLAYER_ID,
+				permissionapi.BulkUpdatePlayerRolesResponse_Result_UPDATED,
+				permissionapi.BulkUpdatePlayerRolesResponse_Result_DUPLICATE,
+			},
+			wantChanges: []notifier.PlayerRolesChange{
+				{PlayerId: jeb.String(), RoleId: "vip", ChangeType: permission.PlayerRolesUpdateMessage_REMOVE},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			mockCntrl := gomock.NewController(t)
+			mockRepo := repository.NewMockRepository(mockCntrl)
+			mockNotif := notifier.NewMockNotifier(mockCntrl)
+
+			mockRepo.EXPECT().DoesRoleExist(gomock.Any(), "vip").Return(true, nil)
+			if tt.changeType == permissionapi.BulkUpdatePlayerRolesRequest_ADD {
+				mockRepo.EXPECT().AddRoleToPlayers(gomock.Any(), []uuid.UUID{notch, jeb}, "vip").Return(tt.changed, nil, nil)
+			} else {
+				mockRepo.EXPECT().RemoveRoleFromPlayers(gomock.Any(), []uuid.UUID{notch, jeb}, "vip").Return(tt.changed, nil, nil)
+			}
+			mockNotif.EXPECT().PlayerRolesUpdates(gomock.Any(), tt.wantChanges, gomock.Any()).Return(nil)
+
+			svc := NewRoleService(zap.NewNop().Sugar(), mockRepo, mockNotif, testDefaultRoleId)
+
+			res, err := svc.BulkUpdatePlayerRoles(context.Background(), &permissionapi.BulkUpdatePlayerRolesRequest{
+				RoleId: "vip", ChangeType: tt.changeType, PlayerIds: playerIds,
+			})
+			require.NoError(t, err)
+
+			statuses := make([]permissionapi.BulkUpdatePlayerRolesResponse_Result_Status, len(res.Results))
+			for i, result := range res.Results {
+				assert.Equal(t, playerIds[i], result.PlayerId)
+				statuses[i] = result.Status
+			}
+			assert.Equal(t, tt.wantStatuses, statuses)
+		})
+	}
+}
+
+func TestRoleService_BulkUpdatePlayerRoles_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		roleId string
+		exists bool
+
+		wantCode codes.Code
+	}{
+		{name: "empty role id", roleId: "", wantCode: codes.InvalidArgument},
+		{name: "default role", roleId: testDefaultRoleId, wantCode: codes.FailedPrecondition},
+		{name: "role not found", roleId: "vip", wantCode: codes.NotFound},
+		{name: "only invalid players", roleId: "vip", exists: true, wantCode: codes.OK},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			mockCntrl := gomock.NewController(t)
+			mockRepo := repository.NewMockRepository(mockCntrl)
+
+			if tt.wantCode == codes.NotFound || tt.wantCode == codes.OK {
+				mockRepo.EXPECT().DoesRoleExist(gomock.Any(), tt.roleId).Return(tt.exists, nil)
+			}
+
+			svc := NewRoleService(zap.NewNop().Sugar(), mockRepo, notifier.NewMockNotifier(mockCntrl), testDefaultRoleId)
+
+			_, err := svc.BulkUpdatePlayerRoles(context.Background(), &permissionapi.BulkUpdatePlayerRolesRequest{
+				RoleId: tt.roleId, PlayerIds: []string{"notch"},
+			})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestRoleService_BulkUpdatePlayerRoles_PartialFailure(t *testing.T) {
+	notch := uuid.MustParse("069a79f4-44e9-4726-a5be-fca90e38aaf5")
+	jeb := uuid.MustParse("61699b2e-d327-4a01-9f1e-0ea8c3f06bc6")
+
+	mockCntrl := gomock.NewController(t)
+	mockRepo := repository.NewMockRepository(mockCntrl)
+	mockNotif := notifier.NewMockNotifier(mockCntrl)
+
+	mockRepo.EXPECT().DoesRoleExist(gomock.Any(), "vip").Return(true, nil)
+	mockRepo.EXPECT().AddRoleToPlayers(gomock.Any(), []uuid.UUID{notch, jeb}, "vip").Return([]uuid.UUID{notch}, []uuid.UUID{jeb}, errors.New("boom"))
+	// The player written despite the failure is still notified
+	mockNotif.EXPECT().PlayerRolesUpdates(gomock.Any(), []notifier.PlayerRolesChange{
+		{PlayerId: notch.String(), RoleId: "vip", ChangeType: permission.PlayerRolesUpdateMessage_ADD},
+	}, gomock.Any()).Return(nil)
+
+	svc := NewRoleService(zap.NewNop().Sugar(), mockRepo, mockNotif, testDefaultRoleId)
+
+	res, err := svc.BulkUpdatePlayerRoles(context.Background(), &permissionapi.BulkUpdatePlayerRolesRequest{
+		RoleId: "vip", PlayerIds: []string{notch.String(), jeb.String()},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*permissionapi.BulkUpdatePlayerRolesResponse_Result{
+		{PlayerId: notch.String(), Status: permissionapi.BulkUpdatePlayerRolesResponse_Result_UPDATED},
+		{PlayerId: jeb.String(), Status: permissionapi.BulkUpdatePlayerRolesResponse_Result_FAILED},
+	}, res.Results)
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/emortalmc/proto-specs/gen/go/grpc/permission"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+	"permission-service/internal/auth"
+	"permission-service/internal/config"
+	"permission-service/internal/repository"
+	"permission-service/internal/repository/model"
+	"permission-service/internal/roleset"
+	"permission-service/internal/utils/filewatch"
+	"sync"
+	"time"
+)
+
+// RoleSyncActor is the actor of changes made by the role sync reconciler
+const RoleSyncActor = "role-sync"
+
+// managedRoleWriteKey marks a context as the role sync reconciler's, which is the only writer allowed to change managed roles
+type managedRoleWriteKey struct{}
+
+func isManagedRoleWrite(ctx context.Context) bool {
+	managed, _ := ctx.Value(managedRoleWriteKey{}).(bool)
+	return managed
+}
+
+// RunRoleSync reconciles the roles declared in cfg.Path whenever the files change, and every cfg.Interval.
+// Changes go through svc, so they're validated and notified like any other. Declared roles are marked managed
+// and can then only be changed by editing the files. Roles that aren't declared are left as they are.
+//
+// Every replica reconciles independently. If they race to create the same role, the loser's create fails
+// and is retried on its next reconcile, which finds the role already matches.
+func RunRoleSync(ctx context.Context, wg *sync.WaitGroup, logger *zap.SugaredLogger, cfg config.RoleSyncConfig,
+	repo repository.Repository, svc permission.PermissionServiceServer) error {
+
+	changes, err := filewatch.Watch(ctx, wg, logger, cfg.Path)
+	if err != nil {
+		return fmt.Errorf("failed to watch role sync path: %w", err)
+	}
+
+	syncer := &roleSyncer{logger: logger, path: cfg.Path, repo: repo, svc: svc}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		var tick <-chan time.Time
+		if cfg.Interval > 0 {
+			ticker := time.NewTicker(cfg.Interval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+
+		syncer.sync(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-changes:
+				syncer.sync(ctx)
+			case <-tick:
+				syncer.sync(ctx)
+			}
+		}
+	}()
+
+	logger.Infow("started role sync", "path", cfg.Path, "interval", cfg.Interval)
+	return nil
+}
+
+type roleSyncer struct {
+	logger *zap.SugaredLogger
+	path   string
+
+	repo repository.Repository
+	svc  permission.PermissionServiceServer
+}
+
+func (s *roleSyncer) sync(ctx context.Context) {
+	if err := s.reconcile(ctx); err != nil && ctx.Err() == nil {
+		s.logger.Errorw("failed to reconcile roles", "path", s.path, "error", err)
+	}
+}
+
+func (s *roleSyncer) reconcile(ctx context.Context) error {
+	// An invalid file leaves the roles as they are until it's fixed
+	desired, err := roleset.Load(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to load roles: %w", err)
+	}
+
+	current, err := s.repo.GetAllRoles(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get roles: %w", err)
+	}
+
+	plan := roleset.Diff(current, desired)
+	if err := plan.Apply(s.writeContext(ctx), s.svc); err != nil {
+		return err
+	}
+
+	managed := make([]string, len(desired))
+	for i, role := range desired {
+		managed[i] = role.Id
+	}
+
+	if !plan.IsEmpty() || !managedMatches(current, managed) {
+		if err := s.repo.SetManagedRoles(ctx, managed); err != nil {
+			return fmt.Errorf("failed to mark managed roles: %w", err)
+		}
+	}
+
+	if !plan.IsEmpty() {
+		s.logger.Infow("reconciled roles", "path", s.path, "changed", len(plan.Changes), "unchanged", len(plan.Unchanged))
+	}
+
+	return nil
+}
+
+// writeContext allows changes to managed roles, and attributes them to the role sync
+func (s *roleSyncer) writeContext(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, managedRoleWriteKey{}, true)
+	ctx = auth.ContextWithIdentity(ctx, &auth.Identity{Name: RoleSyncActor})
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(ReasonMetadataKey, "reconciled from "+s.path))
+}
+
+// managedMatches reports whether exactly the roles in managed are marked managed.
+func managedMatches(roles []*model.Role, managed []string) bool {
+	declared := make(map[string]bool, len(managed))
+	for _, id := range managed {
+		declared[id] = true
+	}
+
+	for _, role := range roles {
+		if role.Managed != declared[role.Id] {
+			return false
+		}
+		delete(declared, role.Id)
+	}
+
+	return len(declared) == 0
+}
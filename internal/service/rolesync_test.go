@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"github.com/emortalmc/proto-specs/gen/go/message/permission"
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"permission-service/internal/messaging/notifier"
+	"permission-service/internal/repository"
+	"permission-service/internal/repository/model"
+	"testing"
+)
+
+const testRoleSyncFile = `roles:
+  - id: admin
+    priority: 100
+    permissions:
+      - node: command.ban
+        permissionState: ALLOW
+  - id: builder
+    priority: 10
+`
+
+func newTestRoleSyncer(t *testing.T, contents string) (*roleSyncer, *repository.MockRepository, *notifier.MockNotifier) {
+	path := filepath.Join(t.TempDir(), "roles.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o644))
+
+	mockCntrl := gomock.NewController(t)
+	mockRepo := repository.NewMockRepository(mockCntrl)
+	mockNotif := notifier.NewMockNotifier(mockCntrl)
+
+	logger := zap.NewNop().Sugar()
+	svc := NewPermissionService(logger, mockRepo, mockNotif)
+
+	return &roleSyncer{logger: logger, path: path, repo: mockRepo, svc: svc}, mockRepo, mockNotif
+}
+
+func TestRoleSyncer_Reconcile(t *testing.T) {
+	syncer, mockRepo, mockNotif := newTestRoleSyncer(t, testRoleSyncFile)
+
+	admin := &model.Role{Id: "admin", Priority: 90, Managed: true, Permissions: []model.PermissionNode{
+		{Node: "command.ban", State: protoModel.PermissionNode_ALLOW},
+	}}
+	current := []*model.Role{{Id: model.DefaultRoleId}, admin.Clone()}
+
+	mockRepo.EXPECT().GetAllRoles(gomock.Any()).Return(current, nil)
+
+	// Managed roles can be updated by the reconciler
+	updated := admin.Clone()
+	updated.Priority = 100
+	mockRepo.EXPECT().GetRole(gomock.Any(), "admin").Return(admin, nil)
+	mockRepo.EXPECT().UpdateRole(gomock.Any(), updated).Return(nil)
+
+	mockRepo.EXPECT().CreateRole(gomock.Any(), gomock.Any()).Return(nil)
+
+	changes := make([]notifier.ChangeMeta, 0)
+	recordMeta := func(_ context.Context, _ *model.Role, _ *model.Role, _ permission.RoleUpdateMessage_ChangeType, meta notifier.ChangeMeta) error {
+		changes = append(changes, meta)
+		return nil
+	}
+	mockNotif.EXPECT().RoleUpdate(gomock.Any(), gomock.Any(), gomock.Any(), permission.RoleUpdateMessage_MODIFY, gomock.Any()).DoAndReturn(recordMeta)
+	mockNotif.EXPECT().RoleUpdate(gomock.Any(), gomock.Nil(), gomock.Any(), permission.RoleUpdateMessage_CREATE, gomock.Any()).DoAndReturn(recordMeta)
+
+	mockRepo.EXPECT().SetManagedRoles(gomock.Any(), []string{"admin", "builder"}).Return(nil)
+
+	require.NoError(t, syncer.reconcile(context.Background()))
+
+	require.Len(t, changes, 2)
+	for _, meta := range changes {
+		assert.Equal(t, RoleSyncActor, meta.Actor)
+		assert.Equal(t, "reconciled from "+syncer.path, meta.Reason)
+	}
+}
+
+func TestRoleSyncer_Reconcile_NoChanges(t *testing.T) {
+	syncer, mockRepo, _ := newTestRoleSyncer(t, testRoleSyncFile)
+
+	current := []*model.Role{
+		{Id: model.DefaultRoleId},
+		{Id: "admin", Priority: 100, Managed: true, Permissions: []model.PermissionNode{
+			{Node: "command.ban", State: protoModel.PermissionNode_ALLOW},
+		}},
+		{Id: "builder", Priority: 10, Managed: true},
+	}
+	mockRepo.EXPECT().GetAllRoles(gomock.Any()).Return(current, nil)
+
+	assert.NoError(t, syncer.reconcile(context.Background()))
+}
+
+func TestRoleSyncer_Reconcile_InvalidFile(t *testing.T) {
+	syncer, _, _ := newTestRoleSyncer(t, "roles:\n  - id: admin\n  - id: admin\n")
+
+	assert.ErrorContains(t, syncer.reconcile(context.Background()), "admin is defined more than once")
+}
+
+func TestManagedMatches(t *testing.T) {
+	roles := []*model.Role{{Id: "default"}, {Id: "admin", Managed: true}}
+
+	assert.True(t, managedMatches(roles, []string{"admin"}))
+	assert.False(t, managedMatches(roles, []string{"admin", "default"}))
+	assert.False(t, managedMatches(roles, nil))
+	assert.False(t, managedMatches(roles, []string{"admin", "builder"}))
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	mongoDb "go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"permission-service/api/permissionapi"
+	"permission-service/internal/auth"
+	"permission-service/internal/repository"
+	"permission-service/internal/repository/model"
+	"permission-service/internal/validation"
+	"time"
+)
+
+var serviceAccountNotFound = status.Error(codes.NotFound, "service account not found")
+
+type serviceAccountService struct {
+	permissionapi.UnimplementedServiceAccountServiceServer
+
+	logger *zap.SugaredLogger
+
+	repo repository.Repository
+}
+
+func NewServiceAccountService(logger *zap.SugaredLogger, repo repository.Repository) permissionapi.ServiceAccountServiceServer {
+	return &serviceAccountService{
+		logger: logger,
+
+		repo: repo,
+	}
+}
+
+func (s *serviceAccountService) GetServiceAccounts(ctx context.Context, _ *permissionapi.GetServiceAccountsRequest) (*permissionapi.GetServiceAccountsResponse, error) {
+	accounts, err := s.repo.GetAllServiceAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting service accounts: %w", err)
+	}
+
+	protoAccounts := make([]*permissionapi.ServiceAccount, len(accounts))
+	for i, account := range accounts {
+		protoAccounts[i] = account.ToProto()
+	}
+
+	return &permissionapi.GetServiceAccountsResponse{ServiceAccounts: protoAccounts}, nil
+}
+
+func (s *serviceAccountService) CreateServiceAccount(ctx context.Context, req *permissionapi.CreateServiceAccountRequest) (*permissionapi.CreateServiceAccountResponse, error) {
+	if err := validation.CreateServiceAccountRequest(req); err != nil {
+		return nil, err
+	}
+
+	token, err := auth.GenerateServiceAccountToken()
+	if err != nil {
+		return nil, fmt.Errorf("error generating token: %w", err)
+	}
+
+	account := &model.ServiceAccount{
+		Id:          req.Id,
+		Description: req.Description,
+		TokenHash:   auth.HashToken(token),
+		Roles:       make([]string, 0),
+		Permissions: make([]model.PermissionNode, 0),
+		CreatedAt:   time.Now(),
+	}
+
+	if err := s.repo.CreateServiceAccount(ctx, account); err != nil {
+		if mongoDb.IsDuplicateKeyError(err) {
+			return nil, status.Error(codes.AlreadyExists, "service account already exists")
+		}
+		return nil, fmt.Errorf("error creating service account: %w", err)
+	}
+
+	s.logger.Infow("created service account", "id", account.Id, "actor", changeMetaFromContext(ctx).Actor)
+
+	return &permissionapi.CreateServiceAccountResponse{
+		ServiceAccount: account.ToProto(),
+		Token:          token,
+	}, nil
+}
+
+func (s *serviceAccountService) DeleteServiceAccount(ctx context.Context, req *permissionapi.DeleteServiceAccountRequest) (*permissionapi.DeleteServiceAccountResponse, error) {
+	if err := s.repo.DeleteServiceAccount(ctx, req.Id); err != nil {
+		if err == mongoDb.ErrNoDocuments {
+			return nil, serviceAccountNotFound
+		}
+		return nil, fmt.Errorf("error deleting service account: %w", err)
+	}
+
+	s.logger.Infow("deleted service account", "id", req.Id, "actor", changeMetaFromContext(ctx).Actor)
+
+	return &permissionapi.DeleteServiceAccountResponse{}, nil
+}
+
+func (s *serviceAccountService) RotateServiceAccountToken(ctx context.Context, req *permissionapi.RotateServiceAccountTokenRequest) (*permissionapi.RotateServiceAccountTokenResponse, error) {
+	account, err := s.getServiceAccount(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := auth.GenerateServiceAccountToken()
+	if err != nil {
+		return nil, fmt.Errorf("error generating token: %w", err)
+	}
+
+	account.TokenHash = auth.HashToken(token)
+	if err := s.repo.UpdateServiceAccount(ctx, account); err != nil {
+		return nil, fmt.Errorf("error updating service account: %w", err)
+	}
+
+	s.logger.Infow("rotated service account token", "id", account.Id, "actor", changeMetaFromContext(ctx).Actor)
+
+	return &permissionapi.RotateServiceAccountTokenResponse{Token: token}, nil
+}
+
+func (s *serviceAccountService) AddRoleToServiceAccount(ctx context.Context, req *permissionapi.AddRoleToServiceAccountRequest) (*permissionapi.AddRoleToServiceAccountResponse, error) {
+	if err := validation.ServiceAccountRoleRequest(req.Id, req.RoleId); err != nil {
+		return nil, err
+	}
+
+	ok, err := s.repo.DoesRoleExist(ctx, req.RoleId)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, status.Error(codes.NotFound, "role not found")
+	}
+
+	if err := s.repo.AddRoleToServiceAccount(ctx, req.Id, req.RoleId); err != nil {
+		if err == mongoDb.ErrNoDocuments {
+			return nil, serviceAccountNotFound
+		} else if err == repository.AlreadyHasRoleError {
+			return nil, status.Error(codes.AlreadyExists, "service account already has role")
+		}
+		return nil, err
+	}
+
+	return &permissionapi.AddRoleToServiceAccountResponse{}, nil
+}
+
+func (s *serviceAccountService) RemoveRoleFromServiceAccount(ctx context.Context, req *permissionapi.RemoveRoleFromServiceAccountRequest) (*permissionapi.RemoveRoleFromServiceAccountResponse, error) {
+	if err := validation.ServiceAccountRoleRequest(req.Id, req.RoleId); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.RemoveRoleFromServiceAccount(ctx, req.Id, req.RoleId); err != nil {
+		if err == mongoDb.ErrNoDocuments {
+			return nil, serviceAccountNotFound
+		} else if err == repository.DoesNotHaveRoleError {
+			return nil, status.Error(codes.NotFound, "service account does not have role")
+		}
+		return nil, err
+	}
+
+	return &permissionapi.RemoveRoleFromServiceAccountResponse{}, nil
+}
+
+func (s *serviceAccountService) UpdateServiceAccountPermissions(ctx context.Context, req *permissionapi.UpdateServiceAccountPermissionsRequest) (*permissionapi.UpdateServiceAccountPermissionsResponse, error) {
+	if err := validation.UpdateServiceAccountPermissionsRequest(req); err != nil {
+		return nil, err
+	}
+
+	account, err := s.getServiceAccount(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, perm := range req.UnsetPermissions {
+		for i, node := range account.Permissions {
+			if node.Node == perm {
+				account.Permissions = append(account.Permissions[:i], account.Permissions[i+1:]...)
+				break
+			}
+		}
+	}
+
+	// Update the permission state if it already exists, otherwise add it
+	for _, perm := range req.SetPermissions {
+		existed := false
+		for i, node := range account.Permissions {
+			if node.Node == perm.Node {
+				account.Permissions[i].State = perm.State
+				existed = true
+				break
+			}
+		}
+		if !existed {
+			account.Permissions = append(account.Permissions, model.PermissionNode{Node: perm.Node, State: perm.State})
+		}
+	}
+
+	if err := s.repo.UpdateServiceAccount(ctx, account); err != nil {
+		return nil, fmt.Errorf("error updating service account: %w", err)
+	}
+
+	return &permissionapi.UpdateServiceAccountPermissionsResponse{ServiceAccount: account.ToProto()}, nil
+}
+
+func (s *serviceAccountService) getServiceAccount(ctx context.Context, id string) (*model.ServiceAccount, error) {
+	account, err := s.repo.GetServiceAccount(ctx, id)
+	if err != nil {
+		if err == mongoDb.ErrNoDocuments {
+			return nil, serviceAccountNotFound
+		}
+		return nil, fmt.Errorf("error getting service account: %w", err)
+	}
+
+	return account, nil
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"permission-service/api/permissionapi"
+	"permission-service/internal/auth"
+	"permission-service/internal/repository"
+	"permission-service/internal/repository/model"
+	"testing"
+)
+
+func TestServiceAccountService_CreateServiceAccount(t *testing.T) {
+	mockCntrl := gomock.NewController(t)
+	mockRepo := repository.NewMockRepository(mockCntrl)
+
+	var created *model.ServiceAccount
+	mockRepo.EXPECT().CreateServiceAccount(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, account *model.ServiceAccount) error {
+		created = account
+		return nil
+	})
+
+	svc := NewServiceAccountService(zap.NewNop().Sugar(), mockRepo)
+
+	res, err := svc.CreateServiceAccount(context.Background(), &permissionapi.CreateServiceAccountRequest{Id: "discord-bot", Description: "Grants cosmetics"})
+	assert.NoError(t, err)
+	assert.Equal(t, "discord-bot", res.ServiceAccount.Id)
+	assert.NotEmpty(t, res.Token)
+
+	// Only the hash of the token is stored
+	assert.Equal(t, auth.HashToken(res.Token), created.TokenHash)
+	assert.NotContains(t, created.TokenHash, res.Token)
+
+	_, err = svc.CreateServiceAccount(context.Background(), &permissionapi.CreateServiceAccountRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServiceAccountService_AddRoleToServiceAccount(t *testing.T) {
+	tests := []struct {
+		name string
+
+		roleExists bool
+		addErr     error
+
+		wantCode codes.Code
+	}{
+		{name: "success", roleExists: true, wantCode: codes.OK},
+		{name: "role not found", roleExists: false, wantCode: codes.NotFound},
+		{name: "service account not found", roleExists: true, addErr: mongo.ErrNoDocuments, wantCode: codes.NotFound},
+		{name: "already has role", roleExists: true, addErr: repository.AlreadyHasRoleError, wantCode: codes.AlreadyExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCntrl := gomock.NewController(t)
+			mockRepo := repository.NewMockRepository(mockCntrl)
+
+			mockRepo.EXPECT().DoesRoleExist(gomock.Any(), "cosmetic").Return(tt.roleExists, nil)
+			if tt.roleExists {
+				mockRepo.EXPECT().AddRoleToServiceAccount(gomock.Any(), "discord-bot", "cosmetic").Return(tt.addErr)
+			}
+
+			svc := NewServiceAccountService(zap.NewNop().Sugar(), mockRepo)
+
+			_, err := svc.AddRoleToServiceAccount(context.Background(), &permissionapi.AddRoleToServiceAccountRequest{Id: "discord-bot", RoleId: "cosmetic"})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
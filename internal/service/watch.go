@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/emortalmc/proto-specs/gen/go/message/permission"
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"github.com/google/uuid"
+	mongoDb "go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"permission-service/api/permissionapi"
+	"permission-service/internal/messaging/notifier"
+	"permission-service/internal/repository"
+	"permission-service/internal/validation"
+)
+
+// watchBuffer is how many changes a watcher may fall behind by before its stream is aborted
+const watchBuffer = 256
+
+type watchService struct {
+	permissionapi.UnimplementedPermissionWatchServiceServer
+
+	// ctx ends every stream when done, as graceful stop waits for streams to finish
+	ctx    context.Context
+	logger *zap.SugaredLogger
+
+	repo   repository.Repository
+	events notifier.MemoryNotifier
+}
+
+// NewWatchService streams the changes published to events, which must receive the changes of every replica.
+func NewWatchService(ctx context.Context, logger *zap.SugaredLogger, repo repository.Repository,
+	events notifier.MemoryNotifier) permissionapi.PermissionWatchServiceServer {
+
+	return &watchService{
+		ctx:    ctx,
+		logger: logger,
+
+		repo:   repo,
+		events: events,
+	}
+}
+
+func (s *watchService) WatchPlayerPermissions(req *permissionapi.WatchPlayerPermissionsRequest,
+	stream permissionapi.PermissionWatchService_WatchPlayerPermissionsServer) error {
+
+	if err := validation.WatchPlayerPermissionsRequest(req); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+
+	// Subscribe before taking the snapshot so nothing applied in between is missed
+	events, unsubscribe := s.events.Subscribe(watchBuffer)
+	defer unsubscribe()
+
+	w := &watcher{
+		allRoles:    req.AllRoles,
+		heldRoles:   make(map[string]map[string]struct{}, len(req.PlayerIds)),
+		roleHolders: make(map[string]int),
+	}
+
+	snapshot, err := s.snapshot(ctx, req, w)
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(snapshot); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.ctx.Done():
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case event, ok := <-events:
+			if !ok {
+				return status.Error(codes.Aborted, "fell too far behind, watch again for a new snapshot")
+			}
+
+			updates, err := s.handleEvent(ctx, w, event)
+			if err != nil {
+				return err
+			}
+
+			for _, update := range updates {
+				if err := stream.Send(update); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func (s *watchService) snapshot(ctx context.Context, req *permissionapi.WatchPlayerPermissionsRequest,
+	w *watcher) (*permissionapi.WatchPlayerPermissionsResponse, error) {
+
+	players := make([]*permissionapi.WatchPlayerPermissionsResponse_PlayerRoles, 0, len(req.PlayerIds))
+	for _, playerId := range req.PlayerIds {
+		pId := uuid.MustParse(playerId)
+		// Events carry the canonical form of the id
+		playerId = pId.String()
+		if _, ok := w.heldRoles[playerId]; ok {
+			continue
+		}
+
+		roleIds, err := s.repo.GetPlayerRoleIds(ctx, pId)
+		if err != nil {
+			return nil, fmt.Errorf("error getting player roles: %w", err)
+		}
+
+		w.heldRoles[playerId] = make(map[string]struct{}, len(roleIds))
+		for _, roleId := range roleIds {
+			w.addRole(playerId, roleId)
+		}
+
+		players = append(players, &permissionapi.WatchPlayerPermissionsResponse_PlayerRoles{PlayerId: playerId, RoleIds: roleIds})
+	}
+
+	allRoles, err := s.repo.GetAllRoles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting all roles: %w", err)
+	}
+
+	roles := make([]*protoModel.Role, 0)
+	for _, role := range allRoles {
+		if w.watchesRole(role.Id) {
+			roles = append(roles, role.ToProto())
+		}
+	}
+
+	return &permissionapi.WatchPlayerPermissionsResponse{
+		Update: &permissionapi.WatchPlayerPermissionsResponse_Snapshot_{
+			Snapshot: &permissionapi.WatchPlayerPermissionsResponse_Snapshot{Roles: roles, Players: players},
+		},
+	}, nil
+}
+
+func (s *watchService) handleEvent(ctx context.Context, w *watcher, event notifier.Event) ([]*permissionapi.WatchPlayerPermissionsResponse, error) {
+	if event.IsRoleUpdate() {
+		if event.Role == nil || !w.watchesRole(event.Role.Id) {
+			return nil, nil
+		}
+
+		return []*permissionapi.WatchPlayerPermissionsResponse{
+			roleUpdateResponse(event.Role.ToProto(), event.RoleChangeType),
+		}, nil
+	}
+
+	if _, ok := w.heldRoles[event.PlayerId]; !ok {
+		return nil, nil
+	}
+
+	updates := []*permissionapi.WatchPlayerPermissionsResponse{{
+		Update: &permissionapi.WatchPlayerPermissionsResponse_PlayerRolesUpdate{
+			PlayerRolesUpdate: &permission.PlayerRolesUpdateMessage{
+				ChangeType: event.PlayerRolesChangeType,
+				PlayerId:   event.PlayerId,
+				RoleId:     event.RoleId,
+			},
+		},
+	}}
+
+	switch event.PlayerRolesChangeType {
+	case permission.PlayerRolesUpdateMessage_ADD:
+		newlyWatched := !w.watchesRole(event.RoleId)
+		w.addRole(event.PlayerId, event.RoleId)
+
+		// The watcher hasn't seen this role yet, so send it what the role grants
+		if newlyWatched {
+			role, err := s.repo.GetRole(ctx, event.RoleId)
+			if err != nil {
+				if err == mongoDb.ErrNoDocuments {
+					return updates, nil
+				}
+				return nil, fmt.Errorf("error getting role: %w", err)
+			}
+
+			updates = append(updates, roleUpdateResponse(role.ToProto(), permission.RoleUpdateMessage_MODIFY))
+		}
+	case permission.PlayerRolesUpdateMessage_REMOVE:
+		w.removeRole(event.PlayerId, event.RoleId)
+	}
+
+	return updates, nil
+}
+
+func roleUpdateResponse(role *protoModel.Role, changeType permission.RoleUpdateMessage_ChangeType) *permissionapi.WatchPlayerPermissionsResponse {
+	return &permissionapi.WatchPlayerPermissionsResponse{
+		Update: &permissionapi.WatchPlayerPermissionsResponse_RoleUpdate{
+			RoleUpdate: &permission.RoleUpdateMessage{ChangeType: changeType, Role: role},
+		},
+	}
+}
+
+// watcher tracks which roles are held by the players of a single stream.
+type watcher struct {
+	allRoles bool
+
+	// heldRoles are the role ids held by each watched player
+	heldRoles map[string]map[string]struct{}
+	// roleHolders is the number of watched players holding each role
+	roleHolders map[string]int
+}
+
+func (w *watcher) watchesRole(roleId string) bool {
+	return w.allRoles || w.roleHolders[roleId] > 0
+}
+
+func (w *watcher) addRole(playerId string, roleId string) {
+	if _, ok := w.heldRoles[playerId][roleId]; ok {
+		return
+	}
+
+	w.heldRoles[playerId][roleId] = struct{}{}
+	w.roleHolders[roleId]++
+}
+
+func (w *watcher) removeRole(playerId string, roleId string) {
+	if _, ok := w.heldRoles[playerId][roleId]; !ok {
+		return
+	}
+
+	delete(w.heldRoles[playerId], roleId)
+	w.roleHolders[roleId]--
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"github.com/emortalmc/proto-specs/gen/go/message/permission"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"permission-service/api/permissionapi"
+	"permission-service/internal/messaging/notifier"
+	"permission-service/internal/repository"
+	"permission-service/internal/repository/model"
+	"testing"
+	"time"
+)
+
+type fakeWatchStream struct {
+	grpc.ServerStream
+
+	ctx       context.Context
+	responses chan *permissionapi.WatchPlayerPermissionsResponse
+}
+
+func (f *fakeWatchStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeWatchStream) Send(res *permissionapi.WatchPlayerPermissionsResponse) error {
+	f.responses <- res
+	return nil
+}
+
+func (f *fakeWatchStream) next(t *testing.T) *permissionapi.WatchPlayerPermissionsResponse {
+	select {
+	case res := <-f.responses:
+		return res
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for response")
+		return nil
+	}
+}
+
+func (f *fakeWatchStream) assertNoResponse(t *testing.T) {
+	select {
+	case res := <-f.responses:
+		t.Fatalf("unexpected response: %v", res)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func startWatch(t *testing.T, repo repository.Repository, events notifier.MemoryNotifier,
+	req *permissionapi.WatchPlayerPermissionsRequest) (*fakeWatchStream, <-chan error) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	stream := &fakeWatchStream{ctx: ctx, responses: make(chan *permissionapi.WatchPlayerPermissionsResponse, 16)}
+	svc := NewWatchService(context.Background(), zap.NewNop().Sugar(), repo, events)
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- svc.WatchPlayerPermissions(req, stream)
+	}()
+
+	return stream, errs
+}
+
+func TestWatchService_WatchPlayerPermissions(t *testing.T) {
+	playerId := uuid.New()
+	otherPlayerId := uuid.New().String()
+
+	mockCntrl := gomock.NewController(t)
+	mockRepo := repository.NewMockRepository(mockCntrl)
+
+	mockRepo.EXPECT().GetPlayerRoleIds(gomock.Any(), playerId).Return([]string{"default", "vip"}, nil)
+	mockRepo.EXPECT().GetAllRoles(gomock.Any()).Return([]*model.Role{
+		{Id: "default", Priority: 0},
+		{Id: "vip", Priority: 10},
+		{Id: "admin", Priority: 100},
+	}, nil)
+	mockRepo.EXPECT().GetRole(gomock.Any(), "admin").Return(&model.Role{Id: "admin", Priority: 100}, nil)
+
+	events := notifier.NewMemoryNotifier()
+	stream, _ := startWatch(t, mockRepo, events, &permissionapi.WatchPlayerPermissionsRequest{PlayerIds: []string{playerId.String()}})
+
+	snapshot := stream.next(t).GetSnapshot()
+	require.NotNil(t, snapshot)
+	require.Len(t, snapshot.Players, 1)
+	assert.Equal(t, playerId.String(), snapshot.Players[0].PlayerId)
+	assert.Equal(t, []string{"default", "vip"}, snapshot.Players[0].RoleIds)
+
+	roleIds := make([]string, len(snapshot.Roles))
+	for i, role := range snapshot.Roles {
+		roleIds[i] = role.Id
+	}
+	assert.Equal(t, []string{"default", "vip"}, roleIds)
+
+	ctx := context.Background()
+
+	// Changes to roles and players that aren't watched are filtered out
+	assert.NoError(t, events.RoleUpdate(ctx, nil, &model.Role{Id: "admin"}, permission.RoleUpdateMessage_MODIFY, notifier.ChangeMeta{}))
+	assert.NoError(t, events.PlayerRolesUpdate(ctx, otherPlayerId, "vip", permission.PlayerRolesUpdateMessage_ADD, notifier.ChangeMeta{}))
+	stream.assertNoResponse(t)
+
+	assert.NoError(t, events.RoleUpdate(ctx, nil, &model.Role{Id: "vip", Priority: 20}, permission.RoleUpdateMessage_MODIFY, notifier.ChangeMeta{}))
+	roleUpdate := stream.next(t).GetRoleUpdate()
+	require.NotNil(t, roleUpdate)
+	assert.Equal(t, "vip", roleUpdate.Role.Id)
+	assert.Equal(t, uint32(20), roleUpdate.Role.Priority)
+
+	// Gaining a role the watcher hasn't seen sends the role's definition
+	assert.NoError(t, events.PlayerRolesUpdate(ctx, playerId.String(), "admin", permission.PlayerRolesUpdateMessage_ADD, notifier.ChangeMeta{}))
+	playerUpdate := stream.next(t).GetPlayerRolesUpdate()
+	require.NotNil(t, playerUpdate)
+	assert.Equal(t, "admin", playerUpdate.RoleId)
+	assert.Equal(t, permission.PlayerRolesUpdateMessage_ADD, playerUpdate.ChangeType)
+
+	roleUpdate = stream.next(t).GetRoleUpdate()
+	require.NotNil(t, roleUpdate)
+	assert.Equal(t, "admin", roleUpdate.Role.Id)
+
+	// Once no watched player holds a role, its changes are filtered out again
+	assert.NoError(t, events.PlayerRolesUpdate(ctx, playerId.String(), "admin", permission.PlayerRolesUpdateMessage_REMOVE, notifier.ChangeMeta{}))
+	assert.NotNil(t, stream.next(t).GetPlayerRolesUpdate())
+
+	assert.NoError(t, events.RoleUpdate(ctx, nil, &model.Role{Id: "admin"}, permission.RoleUpdateMessage_MODIFY, notifier.ChangeMeta{}))
+	stream.assertNoResponse(t)
+}
+
+func TestWatchService_WatchPlayerPermissions_AllRoles(t *testing.T) {
+	mockCntrl := gomock.NewController(t)
+	mockRepo := repository.NewMockRepository(mockCntrl)
+
+	mockRepo.EXPECT().GetAllRoles(gomock.Any()).Return([]*model.Role{{Id: "default"}, {Id: "admin"}}, nil)
+
+	events := notifier.NewMemoryNotifier()
+	stream, _ := startWatch(t, mockRepo, events, &permissionapi.WatchPlayerPermissionsRequest{AllRoles: true})
+
+	snapshot := stream.next(t).GetSnapshot()
+	require.NotNil(t, snapshot)
+	assert.Len(t, snapshot.Roles, 2)
+	assert.Empty(t, snapshot.Players)
+
+	assert.NoError(t, events.RoleUpdate(context.Background(), nil, &model.Role{Id: "builder"}, permission.RoleUpdateMessage_CREATE, notifier.ChangeMeta{}))
+	roleUpdate := stream.next(t).GetRoleUpdate()
+	require.NotNil(t, roleUpdate)
+	assert.Equal(t, permission.RoleUpdateMessage_CREATE, roleUpdate.ChangeType)
+	assert.Equal(t, "builder", roleUpdate.Role.Id)
+}
+
+func TestWatchService_WatchPlayerPermissions_FallenBehind(t *testing.T) {
+	mockCntrl := gomock.NewController(t)
+	mockRepo := repository.NewMockRepository(mockCntrl)
+
+	mockRepo.EXPECT().GetAllRoles(gomock.Any()).Return([]*model.Role{}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Sends block until the test reads them, so the watcher can't keep up with the events below
+	stream := &fakeWatchStream{ctx: ctx, responses: make(chan *permissionapi.WatchPlayerPermissionsResponse)}
+	events := notifier.NewMemoryNotifier()
+	svc := NewWatchService(context.Background(), zap.NewNop().Sugar(), mockRepo, events)
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- svc.WatchPlayerPermissions(&permissionapi.WatchPlayerPermissionsRequest{AllRoles: true}, stream)
+	}()
+
+	// The watcher has subscribed once the snapshot is being sent
+	stream.next(t)
+	for i := 0; i < watchBuffer+2; i++ {
+		assert.NoError(t, events.RoleUpdate(ctx, nil, &model.Role{Id: "default"}, permission.RoleUpdateMessage_MODIFY, notifier.ChangeMeta{}))
+	}
+
+	// Drain whatever was buffered before the subscription was dropped
+	for {
+		select {
+		case <-stream.responses:
+		case err := <-errs:
+			assert.Equal(t, codes.Aborted, status.Code(err))
+			return
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for the watch to abort")
+		}
+	}
+}
+
+func TestWatchService_WatchPlayerPermissions_Invalid(t *testing.T) {
+	svc := NewWatchService(context.Background(), zap.NewNop().Sugar(), nil, notifier.NewMemoryNotifier())
+
+	err := svc.WatchPlayerPermissions(&permissionapi.WatchPlayerPermissionsRequest{PlayerIds: []string{"not-a-uuid"}}, &fakeWatchStream{ctx: context.Background()})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestWatchService_WatchPlayerPermissions_ServerShutdown(t *testing.T) {
+	mockCntrl := gomock.NewController(t)
+	mockRepo := repository.NewMockRepository(mockCntrl)
+
+	mockRepo.EXPECT().GetAllRoles(gomock.Any()).Return([]*model.Role{}, nil)
+
+	serverCtx, shutdown := context.WithCancel(context.Background())
+	stream := &fakeWatchStream{ctx: context.Background(), responses: make(chan *permissionapi.WatchPlayerPermissionsResponse, 1)}
+	svc := NewWatchService(serverCtx, zap.NewNop().Sugar(), mockRepo, notifier.NewMemoryNotifier())
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- svc.WatchPlayerPermissions(&permissionapi.WatchPlayerPermissionsRequest{AllRoles: true}, stream)
+	}()
+
+	stream.next(t)
+	shutdown()
+
+	select {
+	case err := <-errs:
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	case <-time.After(time.Second):
+		t.Fatal("stream did not end on shutdown")
+	}
+}
@@ -0,0 +1,89 @@
+package tracing
+
+import (
+	"context"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// headerCarrier adapts Kafka message headers to the OTel propagation API.
+type headerCarrier struct {
+	headers *[]kafka.Header
+}
+
+func (c headerCarrier) Get(key string) string {
+	for _, h := range *c.headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+
+	return ""
+}
+
+func (c headerCarrier) Set(key string, value string) {
+	for i, h := range *c.headers {
+		if h.Key == key {
+			(*c.headers)[i].Value = []byte(value)
+			return
+		}
+	}
+
+	*c.headers = append(*c.headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, len(*c.headers))
+	for i, h := range *c.headers {
+		keys[i] = h.Key
+	}
+
+	return keys
+}
+
+// InjectKafkaHeaders adds the trace context of ctx to headers (e.g. "traceparent").
+func InjectKafkaHeaders(ctx context.Context, headers []kafka.Header) []kafka.Header {
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier{headers: &headers})
+	return headers
+}
+
+// ExtractKafkaHeaders returns ctx with the trace context carried by headers, if any.
+func ExtractKafkaHeaders(ctx context.Context, headers []kafka.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, headerCarrier{headers: &headers})
+}
+
+// StartKafkaPublish starts a producer span for a message published to topic.
+// Its trace context should be injected into the message with InjectKafkaHeaders.
+func StartKafkaPublish(ctx context.Context, topic string, messageType string) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(kafkaAttributes(topic, messageType, semconv.MessagingOperationPublish)...),
+	)
+}
+
+// StartKafkaReceive starts a consumer span for m, continuing the trace of the producer.
+func StartKafkaReceive(ctx context.Context, m *kafka.Message, messageType string) (context.Context, trace.Span) {
+	ctx = ExtractKafkaHeaders(ctx, m.Headers)
+
+	attrs := append(kafkaAttributes(m.Topic, messageType, semconv.MessagingOperationReceive),
+		semconv.MessagingKafkaDestinationPartition(m.Partition),
+		semconv.MessagingKafkaMessageOffset(int(m.Offset)),
+	)
+
+	return Tracer().Start(ctx, m.Topic+" receive",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+func kafkaAttributes(topic string, messageType string, operation attribute.KeyValue) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.MessagingSystem("kafka"),
+		semconv.MessagingDestinationName(topic),
+		operation,
+		attribute.String("messaging.message.type", messageType),
+	}
+}
@@ -0,0 +1,189 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"permission-service/internal/repository"
+	"permission-service/internal/repository/model"
+	"time"
+)
+
+type tracedRepository struct {
+	repo repository.Repository
+}
+
+// TraceRepository creates a child span around every method of repo.
+func TraceRepository(repo repository.Repository) repository.Repository {
+	return &tracedRepository{repo: repo}
+}
+
+func startRepositorySpan(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, "repository."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append([]attribute.KeyValue{semconv.DBSystemMongoDB, semconv.DBOperation(method)}, attrs...)...),
+	)
+}
+
+func endRepositorySpan(span trace.Span, err error) {
+	// Missing documents are an expected result, not a failure of the database
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (r *tracedRepository) Ping(ctx context.Context) (err error) {
+	ctx, span := startRepositorySpan(ctx, "Ping")
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.Ping(ctx)
+}
+
+func (r *tracedRepository) SetTimeouts(timeout time.Duration, bulkTimeout time.Duration) {
+	r.repo.SetTimeouts(timeout, bulkTimeout)
+}
+
+func (r *tracedRepository) GetAllRoles(ctx context.Context) (roles []*model.Role, err error) {
+	ctx, span := startRepositorySpan(ctx, "GetAllRoles")
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.GetAllRoles(ctx)
+}
+
+func (r *tracedRepository) GetRole(ctx context.Context, roleId string) (role *model.Role, err error) {
+	ctx, span := startRepositorySpan(ctx, "GetRole", attribute.String("role.id", roleId))
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.GetRole(ctx, roleId)
+}
+
+func (r *tracedRepository) DoesRoleExist(ctx context.Context, roleId string) (exists bool, err error) {
+	ctx, span := startRepositorySpan(ctx, "DoesRoleExist", attribute.String("role.id", roleId))
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.DoesRoleExist(ctx, roleId)
+}
+
+func (r *tracedRepository) CreateRole(ctx context.Context, role *model.Role) (err error) {
+	ctx, span := startRepositorySpan(ctx, "CreateRole", attribute.String("role.id", role.Id))
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.CreateRole(ctx, role)
+}
+
+func (r *tracedRepository) UpdateRole(ctx context.Context, newRole *model.Role) (err error) {
+	ctx, span := startRepositorySpan(ctx, "UpdateRole", attribute.String("role.id", newRole.Id))
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.UpdateRole(ctx, newRole)
+}
+
+func (r *tracedRepository) DeleteRole(ctx context.Context, roleId string) (err error) {
+	ctx, span := startRepositorySpan(ctx, "DeleteRole", attribute.String("role.id", roleId))
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.DeleteRole(ctx, roleId)
+}
+
+func (r *tracedRepository) CreateRolesIfMissing(ctx context.Context, roles []*model.Role) (created []string, err error) {
+	ctx, span := startRepositorySpan(ctx, "CreateRolesIfMissing", attribute.Int("role.count", len(roles)))
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.CreateRolesIfMissing(ctx, roles)
+}
+
+func (r *tracedRepository) SetManagedRoles(ctx context.Context, roleIds []string) (err error) {
+	ctx, span := startRepositorySpan(ctx, "SetManagedRoles", attribute.Int("role.count", len(roleIds)))
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.SetManagedRoles(ctx, roleIds)
+}
+
+func (r *tracedRepository) CountPlayers(ctx context.Context) (count int64, err error) {
+	ctx, span := startRepositorySpan(ctx, "CountPlayers")
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.CountPlayers(ctx)
+}
+
+func (r *tracedRepository) GetPlayerRoleIds(ctx context.Context, playerId uuid.UUID) (roleIds []string, err error) {
+	ctx, span := startRepositorySpan(ctx, "GetPlayerRoleIds", attribute.String("player.id", playerId.String()))
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.GetPlayerRoleIds(ctx, playerId)
+}
+
+func (r *tracedRepository) AddRoleToPlayer(ctx context.Context, playerId uuid.UUID, roleId string) (err error) {
+	ctx, span := startRepositorySpan(ctx, "AddRoleToPlayer", attribute.String("player.id", playerId.String()), attribute.String("role.id", roleId))
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.AddRoleToPlayer(ctx, playerId, roleId)
+}
+
+func (r *tracedRepository) RemoveRoleFromPlayer(ctx context.Context, playerId uuid.UUID, roleId string) (err error) {
+	ctx, span := startRepositorySpan(ctx, "RemoveRoleFromPlayer", attribute.String("player.id", playerId.String()), attribute.String("role.id", roleId))
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.RemoveRoleFromPlayer(ctx, playerId, roleId)
+}
+
+func (r *tracedRepository) AddRoleToPlayers(ctx context.Context, playerIds []uuid.UUID, roleId string) (changed []uuid.UUID, failed []uuid.UUID, err error) {
+	ctx, span := startRepositorySpan(ctx, "AddRoleToPlayers", attribute.String("role.id", roleId), attribute.Int("player.count", len(playerIds)))
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.AddRoleToPlayers(ctx, playerIds, roleId)
+}
+
+func (r *tracedRepository) RemoveRoleFromPlayers(ctx context.Context, playerIds []uuid.UUID, roleId string) (changed []uuid.UUID, failed []uuid.UUID, err error) {
+	ctx, span := startRepositorySpan(ctx, "RemoveRoleFromPlayers", attribute.String("role.id", roleId), attribute.Int("player.count", len(playerIds)))
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.RemoveRoleFromPlayers(ctx, playerIds, roleId)
+}
+
+func (r *tracedRepository) GetSubjectGrants(ctx context.Context, subject model.Subject) (grants *model.SubjectGrants, err error) {
+	ctx, span := startRepositorySpan(ctx, "GetSubjectGrants", attribute.String("subject", subject.String()))
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.GetSubjectGrants(ctx, subject)
+}
+
+func (r *tracedRepository) GetAllServiceAccounts(ctx context.Context) (accounts []*model.ServiceAccount, err error) {
+	ctx, span := startRepositorySpan(ctx, "GetAllServiceAccounts")
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.GetAllServiceAccounts(ctx)
+}
+
+func (r *tracedRepository) GetServiceAccount(ctx context.Context, id string) (account *model.ServiceAccount, err error) {
+	ctx, span := startRepositorySpan(ctx, "GetServiceAccount", attribute.String("service_account.id", id))
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.GetServiceAccount(ctx, id)
+}
+
+func (r *tracedRepository) GetServiceAccountByTokenHash(ctx context.Context, tokenHash string) (account *model.ServiceAccount, err error) {
+	// The token hash is deliberately not recorded
+	ctx, span := startRepositorySpan(ctx, "GetServiceAccountByTokenHash")
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.GetServiceAccountByTokenHash(ctx, tokenHash)
+}
+
+func (r *tracedRepository) CreateServiceAccount(ctx context.Context, account *model.ServiceAccount) (err error) {
+	ctx, span := startRepositorySpan(ctx, "CreateServiceAccount", attribute.String("service_account.id", account.Id))
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.CreateServiceAccount(ctx, account)
+}
+
+func (r *tracedRepository) UpdateServiceAccount(ctx context.Context, account *model.ServiceAccount) (err error) {
+	ctx, span := startRepositorySpan(ctx, "UpdateServiceAccount", attribute.String("service_account.id", account.Id))
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.UpdateServiceAccount(ctx, account)
+}
+
+func (r *tracedRepository) DeleteServiceAccount(ctx context.Context, id string) (err error) {
+	ctx, span := startRepositorySpan(ctx, "DeleteServiceAccount", attribute.String("service_account.id", id))
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.DeleteServiceAccount(ctx, id)
+}
+
+func (r *tracedRepository) AddRoleToServiceAccount(ctx context.Context, id string, roleId string) (err error) {
+	ctx, span := startRepositorySpan(ctx, "AddRoleToServiceAccount", attribute.String("service_account.id", id), attribute.String("role.id", roleId))
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.AddRoleToServiceAccount(ctx, id, roleId)
+}
+
+func (r *tracedRepository) RemoveRoleFromServiceAccount(ctx context.Context, id string, roleId string) (err error) {
+	ctx, span := startRepositorySpan(ctx, "RemoveRoleFromServiceAccount", attribute.String("service_account.id", id), attribute.String("role.id", roleId))
+	defer func() { endRepositorySpan(span, err) }()
+	return r.repo.RemoveRoleFromServiceAccount(ctx, id, roleId)
+}
@@ -0,0 +1,97 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"permission-service/internal/repository"
+	"permission-service/internal/repository/model"
+	"testing"
+)
+
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	return recorder
+}
+
+func TestKafkaHeaders(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	ctx, span := StartKafkaPublish(context.Background(), "permission-manager", "emortal.message.permission.RoleUpdateMessage")
+	headers := InjectKafkaHeaders(ctx, []kafka.Header{{Key: "X-Proto-Type", Value: []byte("emortal.message.permission.RoleUpdateMessage")}})
+	span.End()
+
+	require.Len(t, headers, 2)
+	assert.Equal(t, "X-Proto-Type", headers[0].Key)
+	assert.Equal(t, "traceparent", headers[1].Key)
+
+	// Injecting again replaces the existing header rather than adding another
+	assert.Len(t, InjectKafkaHeaders(ctx, headers), 2)
+
+	_, receiveSpan := StartKafkaReceive(context.Background(), &kafka.Message{Topic: "permission-manager", Headers: headers}, "emortal.message.permission.RoleUpdateMessage")
+	receiveSpan.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, trace.SpanKindProducer, spans[0].SpanKind())
+	assert.Equal(t, trace.SpanKindConsumer, spans[1].SpanKind())
+	assert.Equal(t, spans[0].SpanContext().TraceID(), spans[1].SpanContext().TraceID())
+	assert.Equal(t, spans[0].SpanContext().SpanID(), spans[1].Parent().SpanID())
+}
+
+func TestTraceRepository(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	mockCntrl := gomock.NewController(t)
+	mockRepo := repository.NewMockRepository(mockCntrl)
+
+	parentCtx, parent := Tracer().Start(context.Background(), "GetRole")
+	mockRepo.EXPECT().GetRole(gomock.Any(), "vip").DoAndReturn(func(ctx context.Context, _ string) (*model.Role, error) {
+		// The repository receives the context of its own span
+		assert.NotEqual(t, parent.SpanContext().SpanID(), trace.SpanContextFromContext(ctx).SpanID())
+		return &model.Role{Id: "vip"}, nil
+	})
+	mockRepo.EXPECT().GetRole(gomock.Any(), "missing").Return(nil, mongo.ErrNoDocuments)
+	mockRepo.EXPECT().GetRole(gomock.Any(), "broken").Return(nil, errors.New("connection reset"))
+
+	repo := TraceRepository(mockRepo)
+
+	_, err := repo.GetRole(parentCtx, "vip")
+	assert.NoError(t, err)
+	_, err = repo.GetRole(parentCtx, "missing")
+	assert.Equal(t, mongo.ErrNoDocuments, err)
+	_, err = repo.GetRole(parentCtx, "broken")
+	assert.Error(t, err)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+
+	for _, span := range spans[:3] {
+		assert.Equal(t, "repository.GetRole", span.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+	}
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+}
@@ -0,0 +1,82 @@
+package filewatch
+
+import (
+	"context"
+	"fmt"
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// debounce waits for changes to settle, as editors and ConfigMap updates write several events at once
+const debounce = time.Second
+
+// Watch returns a channel that receives once changes to path, a file or directory, have settled.
+// Watching stops when ctx is done.
+func Watch(ctx context.Context, wg *sync.WaitGroup, logger *zap.SugaredLogger, path string) (<-chan struct{}, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	// A file's directory is watched rather than the file itself, so it's still watched after being replaced
+	watchPath := path
+	if !info.IsDir() {
+		watchPath = filepath.Dir(path)
+	}
+	if err := watcher.Add(watchPath); err != nil {
+		_ = watcher.Close()
+		return nil, fmt.Errorf("failed to watch %s: %w", watchPath, err)
+	}
+
+	changes := make(chan struct{}, 1)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer watcher.Close()
+
+		timer := time.NewTimer(debounce)
+		timer.Stop()
+		defer timer.Stop()
+
+		events, errs := watcher.Events, watcher.Errors
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+				// A change is already pending if the channel is full
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			case event, ok := <-events:
+				if !ok {
+					events = nil
+					continue
+				}
+				// Any change in the directory may be to path, as ConfigMaps replace files through a symlink of another name
+				if !event.Has(fsnotify.Chmod) {
+					timer.Reset(debounce)
+				}
+			case err, ok := <-errs:
+				if !ok {
+					errs = nil
+					continue
+				}
+				logger.Warnw("file watcher error", "path", path, "error", err)
+			}
+		}
+	}()
+
+	return changes, nil
+}
@@ -0,0 +1,48 @@
+package filewatch
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("port: 10010"), 0o644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	defer cancel()
+
+	changes, err := Watch(ctx, wg, zap.NewNop().Sugar(), path)
+	require.NoError(t, err)
+
+	// Replaced the way editors save, which would stop a watch on the file itself
+	tmp := path + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, []byte("port: 10011"), 0o644))
+	require.NoError(t, os.Rename(tmp, path))
+
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("change not reported")
+	}
+
+	// Several writes settle into one change
+	select {
+	case <-changes:
+		t.Fatal("change reported twice")
+	case <-time.After(debounce + 500*time.Millisecond):
+	}
+}
+
+func TestWatch_MissingPath(t *testing.T) {
+	_, err := Watch(context.Background(), &sync.WaitGroup{}, zap.NewNop().Sugar(), filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
@@ -0,0 +1,133 @@
+package validation
+
+import (
+	"fmt"
+	"github.com/emortalmc/proto-specs/gen/go/grpc/permission"
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"permission-service/api/permissionapi"
+)
+
+func GetPlayerRolesRequest(req *permission.GetPlayerRolesRequest) error {
+	v := &Violations{}
+	v.PlayerId("player_id", req.PlayerId)
+	return v.Err()
+}
+
+func RoleCreateRequest(req *permission.RoleCreateRequest) error {
+	v := &Violations{}
+	v.Id("id", req.Id)
+	v.Priority("priority", req.Priority)
+	if req.DisplayName != nil {
+		v.DisplayName("display_name", *req.DisplayName)
+	}
+	return v.Err()
+}
+
+func RoleUpdateRequest(req *permission.RoleUpdateRequest) error {
+	v := &Violations{}
+	v.ExistingId("id", req.Id)
+	if req.Priority != nil {
+		v.Priority("priority", *req.Priority)
+	}
+	if req.DisplayName != nil {
+		v.DisplayName("display_name", *req.DisplayName)
+	}
+	permissionChanges(v, req.SetPermissions, req.UnsetPermissions)
+	return v.Err()
+}
+
+func AddRoleToPlayerRequest(req *permission.AddRoleToPlayerRequest) error {
+	v := &Violations{}
+	v.PlayerId("player_id", req.PlayerId)
+	v.ExistingId("role_id", req.RoleId)
+	return v.Err()
+}
+
+func RemoveRoleFromPlayerRequest(req *permission.RemoveRoleFromPlayerRequest) error {
+	v := &Violations{}
+	v.PlayerId("player_id", req.PlayerId)
+	v.ExistingId("role_id", req.RoleId)
+	return v.Err()
+}
+
+func DeleteRoleRequest(req *permissionapi.DeleteRoleRequest) error {
+	v := &Violations{}
+	v.ExistingId("id", req.Id)
+	return v.Err()
+}
+
+func CreateServiceAccountRequest(req *permissionapi.CreateServiceAccountRequest) error {
+	v := &Violations{}
+	v.Id("id", req.Id)
+	return v.Err()
+}
+
+func ServiceAccountRoleRequest(id string, roleId string) error {
+	v := &Violations{}
+	v.ExistingId("id", id)
+	v.ExistingId("role_id", roleId)
+	return v.Err()
+}
+
+func UpdateServiceAccountPermissionsRequest(req *permissionapi.UpdateServiceAccountPermissionsRequest) error {
+	v := &Violations{}
+	v.ExistingId("id", req.Id)
+	permissionChanges(v, req.SetPermissions, req.UnsetPermissions)
+	return v.Err()
+}
+
+func WatchPlayerPermissionsRequest(req *permissionapi.WatchPlayerPermissionsRequest) error {
+	v := &Violations{}
+	if len(req.PlayerIds) == 0 && !req.AllRoles {
+		v.Add("player_ids", "must not be empty unless all_roles is set")
+	}
+	if len(req.PlayerIds) > MaxWatchedPlayers {
+		v.Add("player_ids", fmt.Sprintf("must contain at most %d players", MaxWatchedPlayers))
+	}
+	for i, playerId := range req.PlayerIds {
+		v.PlayerId(fmt.Sprintf("player_ids[%d]", i), playerId)
+	}
+	return v.Err()
+}
+
+// BulkUpdatePlayerRolesRequest doesn't check the player ids, as invalid ones are reported per player instead of
+// failing the whole request.
+func BulkUpdatePlayerRolesRequest(req *permissionapi.BulkUpdatePlayerRolesRequest) error {
+	v := &Violations{}
+	v.ExistingId("role_id", req.RoleId)
+	if _, ok := permissionapi.BulkUpdatePlayerRolesRequest_ChangeType_name[int32(req.ChangeType)]; !ok {
+		v.Add("change_type", "must be ADD or REMOVE")
+	}
+	switch {
+	case len(req.PlayerIds) == 0:
+		v.Add("player_ids", "must not be empty")
+	case len(req.PlayerIds) > MaxBulkPlayers:
+		v.Add("player_ids", fmt.Sprintf("must contain at most %d players", MaxBulkPlayers))
+	}
+	return v.Err()
+}
+
+func permissionChanges(v *Violations, set []*protoModel.PermissionNode, unset []string) {
+	setNodes := make(map[string]struct{}, len(set))
+	for i, node := range set {
+		field := fmt.Sprintf("set_permissions[%d]", i)
+		v.PermissionNode(field, node)
+		if node == nil {
+			continue
+		}
+
+		if _, ok := setNodes[node.Node]; ok {
+			v.Add(field+".node", "is set more than once")
+		}
+		setNodes[node.Node] = struct{}{}
+	}
+
+	for i, node := range unset {
+		field := fmt.Sprintf("unset_permissions[%d]", i)
+		v.ExistingNode(field, node)
+
+		if _, ok := setNodes[node]; ok {
+			v.Add(field, "is both set and unset")
+		}
+	}
+}
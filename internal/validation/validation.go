@@ -0,0 +1,135 @@
+package validation
+
+import (
+	"fmt"
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"regexp"
+	"unicode/utf8"
+)
+
+const (
+	MaxIdLength          = 32
+	MaxNodeLength        = 128
+	MaxPriority          = 1_000_000
+	MaxDisplayNameLength = 256
+
+	MaxWatchedPlayers = 1000
+	MaxBulkPlayers    = 10_000
+)
+
+var (
+	// idPattern is used for role and service account ids: lowercase, digits, '-' and '_', not starting with a separator
+	idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	// nodePattern matches dot separated segments, optionally ending in a wildcard ("command.gamemode", "command.*", "*")
+	nodePattern = regexp.MustCompile(`^(\*|[a-zA-Z0-9_-]+(\.[a-zA-Z0-9_-]+)*(\.\*)?)$`)
+)
+
+// Violations collects invalid fields of a request and converts them to an InvalidArgument
+// status with BadRequest details.
+type Violations struct {
+	violations []*errdetails.BadRequest_FieldViolation
+}
+
+func (v *Violations) Add(field string, description string) {
+	v.violations = append(v.violations, &errdetails.BadRequest_FieldViolation{Field: field, Description: description})
+}
+
+// Each calls fn with every violation, in the order they were added.
+func (v *Violations) Each(fn func(field string, description string)) {
+	for _, violation := range v.violations {
+		fn(violation.Field, violation.Description)
+	}
+}
+
+// Err returns nil if there are no violations.
+func (v *Violations) Err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+
+	msg := fmt.Sprintf("invalid %s: %s", v.violations[0].Field, v.violations[0].Description)
+	if len(v.violations) > 1 {
+		msg = fmt.Sprintf("%s (and %d more)", msg, len(v.violations)-1)
+	}
+
+	st, err := status.New(codes.InvalidArgument, msg).WithDetails(&errdetails.BadRequest{FieldViolations: v.violations})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, msg)
+	}
+
+	return st.Err()
+}
+
+func (v *Violations) Id(field string, id string) {
+	switch {
+	case id == "":
+		v.Add(field, "must not be empty")
+	case len(id) > MaxIdLength:
+		v.Add(field, fmt.Sprintf("must be at most %d characters", MaxIdLength))
+	case !idPattern.MatchString(id):
+		v.Add(field, "must only contain lowercase letters, digits, '-' and '_', and start with a letter or digit")
+	}
+}
+
+// ExistingId checks an id that refers to a role or service account rather than naming a new one. Only Id's strict
+// rules apply to new ids, so ones created before them can still be referenced.
+func (v *Violations) ExistingId(field string, id string) {
+	if id == "" {
+		v.Add(field, "must not be empty")
+	}
+}
+
+func (v *Violations) PlayerId(field string, playerId string) {
+	if _, err := uuid.Parse(playerId); err != nil {
+		v.Add(field, "must be a UUID")
+	}
+}
+
+func (v *Violations) Node(field string, node string) {
+	switch {
+	case node == "":
+		v.Add(field, "must not be empty")
+	case len(node) > MaxNodeLength:
+		v.Add(field, fmt.Sprintf("must be at most %d characters", MaxNodeLength))
+	case !nodePattern.MatchString(node):
+		v.Add(field, "must be dot separated segments of letters, digits, '-' and '_', optionally ending in '.*'")
+	}
+}
+
+// ExistingNode checks a node that is being unset, which may predate Node's rules.
+func (v *Violations) ExistingNode(field string, node string) {
+	if node == "" {
+		v.Add(field, "must not be empty")
+	}
+}
+
+func (v *Violations) PermissionNode(field string, node *protoModel.PermissionNode) {
+	if node == nil {
+		v.Add(field, "must not be null")
+		return
+	}
+
+	v.Node(field+".node", node.Node)
+	if _, ok := protoModel.PermissionNode_PermissionState_name[int32(node.State)]; !ok {
+		v.Add(field+".state", "must be ALLOW or DENY")
+	}
+}
+
+func (v *Violations) Priority(field string, priority uint32) {
+	if priority > MaxPriority {
+		v.Add(field, fmt.Sprintf("must be at most %d", MaxPriority))
+	}
+}
+
+func (v *Violations) DisplayName(field string, displayName string) {
+	switch {
+	case displayName == "":
+		v.Add(field, "must not be empty")
+	case utf8.RuneCountInString(displayName) > MaxDisplayNameLength:
+		v.Add(field, fmt.Sprintf("must be at most %d characters", MaxDisplayNameLength))
+	}
+}
@@ -0,0 +1,189 @@
+package validation
+
+import (
+	"github.com/emortalmc/proto-specs/gen/go/grpc/permission"
+	protoModel "github.com/emortalmc/proto-specs/gen/go/model/permission"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"permission-service/api/permissionapi"
+	"permission-service/internal/utils"
+	"strings"
+	"testing"
+)
+
+func TestRoleCreateRequest(t *testing.T) {
+	tests := []struct {
+		name string
+
+		req *permission.RoleCreateRequest
+
+		wantFields []string
+	}{
+		{
+			name: "valid",
+			req:  &permission.RoleCreateRequest{Id: "mod-2", Priority: 50, DisplayName: utils.PointerOf("<red>{{.Username}}")},
+		},
+		{
+			name:       "empty id",
+			req:        &permission.RoleCreateRequest{Id: ""},
+			wantFields: []string{"id"},
+		},
+		{
+			name:       "uppercase id",
+			req:        &permission.RoleCreateRequest{Id: "Admin"},
+			wantFields: []string{"id"},
+		},
+		{
+			name:       "id with spaces",
+			req:        &permission.RoleCreateRequest{Id: "senior mod"},
+			wantFields: []string{"id"},
+		},
+		{
+			name:       "id too long",
+			req:        &permission.RoleCreateRequest{Id: strings.Repeat("a", MaxIdLength+1)},
+			wantFields: []string{"id"},
+		},
+		{
+			name:       "every field invalid",
+			req:        &permission.RoleCreateRequest{Id: "-admin", Priority: MaxPriority + 1, DisplayName: utils.PointerOf("")},
+			wantFields: []string{"id", "priority", "display_name"},
+		},
+		{
+			name:       "display name too long",
+			req:        &permission.RoleCreateRequest{Id: "admin", DisplayName: utils.PointerOf(strings.Repeat("é", MaxDisplayNameLength+1))},
+			wantFields: []string{"display_name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertViolations(t, tt.wantFields, RoleCreateRequest(tt.req))
+		})
+	}
+}
+
+func TestRoleUpdateRequest(t *testing.T) {
+	tests := []struct {
+		name string
+
+		req *permission.RoleUpdateRequest
+
+		wantFields []string
+	}{
+		{
+			name: "valid",
+			req: &permission.RoleUpdateRequest{
+				Id:               "admin",
+				SetPermissions:   []*protoModel.PermissionNode{{Node: "command.gamemode"}, {Node: "command.*"}, {Node: "*"}},
+				UnsetPermissions: []string{"command.fly"},
+			},
+		},
+		{
+			name: "invalid nodes",
+			req: &permission.RoleUpdateRequest{
+				Id:               "admin",
+				SetPermissions:   []*protoModel.PermissionNode{{Node: "command gamemode"}, {Node: "command..fly"}, nil},
+				UnsetPermissions: []string{""},
+			},
+			wantFields: []string{"set_permissions[0].node", "set_permissions[1].node", "set_permissions[2]", "unset_permissions[0]"},
+		},
+		{
+			name: "legacy id and node can be unset",
+			req: &permission.RoleUpdateRequest{
+				Id:               "Legacy Role",
+				UnsetPermissions: []string{"*.command"},
+			},
+		},
+		{
+			name: "unknown state",
+			req: &permission.RoleUpdateRequest{
+				Id:             "admin",
+				SetPermissions: []*protoModel.PermissionNode{{Node: "command.fly", State: 5}},
+			},
+			wantFields: []string{"set_permissions[0].state"},
+		},
+		{
+			name: "conflicting changes",
+			req: &permission.RoleUpdateRequest{
+				Id:               "admin",
+				SetPermissions:   []*protoModel.PermissionNode{{Node: "command.fly"}, {Node: "command.fly"}},
+				UnsetPermissions: []string{"command.fly"},
+			},
+			wantFields: []string{"set_permissions[1].node", "unset_permissions[0]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertViolations(t, tt.wantFields, RoleUpdateRequest(tt.req))
+		})
+	}
+}
+
+func TestAddRoleToPlayerRequest(t *testing.T) {
+	assert.NoError(t, AddRoleToPlayerRequest(&permission.AddRoleToPlayerRequest{PlayerId: uuid.NewString(), RoleId: "admin"}))
+	assertViolations(t, []string{"player_id", "role_id"}, AddRoleToPlayerRequest(&permission.AddRoleToPlayerRequest{PlayerId: "notch", RoleId: ""}))
+}
+
+func TestExistingIdRequests(t *testing.T) {
+	// Ids created before the id rules can still be referenced, so they can be removed
+	assert.NoError(t, RemoveRoleFromPlayerRequest(&permission.RemoveRoleFromPlayerRequest{PlayerId: uuid.NewString(), RoleId: "Legacy.Role"}))
+	assert.NoError(t, DeleteRoleRequest(&permissionapi.DeleteRoleRequest{Id: "Legacy.Role"}))
+	assert.NoError(t, ServiceAccountRoleRequest("Legacy.Bot", "Legacy.Role"))
+
+	assertViolations(t, []string{"id"}, DeleteRoleRequest(&permissionapi.DeleteRoleRequest{}))
+	assertViolations(t, []string{"id"}, CreateServiceAccountRequest(&permissionapi.CreateServiceAccountRequest{Id: "Legacy.Bot"}))
+}
+
+func TestWatchPlayerPermissionsRequest(t *testing.T) {
+	assert.NoError(t, WatchPlayerPermissionsRequest(&permissionapi.WatchPlayerPermissionsRequest{PlayerIds: []string{uuid.NewString()}}))
+	assert.NoError(t, WatchPlayerPermissionsRequest(&permissionapi.WatchPlayerPermissionsRequest{AllRoles: true}))
+
+	assertViolations(t, []string{"player_ids"}, WatchPlayerPermissionsRequest(&permissionapi.WatchPlayerPermissionsRequest{}))
+	assertViolations(t, []string{"player_ids[1]"}, WatchPlayerPermissionsRequest(&permissionapi.WatchPlayerPermissionsRequest{
+		PlayerIds: []string{uuid.NewString(), "notch"},
+	}))
+
+	tooMany := make([]string, MaxWatchedPlayers+1)
+	for i := range tooMany {
+		tooMany[i] = uuid.NewString()
+	}
+	assertViolations(t, []string{"player_ids"}, WatchPlayerPermissionsRequest(&permissionapi.WatchPlayerPermissionsRequest{PlayerIds: tooMany}))
+}
+
+func TestBulkUpdatePlayerRolesRequest(t *testing.T) {
+	assert.NoError(t, BulkUpdatePlayerRolesRequest(&permissionapi.BulkUpdatePlayerRolesRequest{
+		RoleId: "vip", ChangeType: permissionapi.BulkUpdatePlayerRolesRequest_REMOVE, PlayerIds: []string{"notch"},
+	}))
+
+	assertViolations(t, []string{"role_id", "change_type", "player_ids"}, BulkUpdatePlayerRolesRequest(&permissionapi.BulkUpdatePlayerRolesRequest{
+		ChangeType: 5,
+	}))
+	assertViolations(t, []string{"player_ids"}, BulkUpdatePlayerRolesRequest(&permissionapi.BulkUpdatePlayerRolesRequest{
+		RoleId: "vip", PlayerIds: make([]string, MaxBulkPlayers+1),
+	}))
+}
+
+func assertViolations(t *testing.T, wantFields []string, err error) {
+	if len(wantFields) == 0 {
+		assert.NoError(t, err)
+		return
+	}
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	if !assert.Len(t, st.Details(), 1) {
+		return
+	}
+
+	badRequest := st.Details()[0].(*errdetails.BadRequest)
+	fields := make([]string, len(badRequest.FieldViolations))
+	for i, violation := range badRequest.FieldViolations {
+		fields[i] = violation.Field
+		assert.NotEmpty(t, violation.Description)
+	}
+	assert.Equal(t, wantFields, fields)
+}